@@ -0,0 +1,157 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrForbidden is returned or reported when the subject is not allowed to
+// dispatch or subscribe to the event
+var ErrForbidden = errors.New("eventdispatcher: forbidden")
+
+// Authorizer decides what the subjects, like plugins, scripts or remote
+// clients, may do with the dispatcher
+type Authorizer interface {
+
+	// CanSubscribe informs whether the subject may register and remove the
+	// listeners for the event name n
+	CanSubscribe(subject string, n string) bool
+
+	// CanDispatch informs whether the subject may dispatch the event
+	CanDispatch(subject string, e Event) bool
+}
+
+// NamespacePolicy is the Authorizer restricting each subject to its
+// namespaces, so the subject allowed the "billing" namespace may use
+// "billing" and "billing.invoice.paid", but not "shipping.sent". The
+// subjects not in the policy are not allowed anything.
+type NamespacePolicy map[string][]string
+
+// CanSubscribe informs whether the name n is within the namespaces of the
+// subject
+func (p NamespacePolicy) CanSubscribe(subject string, n string) bool {
+	for _, ns := range p[subject] {
+		if n == ns || strings.HasPrefix(n, ns+NamespaceSeparator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanDispatch informs whether the event name is within the namespaces of
+// the subject
+func (p NamespacePolicy) CanDispatch(subject string, e Event) bool {
+	return p.CanSubscribe(subject, e.Name())
+}
+
+// WithAuthorizer makes the views returned by As check what their subjects
+// may do with the Authorizer a. Without it the subjects may do anything.
+func WithAuthorizer(a Authorizer) Option {
+	return func(d *EventDispatcher) {
+		d.authorizer = a
+	}
+}
+
+// SubjectView is a Dispatcher view acting on behalf of the subject,
+// restricted by the Authorizer of the dispatcher. The forbidden
+// subscriptions are skipped and reported with ReportError, as there is no
+// other way to surface them, the forbidden events are dropped.
+type SubjectView struct {
+	d       *EventDispatcher
+	subject string
+}
+
+// As returns the view of the dispatcher acting on behalf of the subject
+func (d *EventDispatcher) As(subject string) *SubjectView {
+	return &SubjectView{d, subject}
+}
+
+// Subject returns the subject the view acts on behalf of
+func (v *SubjectView) Subject() string {
+	return v.subject
+}
+
+// Dispatch dispatches the event if the subject may dispatch it and returns
+// it after all listeners do their jobs
+func (v *SubjectView) Dispatch(e Event) Event {
+	e, _ = v.TryDispatch(e)
+
+	return e
+}
+
+// TryDispatch dispatches the event like Dispatch does, but returns
+// ErrForbidden if the subject may not dispatch it, along with the errors
+// returned by the TryDispatch of the dispatcher
+func (v *SubjectView) TryDispatch(e Event) (Event, error) {
+	if v.d.authorizer != nil && v.d.authorizer.CanDispatch(v.subject, e) == false {
+		return e, fmt.Errorf("%w: %s may not dispatch %s", ErrForbidden, v.subject, e.Name())
+	}
+
+	return v.d.TryDispatch(e)
+}
+
+// On registers a listener for given event names the subject may subscribe
+// to
+func (v *SubjectView) On(n string, l Listener) {
+	if names := v.allowed(n); names != "" {
+		v.d.On(names, l)
+	}
+}
+
+// Once registers a listener to be executed only once for given event names
+// the subject may subscribe to
+func (v *SubjectView) Once(n string, l Listener) {
+	if names := v.allowed(n); names != "" {
+		v.d.Once(names, l)
+	}
+}
+
+// Off removes all registrations of the event listener for given event name
+// if the subject may subscribe to it. Returns the number of listeners
+// removed.
+func (v *SubjectView) Off(n string, l Listener) int {
+	if v.allowed(n) == "" {
+		return 0
+	}
+
+	return v.d.Off(n, l)
+}
+
+// OffAll removes all listeners for given name if the subject may subscribe
+// to it
+func (v *SubjectView) OffAll(n string) {
+	if v.allowed(n) != "" {
+		v.d.OffAll(n)
+	}
+}
+
+// HasListeners returns true if any listener for given event name has been
+// assigned and false otherwise
+func (v *SubjectView) HasListeners(n string) bool {
+	return v.d.HasListeners(n)
+}
+
+// allowed returns the space separated names of n the subject may subscribe
+// to, reporting the forbidden ones
+func (v *SubjectView) allowed(n string) string {
+	names := getNames(n)
+	if v.d.authorizer == nil {
+		return strings.Join(names, " ")
+	}
+
+	var allowed []string
+	for _, name := range names {
+		name = normalize(v.d, name)
+		if v.d.authorizer.CanSubscribe(v.subject, name) {
+			allowed = append(allowed, name)
+			continue
+		}
+		v.d.ReportError(NewParamsEvent(name), fmt.Errorf("%w: %s may not subscribe to %s", ErrForbidden, v.subject, name))
+	}
+
+	return strings.Join(allowed, " ")
+}
@@ -0,0 +1,43 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSubjectView(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithAuthorizer(NamespacePolicy{"plugin": {"billing"}}))
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	var v Dispatcher = d.As("plugin")
+	var c int
+	v.On("billing.paid shipping.sent", func(e Event) {
+		c++
+	})
+	assert.True(d.HasListeners("billing.paid"), "The allowed subscription should be registered!")
+	assert.False(d.HasListeners("shipping.sent"), "The forbidden subscription should be skipped!")
+	assert.Len(errs, 1, "The forbidden subscription should be reported!")
+	assert.ErrorIs(errs[0], ErrForbidden, "Invalid error reported!")
+
+	v.Dispatch(NewParamsEvent("billing.paid"))
+	_, err := d.As("plugin").TryDispatch(NewParamsEvent("shipping.sent"))
+	assert.ErrorIs(err, ErrForbidden, "The forbidden dispatch should be rejected!")
+	_, err = d.As("unknown").TryDispatch(NewParamsEvent("billing.paid"))
+	assert.ErrorIs(err, ErrForbidden, "The unknown subject should not be allowed anything!")
+	assert.Equal(1, c, "Only the allowed event should be dispatched!")
+
+	d.On("shipping.sent", func(e Event) {})
+	v.OffAll("shipping.sent")
+	assert.True(d.HasListeners("shipping.sent"), "The subject should not remove forbidden listeners!")
+}
+
+func TestSubjectViewWithoutAuthorizer(t *testing.T) {
+	d := NewDispatcher()
+	d.As("anyone").On(TestEventName, func(e Event) {})
+	assert.True(t, d.HasListeners(TestEventName), "Subjects should be allowed anything without the authorizer!")
+}
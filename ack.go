@@ -0,0 +1,104 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"runtime/debug"
+	"time"
+)
+
+// Ack is the acknowledgement returned by the AckListener
+type Ack int
+
+const (
+	// Acked confirms the event has been handled
+	Acked Ack = iota
+
+	// Nacked asks for the redelivery of the event after the timeout
+	Nacked
+
+	// Rejected dead letters the event at once, without the redelivery
+	Rejected
+)
+
+// AckListener is the listener acknowledging the handled events, like the
+// consumer of the message broker does
+type AckListener func(Event) Ack
+
+// AckPolicy defines the redelivery of the events not acknowledged by the
+// AckListener
+type AckPolicy struct {
+
+	// MaxAttempts is the max number of deliveries, including the first one
+	MaxAttempts int
+
+	// Timeout is the delay before the redelivery of the unacked event
+	Timeout time.Duration
+}
+
+// DefaultAckPolicy is used by OnAck when no policy is given
+var DefaultAckPolicy = AckPolicy{
+	MaxAttempts: 5,
+	Timeout:     time.Second,
+}
+
+var (
+	// ErrNotAcked is the error of the dead letter not acknowledged within
+	// the max attempts
+	ErrNotAcked = errors.New("eventdispatcher: event not acknowledged")
+
+	// ErrRejected is the error of the dead letter rejected by the listener
+	ErrRejected = errors.New("eventdispatcher: event rejected")
+)
+
+// OnAck registers the acknowledging listener for given event name. The
+// first delivery is synchronous. The events the listener nacks or panics
+// on are redelivered in the background after the timeout of the policy p,
+// or DefaultAckPolicy if p is nil, giving the at-least-once delivery. The
+// events rejected or still unacked after the max attempts are put to the
+// dead letter queue. The same event instance is passed to all deliveries.
+func (d *EventDispatcher) OnAck(n string, l AckListener, p *AckPolicy) {
+	ap := DefaultAckPolicy
+	if p != nil {
+		ap = *p
+	}
+	for _, name := range getNames(n) {
+		name := name
+		on(d, name, func(e Event) {
+			deliver(d, name, e, l, ap, 1)
+		})
+	}
+}
+
+// deliver delivers the event to the listener l for the a-th time and
+// schedules the redelivery unless it is acked
+func deliver(d *EventDispatcher, n string, e Event, l AckListener, p AckPolicy, a int) {
+	switch callAck(d, l, e) {
+	case Acked:
+		return
+	case Rejected:
+		deadLetter(d, DeadLetter{n, e, ErrRejected, a, d.clock.Now()})
+		return
+	}
+	if a >= p.MaxAttempts {
+		deadLetter(d, DeadLetter{n, e, ErrNotAcked, a, d.clock.Now()})
+		return
+	}
+	later(d, p.Timeout, func() {
+		deliver(d, n, e, l, p, a+1)
+	})
+}
+
+// callAck invokes the listener, reporting its panic and treating it as
+// Nacked, as the crashed consumer never acknowledges
+func callAck(d *EventDispatcher, l AckListener, e Event) (ack Ack) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.ReportError(e, &PanicError{r, debug.Stack()})
+			ack = Nacked
+		}
+	}()
+
+	return l(e)
+}
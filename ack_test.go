@@ -0,0 +1,61 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOnAck(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.OnAck(TestEventName, func(e Event) Ack {
+		c++
+		if c == 1 {
+			panic("crashed")
+		}
+		if c < 3 {
+			return Nacked
+		}
+		return Acked
+	}, &AckPolicy{MaxAttempts: 5, Timeout: time.Millisecond})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Drain()
+	assert.Equal(3, c, "The event should be redelivered until acked!")
+	assert.Empty(d.DeadLetters(), "The acked event should not be dead lettered!")
+}
+
+func TestOnAckNotAcked(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode())
+	var c int
+	d.OnAck(TestEventName, func(e Event) Ack {
+		c++
+		return Nacked
+	}, &AckPolicy{MaxAttempts: 2})
+	e := NewParamsEvent(TestEventName)
+	d.Dispatch(e)
+	assert.Equal(2, c, "The event should be delivered max attempts times!")
+	letters := d.TakeDeadLetters()
+	assert.Equal(1, len(letters), "The unacked event should be dead lettered!")
+	assert.Equal(e, letters[0].Event)
+	assert.ErrorIs(letters[0].Err, ErrNotAcked)
+}
+
+func TestOnAckRejected(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode())
+	var c int
+	d.OnAck(TestEventName, func(e Event) Ack {
+		c++
+		return Rejected
+	}, nil)
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The rejected event should not be redelivered!")
+	letters := d.DeadLetters()
+	assert.Equal(1, len(letters), "The rejected event should be dead lettered!")
+	assert.ErrorIs(letters[0].Err, ErrRejected)
+}
@@ -0,0 +1,90 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"slices"
+)
+
+// AliasMode defines in which direction the events are delivered between
+// the aliased names
+type AliasMode int
+
+const (
+	// AliasForward delivers the events dispatched under the old name also
+	// to the listeners of the new name
+	AliasForward AliasMode = iota
+
+	// AliasBoth additionally delivers the events dispatched under the new
+	// name to the listeners of the old name
+	AliasBoth
+)
+
+// Alias makes the events dispatched under oldName also delivered to the
+// listeners of newName and, in the AliasBoth mode, vice versa, easing the
+// incremental renames. Aliases are not transitive.
+func (d *EventDispatcher) Alias(oldName string, newName string, mode AliasMode) {
+	oldName, newName = normalize(d, oldName), normalize(d, newName)
+	updateAliases(d, func(aliases map[string][]string) {
+		addAlias(aliases, oldName, newName)
+		if mode == AliasBoth {
+			addAlias(aliases, newName, oldName)
+		}
+	})
+}
+
+// RemoveAlias removes the alias between the names, in both directions
+func (d *EventDispatcher) RemoveAlias(oldName string, newName string) {
+	oldName, newName = normalize(d, oldName), normalize(d, newName)
+	updateAliases(d, func(aliases map[string][]string) {
+		removeAlias(aliases, oldName, newName)
+		removeAlias(aliases, newName, oldName)
+	})
+}
+
+// aliasesOf returns the names the events dispatched under name n are also
+// delivered to
+func aliasesOf(d *EventDispatcher, n string) []string {
+	aliases := d.aliases.Load()
+	if aliases == nil {
+		return nil
+	}
+
+	return (*aliases)[n]
+}
+
+// updateAliases applies f to the copy of the aliases and stores it, so the
+// dispatches read the aliases without locking
+func updateAliases(d *EventDispatcher, f func(map[string][]string)) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	aliases := make(map[string][]string)
+	if current := d.aliases.Load(); current != nil {
+		for n, names := range *current {
+			aliases[n] = names
+		}
+	}
+	f(aliases)
+	d.aliases.Store(&aliases)
+}
+
+// addAlias adds the alias of name n to the aliases
+func addAlias(aliases map[string][]string, n string, alias string) {
+	if n == alias || slices.Contains(aliases[n], alias) {
+		return
+	}
+	aliases[n] = append(slices.Clip(aliases[n]), alias)
+}
+
+// removeAlias removes the alias of name n from the aliases
+func removeAlias(aliases map[string][]string, n string, alias string) {
+	names := slices.DeleteFunc(slices.Clone(aliases[n]), func(name string) bool {
+		return name == alias
+	})
+	if len(names) == 0 {
+		delete(aliases, n)
+		return
+	}
+	aliases[n] = names
+}
@@ -0,0 +1,48 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var calls []string
+	d.On("user.signed_up", func(e Event) {
+		calls = append(calls, "old "+e.Name())
+	})
+	d.On("user.created", func(e Event) {
+		calls = append(calls, "new "+e.Name())
+	})
+	d.Alias("user.signed_up", "user.created", AliasForward)
+	d.Dispatch(NewParamsEvent("user.signed_up"))
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal([]string{"old user.signed_up", "new user.signed_up", "new user.created"}, calls,
+		"The old name should be delivered to the new listeners only!")
+
+	calls = nil
+	d.Alias("user.signed_up", "user.created", AliasBoth)
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal([]string{"old user.created", "new user.created"}, calls, "The new name should be delivered to the old listeners!")
+	assert.Equal(2, d.ListenerCount("user.signed_up"))
+
+	calls = nil
+	d.RemoveAlias("user.signed_up", "user.created")
+	d.Dispatch(NewParamsEvent("user.signed_up"))
+	assert.Equal([]string{"old user.signed_up"}, calls, "The removed alias should not be used!")
+}
+
+func TestAliasWildcard(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	var c int
+	d.On("account.*", func(e Event) {
+		c++
+	})
+	d.Alias("user.created", "account.created", AliasForward)
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal(1, c, "The aliases should be matched against the patterns once!")
+}
@@ -0,0 +1,70 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// OnAny registers the listener receiving every dispatched event, e.g. for
+// the audit logging or the metrics, without enumerating the event names.
+// The listeners are called after the listeners of the event name, even if
+// the propagation has been stopped, in the order of registration. They are
+// not counted by HasListeners. Returns the subscription removing the
+// listener.
+func (d *EventDispatcher) OnAny(l Listener) *Subscription {
+	if rejectSealed(d, WildcardSegments) {
+		return &Subscription{d: d}
+	}
+	id := nextID(d)
+	updateAnyListeners(d, func(listeners listenersCollection) listenersCollection {
+		return append(listeners, listenerEntry{id: id, l: l})
+	})
+
+	return &Subscription{d: d, stop: func() bool {
+		var removed bool
+		updateAnyListeners(d, func(listeners listenersCollection) listenersCollection {
+			var kept listenersCollection
+			for _, le := range listeners {
+				if le.id == id {
+					removed = true
+					continue
+				}
+				kept = append(kept, le)
+			}
+			return kept
+		})
+		return removed
+	}}
+}
+
+// updateAnyListeners replaces the listeners of all events with the copy
+// changed by f, so the dispatches in flight keep the old ones
+func updateAnyListeners(d *EventDispatcher, f func(listenersCollection) listenersCollection) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	var listeners listenersCollection
+	if p := d.anyListeners.Load(); p != nil {
+		listeners = append(listeners, *p...)
+	}
+	listeners = f(listeners)
+	d.anyListeners.Store(&listeners)
+}
+
+// dispatchAny calls the listeners of all events with the event e
+// dispatched under name n. Returns the number of listeners called.
+func dispatchAny(d *EventDispatcher, n string, e Event, mws []Middleware, rep *DispatchReport) int {
+	p := d.anyListeners.Load()
+	if p == nil {
+		return 0
+	}
+	for _, le := range *p {
+		le := le
+		if rep == nil {
+			invoke(d, mws, ListenerCall{n, le.id}, le.l, e)
+			continue
+		}
+		reportListener(d, rep, le.id, e, func() {
+			invoke(d, mws, ListenerCall{n, le.id}, le.l, e)
+		})
+	}
+
+	return len(*p)
+}
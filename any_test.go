@@ -0,0 +1,39 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOnAny(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	d.On("a", func(e Event) {
+		got = append(got, "listener")
+		e.StopPropagation()
+	})
+	s := d.OnAny(func(e Event) {
+		got = append(got, "any:"+e.Name())
+	})
+	d.Dispatch(NewParamsEvent("a"))
+	d.Dispatch(NewParamsEvent("b"))
+	assert.Equal([]string{"listener", "any:a", "any:b"}, got, "Every event should be received after the listeners of its name!")
+	assert.False(d.HasListeners("b"), "The listeners of all events should not be counted!")
+
+	s.Unsubscribe()
+	d.Dispatch(NewParamsEvent("b"))
+	assert.Len(got, 3, "The removed listener should not be called!")
+}
+
+func TestOnAnyBroadcast(t *testing.T) {
+	d := NewDispatcher()
+	var c int
+	d.OnAny(func(e Event) {
+		c++
+	})
+	assert.Equal(t, 1, d.Broadcast(NewParamsEvent("shutdown")), "The broadcast should reach the listeners of all events!")
+	assert.Equal(t, 1, c)
+}
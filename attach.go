@@ -0,0 +1,81 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"reflect"
+)
+
+// Attach registers the child dispatcher which will receive every event
+// dispatched by this dispatcher, after its own listeners are done. Attaching
+// the same child twice has no effect. The children are told apart by the
+// == operator, the children of the types not comparable, e.g. the structs
+// holding a map, are never the same, so they cannot be detached. Do not
+// create cycles of attached dispatchers.
+func (d *EventDispatcher) Attach(child Dispatcher) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	if sameDispatcher(child, d) || indexOfChild(d, child) != -1 {
+		return
+	}
+	d.children = append(d.children, child)
+}
+
+// Detach removes the child dispatcher previously registered with Attach.
+// Does nothing if the child is not attached.
+func (d *EventDispatcher) Detach(child Dispatcher) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	i := indexOfChild(d, child)
+	if i == -1 {
+		return
+	}
+	children := make([]Dispatcher, 0, len(d.children)-1)
+	children = append(children, d.children[:i]...)
+	d.children = append(children, d.children[i+1:]...)
+}
+
+// indexOfChild returns the position of given child in the attached children
+// slice or -1 if it is not attached
+func indexOfChild(d *EventDispatcher, child Dispatcher) int {
+	for i, c := range d.children {
+		if sameDispatcher(c, child) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sameDispatcher compares the dispatchers with the == operator, without
+// panicking on the types not comparable
+func sameDispatcher(a Dispatcher, b Dispatcher) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() || isComparable(a) == false {
+		return false
+	}
+
+	return va.Equal(vb)
+}
+
+// isComparable informs whether the value v may be compared or be the key of
+// the map without panicking
+func isComparable(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).Comparable()
+}
+
+// forward dispatches the event to all attached children
+func forward(d *EventDispatcher, e Event) {
+	d.RWMutex.RLock()
+	children := d.children
+	d.RWMutex.RUnlock()
+
+	for _, c := range children {
+		c.Dispatch(e)
+	}
+}
@@ -0,0 +1,58 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAttachDetach(t *testing.T) {
+	assert := assert.New(t)
+	parent := NewDispatcher()
+	child := NewDispatcher()
+	var pc, cc int
+	parent.On(TestEventName, func(e Event) {
+		pc++
+	})
+	child.On(TestEventName, func(e Event) {
+		cc++
+	})
+
+	parent.Attach(child)
+	parent.Attach(child)
+	parent.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, pc, "The parent listener should be called!")
+	assert.Equal(1, cc, "The event should be forwarded once to the attached child!")
+
+	child.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, pc, "Child events should not reach the parent!")
+
+	parent.Detach(child)
+	parent.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(2, pc, "The parent listener should be called!")
+	assert.Equal(2, cc, "The detached child should not receive events!")
+}
+
+// mapDispatcher is the dispatcher of the type not comparable
+type mapDispatcher struct {
+	*EventDispatcher
+	m map[string]int
+}
+
+func TestAttachUncomparable(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	child := mapDispatcher{NewDispatcher(), map[string]int{}}
+	var c int
+	child.On(TestEventName, func(e Event) {
+		c++
+	})
+
+	assert.NotPanics(func() {
+		d.Attach(child)
+		d.Detach(child)
+	}, "The uncomparable child should not panic!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The uncomparable child should be attached!")
+}
@@ -0,0 +1,79 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+const (
+	// HighWatermarkEventName is the name of the event dispatched when the
+	// queue depth reaches the high watermark
+	HighWatermarkEventName = "dispatcher.watermark.high"
+
+	// LowWatermarkEventName is the name of the event dispatched when the
+	// queue depth falls back to the low watermark
+	LowWatermarkEventName = "dispatcher.watermark.low"
+
+	// ParamDepth is the param of the watermark events holding the queue
+	// depth
+	ParamDepth = "depth"
+)
+
+// watermarks are the queue depths signaling the backpressure
+type watermarks struct {
+	high  int
+	low   int
+	above bool
+}
+
+// WithWatermarks makes the dispatcher signal the backpressure, dispatching
+// the HighWatermarkEventName event when the queue depth reaches high and
+// the LowWatermarkEventName event when it falls back to low, so the
+// producers may slow down
+func WithWatermarks(high int, low int) Option {
+	return func(d *EventDispatcher) {
+		d.watermarks = &watermarks{high: high, low: low}
+	}
+}
+
+// QueueDepth returns the number of the asynchronous work items pending,
+// either queued or running
+func (d *EventDispatcher) QueueDepth() int {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	return d.pending
+}
+
+// IsBackpressured informs whether the queue depth has reached the high
+// watermark and not fallen back to the low one yet
+func (d *EventDispatcher) IsBackpressured() bool {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	return d.watermarks != nil && d.watermarks.above
+}
+
+// crossWatermark returns the name of the watermark event to dispatch when
+// the queue depth changed, or an empty string. Must be called under the
+// pending lock.
+func crossWatermark(d *EventDispatcher) string {
+	w := d.watermarks
+	if w == nil {
+		return ""
+	}
+	if w.above == false && d.pending >= w.high {
+		w.above = true
+		return HighWatermarkEventName
+	}
+	if w.above && d.pending <= w.low {
+		w.above = false
+		return LowWatermarkEventName
+	}
+
+	return ""
+}
+
+// signalWatermark dispatches the watermark event with name n, if any
+func signalWatermark(d *EventDispatcher, n string, depth int) {
+	if n != "" {
+		d.Dispatch(NewParamsEvent(n).SetParam(ParamDepth, depth))
+	}
+}
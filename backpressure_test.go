@@ -0,0 +1,41 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestWatermarks(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithAsync(1), WithWatermarks(3, 1))
+	release := make(chan struct{})
+	d.On(TestEventName, func(e Event) {
+		<-release
+	})
+	var mu sync.Mutex
+	var signals []string
+	d.On(HighWatermarkEventName+" "+LowWatermarkEventName, func(e Event) {
+		depth, _ := e.(*ParamsEvent).GetParam(ParamDepth)
+		mu.Lock()
+		signals = append(signals, e.Name())
+		mu.Unlock()
+		if e.Name() == HighWatermarkEventName {
+			assert.Equal(3, depth, "The depth should be passed in the event!")
+		}
+	})
+	for i := 0; i < 4; i++ {
+		d.DispatchAsync(NewParamsEvent(TestEventName))
+	}
+	assert.Equal(4, d.QueueDepth(), "The pending work should be counted!")
+	assert.True(d.IsBackpressured(), "The high watermark should be reached!")
+	close(release)
+	d.Drain()
+	assert.Equal(0, d.QueueDepth())
+	assert.False(d.IsBackpressured(), "The low watermark should be reached!")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal([]string{HighWatermarkEventName, LowWatermarkEventName}, signals)
+}
@@ -0,0 +1,99 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+	"time"
+)
+
+// BatchFunc receives the events accumulated by the BatchListener
+type BatchFunc func([]Event)
+
+// BatchListener is an adapter accumulating dispatched events and passing
+// them to the BatchFunc when the batch reaches its max size or when the max
+// latency elapses since the first event of the batch arrived, whichever
+// comes first. Register its Listen method on the dispatcher.
+type BatchListener struct {
+	sync.Mutex
+	f          BatchFunc
+	maxSize    int
+	maxLatency time.Duration
+	events     []Event
+	timer      *time.Timer
+	closed     bool
+}
+
+// Listen is the Listener function adding the event to the current batch
+func (b *BatchListener) Listen(e Event) {
+	b.Lock()
+	if b.closed {
+		b.Unlock()
+		return
+	}
+	b.events = append(b.events, e)
+	if b.maxSize > 0 && len(b.events) >= b.maxSize {
+		events := takeBatch(b)
+		b.Unlock()
+		b.f(events)
+		return
+	}
+	if b.timer == nil && b.maxLatency > 0 {
+		b.timer = time.AfterFunc(b.maxLatency, b.Flush)
+	}
+	b.Unlock()
+}
+
+// Flush passes all accumulated events to the BatchFunc immediately. Does
+// nothing if the batch is empty.
+func (b *BatchListener) Flush() {
+	b.Lock()
+	events := takeBatch(b)
+	b.Unlock()
+
+	if len(events) != 0 {
+		b.f(events)
+	}
+}
+
+// Close flushes pending events and makes the listener ignore all events
+// received afterwards
+func (b *BatchListener) Close() {
+	b.Lock()
+	b.closed = true
+	b.Unlock()
+
+	b.Flush()
+}
+
+// Len returns the number of events waiting in the current batch
+func (b *BatchListener) Len() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return len(b.events)
+}
+
+// takeBatch detaches the current batch and stops the latency timer. Must be
+// called with the lock held.
+func takeBatch(b *BatchListener) []Event {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	events := b.events
+	b.events = nil
+
+	return events
+}
+
+// NewBatchListener creates a new batching adapter. The size param is the
+// max number of events in a batch and latency is the max time an event may
+// wait before being flushed. Zero value of any of them disables the limit.
+func NewBatchListener(size int, latency time.Duration, f BatchFunc) *BatchListener {
+	return &BatchListener{
+		f:          f,
+		maxSize:    size,
+		maxLatency: latency,
+	}
+}
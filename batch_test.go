@@ -0,0 +1,52 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchListenerMaxSize(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var batches [][]Event
+	b := NewBatchListener(3, 0, func(events []Event) {
+		batches = append(batches, events)
+	})
+	d.On(TestEventName, b.Listen)
+	for i := 0; i < 7; i++ {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	}
+	assert.Equal(2, len(batches), fmt.Sprintf("Expected %d full batches!", 2))
+	assert.Equal(3, len(batches[0]), "Invalid batch size!")
+	assert.Equal(1, b.Len(), "One event should wait in the batch!")
+
+	b.Close()
+	assert.Equal(3, len(batches), "Close should flush the pending events!")
+	assert.Equal(1, len(batches[2]), "Invalid flushed batch size!")
+
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(0, b.Len(), "Closed listener should ignore events!")
+}
+
+func TestBatchListenerMaxLatency(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var wg sync.WaitGroup
+	var flushed []Event
+	wg.Add(1)
+	b := NewBatchListener(100, 10*time.Millisecond, func(events []Event) {
+		flushed = events
+		wg.Done()
+	})
+	d.On(TestEventName, b.Listen)
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	wg.Wait()
+	assert.Equal(2, len(flushed), "The batch should be flushed after the max latency!")
+	assert.Equal(0, b.Len(), "No events should wait after the flush!")
+}
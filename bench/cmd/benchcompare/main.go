@@ -0,0 +1,58 @@
+// Command benchcompare compares two `go test -bench` outputs and exits with
+// a non-zero status if any benchmark regressed beyond the threshold.
+//
+// Usage:
+//
+//	benchcompare [-threshold 0.1] old.txt new.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/gacek85/eventdispatcher/bench"
+	"os"
+)
+
+func main() {
+	threshold := flag.Float64("threshold", 0.1, "max allowed relative slowdown per benchmark")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: benchcompare [-threshold 0.1] old.txt new.txt")
+		os.Exit(2)
+	}
+
+	old, err := parseFile(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	new, err := parseFile(flag.Arg(1))
+	if err != nil {
+		fail(err)
+	}
+
+	deltas := bench.Compare(old, new)
+	if err := bench.WriteReport(os.Stdout, deltas); err != nil {
+		fail(err)
+	}
+	regressions := bench.Regressions(deltas, *threshold)
+	if len(regressions) != 0 {
+		fmt.Fprintf(os.Stderr, "%d benchmark(s) regressed:\n", len(regressions))
+		bench.WriteReport(os.Stderr, regressions)
+		os.Exit(1)
+	}
+}
+
+func parseFile(path string) (map[string]bench.Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return bench.Parse(f)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,141 @@
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Result holds the averaged measurements of a single benchmark
+type Result struct {
+	Name        string
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+	runs        int
+}
+
+// Delta describes the change of a benchmark result compared to the baseline
+type Delta struct {
+	Name string
+	Old  Result
+	New  Result
+}
+
+// NsPerOpChange returns the relative change of the time per operation,
+// e.g. 0.1 means the benchmark became 10% slower
+func (d Delta) NsPerOpChange() float64 {
+	return change(d.Old.NsPerOp, d.New.NsPerOp)
+}
+
+// AllocsPerOpChange returns the relative change of the allocations number
+// per operation
+func (d Delta) AllocsPerOpChange() float64 {
+	return change(d.Old.AllocsPerOp, d.New.AllocsPerOp)
+}
+
+func change(o float64, n float64) float64 {
+	if o == 0 {
+		return 0
+	}
+
+	return (n - o) / o
+}
+
+// Parse reads the `go test -bench` output and returns the results by
+// benchmark name. Results of repeated runs (-count) are averaged.
+func Parse(r io.Reader) (map[string]Result, error) {
+	results := make(map[string]Result)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "Benchmark") == false {
+			continue
+		}
+		name := trimProcs(fields[0])
+		res := results[name]
+		res.Name = name
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bench: invalid value %q in line %q", fields[i], s.Text())
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				res.NsPerOp = average(res.NsPerOp, v, res.runs)
+			case "B/op":
+				res.BytesPerOp = average(res.BytesPerOp, v, res.runs)
+			case "allocs/op":
+				res.AllocsPerOp = average(res.AllocsPerOp, v, res.runs)
+			}
+		}
+		res.runs++
+		results[name] = res
+	}
+
+	return results, s.Err()
+}
+
+// trimProcs removes the GOMAXPROCS suffix from the benchmark name
+func trimProcs(n string) string {
+	i := strings.LastIndex(n, "-")
+	if i == -1 {
+		return n
+	}
+	if _, err := strconv.Atoi(n[i+1:]); err != nil {
+		return n
+	}
+
+	return n[:i]
+}
+
+func average(avg float64, v float64, runs int) float64 {
+	return (avg*float64(runs) + v) / float64(runs+1)
+}
+
+// Compare returns the deltas of the benchmarks present in both result sets
+// sorted by benchmark name
+func Compare(old map[string]Result, new map[string]Result) []Delta {
+	var deltas []Delta
+	for name, o := range old {
+		n, ok := new[name]
+		if ok == false {
+			continue
+		}
+		deltas = append(deltas, Delta{name, o, n})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Name < deltas[j].Name
+	})
+
+	return deltas
+}
+
+// Regressions returns the deltas where the time per operation grew by more
+// than given threshold (e.g. 0.1 for 10%) or the allocations number grew
+func Regressions(deltas []Delta, threshold float64) []Delta {
+	var regressions []Delta
+	for _, d := range deltas {
+		if d.NsPerOpChange() > threshold || d.New.AllocsPerOp > d.Old.AllocsPerOp {
+			regressions = append(regressions, d)
+		}
+	}
+
+	return regressions
+}
+
+// WriteReport writes the comparison table of given deltas to w
+func WriteReport(w io.Writer, deltas []Delta) error {
+	for _, d := range deltas {
+		_, err := fmt.Fprintf(w, "%-50s %12.1f ns/op %12.1f ns/op %+7.1f%% %8.1f allocs/op %8.1f allocs/op\n",
+			d.Name, d.Old.NsPerOp, d.New.NsPerOp, d.NsPerOpChange()*100, d.Old.AllocsPerOp, d.New.AllocsPerOp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
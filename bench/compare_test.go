@@ -0,0 +1,40 @@
+package bench
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const oldOutput = `goos: linux
+BenchmarkDispatch/listeners=1-8   	 1000000	       100.0 ns/op	       0 B/op	       0 allocs/op
+BenchmarkDispatch/listeners=1-8   	 1000000	       120.0 ns/op	       0 B/op	       0 allocs/op
+BenchmarkOnOff-8                  	 1000000	       300.0 ns/op	      64 B/op	       2 allocs/op
+PASS
+`
+
+const newOutput = `BenchmarkDispatch/listeners=1-8   	 1000000	       150.0 ns/op	       0 B/op	       0 allocs/op
+BenchmarkOnOff-8                  	 1000000	       290.0 ns/op	      64 B/op	       2 allocs/op
+BenchmarkRemoved-8                	 1000000	       290.0 ns/op
+`
+
+func TestParseAndCompare(t *testing.T) {
+	assert := assert.New(t)
+	old, err := Parse(strings.NewReader(oldOutput))
+	assert.Nil(err)
+	new, err := Parse(strings.NewReader(newOutput))
+	assert.Nil(err)
+
+	assert.Equal(110.0, old["BenchmarkDispatch/listeners=1"].NsPerOp, "Repeated runs should be averaged!")
+	assert.Equal(2.0, old["BenchmarkOnOff"].AllocsPerOp, "Invalid allocations number!")
+
+	deltas := Compare(old, new)
+	assert.Equal(2, len(deltas), "Only benchmarks present in both results should be compared!")
+	regressions := Regressions(deltas, 0.1)
+	assert.Equal(1, len(regressions), "Only the slowed down benchmark should be reported!")
+	assert.Equal("BenchmarkDispatch/listeners=1", regressions[0].Name)
+
+	var report strings.Builder
+	assert.Nil(WriteReport(&report, deltas))
+	assert.Contains(report.String(), "BenchmarkOnOff")
+}
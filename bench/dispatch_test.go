@@ -0,0 +1,128 @@
+package bench
+
+import (
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+	"testing"
+)
+
+const benchEventName = "bench.event"
+
+func newDispatcher(listeners int) *ed.EventDispatcher {
+	d := ed.NewDispatcher()
+	for i := 0; i < listeners; i++ {
+		d.On(benchEventName, func(e ed.Event) {})
+	}
+
+	return d
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("listeners=%d", n), func(b *testing.B) {
+			d := newDispatcher(n)
+			e := ed.NewParamsEvent(benchEventName)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.Dispatch(e)
+			}
+		})
+	}
+}
+
+func BenchmarkDispatchNoListeners(b *testing.B) {
+	d := newDispatcher(0)
+	e := ed.NewParamsEvent(benchEventName)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Dispatch(e)
+	}
+}
+
+func BenchmarkDispatchConcurrent(b *testing.B) {
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("listeners=%d", n), func(b *testing.B) {
+			d := newDispatcher(n)
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				e := ed.NewParamsEvent(benchEventName)
+				for pb.Next() {
+					d.Dispatch(e)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkOnOff(b *testing.B) {
+	d := newDispatcher(10)
+	l := func(e ed.Event) {}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.On(benchEventName, l)
+		d.Off(benchEventName, l)
+	}
+}
+
+func BenchmarkDispatchWildcard(b *testing.B) {
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("patterns=%d", n), func(b *testing.B) {
+			d := ed.NewDispatcher(ed.WithWildcardMatching())
+			for i := 0; i < n; i++ {
+				d.On(fmt.Sprintf("bench.%d.*", i), func(e ed.Event) {})
+			}
+			d.On("bench.*", func(e ed.Event) {})
+			e := ed.NewParamsEvent(benchEventName)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.Dispatch(e)
+			}
+		})
+	}
+}
+
+func BenchmarkDispatchAsync(b *testing.B) {
+	for _, n := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", n), func(b *testing.B) {
+			d := ed.NewDispatcher(ed.WithAsync(n))
+			d.On(benchEventName, func(e ed.Event) {})
+			e := ed.NewParamsEvent(benchEventName)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.DispatchAsync(e)
+			}
+			d.Drain()
+		})
+	}
+}
+
+// BenchmarkContention registers, dispatches and removes the listeners of
+// distinct event names from many goroutines, measuring the lock contention
+// depending on the number of shards
+func BenchmarkContention(b *testing.B) {
+	for _, shards := range []int{1, 8, 32} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			d := ed.NewDispatcher(ed.WithShards(shards))
+			var next int64
+			var mu sync.Mutex
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				mu.Lock()
+				next++
+				n := fmt.Sprintf("bench.%d", next)
+				mu.Unlock()
+				e := ed.NewParamsEvent(n)
+				l := func(e ed.Event) {}
+				for pb.Next() {
+					d.On(n, l)
+					d.Dispatch(e)
+					d.Off(n, l)
+				}
+			})
+		})
+	}
+}
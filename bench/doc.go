@@ -0,0 +1,9 @@
+// Package bench contains the benchmark suite of the event dispatcher and
+// tools for comparing the benchmark results against a baseline.
+//
+// Record the baseline before making changes and compare afterwards:
+//
+//	go test -run xxx -bench . -benchmem -count 5 ./bench > old.txt
+//	go test -run xxx -bench . -benchmem -count 5 ./bench > new.txt
+//	go run ./bench/cmd/benchcompare old.txt new.txt
+package bench
@@ -0,0 +1,290 @@
+// Package amqp bridges the event dispatcher with the AMQP broker, e.g.
+// RabbitMQ, publishing the dispatched events to the exchanges and
+// dispatching the messages consumed from the queues, so the dispatcher may
+// serve as the in-process edge of the broker topology. The events are
+// encoded as JSON and decoded as ed.ParamsEvent.
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"strings"
+	"sync"
+)
+
+const (
+	// ContentType is the content type of the published messages
+	ContentType = "application/json"
+
+	// ErrorEventName is the name of the source event of the errors of the
+	// consumed messages reported with ReportError
+	ErrorEventName = "amqp.error"
+)
+
+var (
+	// ErrNacked is wrapped by the error reported when the broker has not
+	// confirmed the published message
+	ErrNacked = errors.New("amqp: message not confirmed")
+
+	// ErrChannelClosed is wrapped by the error reported when the channel
+	// has been closed before the broker confirmed the published message
+	ErrChannelClosed = errors.New("amqp: channel closed")
+)
+
+// Channel is the subset of the *amqp091.Channel used by the bridge
+type Channel interface {
+
+	// Confirm puts the channel into the confirm mode
+	Confirm(noWait bool) error
+
+	// NotifyPublish registers the listener of the publisher confirms
+	NotifyPublish(confirm chan amqp091.Confirmation) chan amqp091.Confirmation
+
+	// PublishWithContext publishes the message
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp091.Publishing) error
+
+	// Qos limits the unacknowledged deliveries of the consumers
+	Qos(prefetchCount, prefetchSize int, global bool) error
+
+	// Consume starts delivering the messages of the queue
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error)
+
+	// Cancel stops the deliveries of the consumer
+	Cancel(consumer string, noWait bool) error
+}
+
+// Route is the exchange and the routing key the event is published with
+type Route struct {
+
+	// Exchange is the name of the exchange
+	Exchange string
+
+	// Key is the routing key
+	Key string
+}
+
+// RouteFunc maps the name of the event to the route it is published with
+type RouteFunc func(n string) Route
+
+// Option configures the bridge
+type Option func(*Bridge)
+
+// WithRoute makes the bridge map the event names to the routes with f
+// instead of publishing to the exchange of the bridge with the event name
+// as the routing key
+func WithRoute(f RouteFunc) Option {
+	return func(b *Bridge) {
+		b.route = f
+	}
+}
+
+// WithConfirms makes the bridge wait until the broker confirms each
+// published message, reporting the ErrNacked otherwise
+func WithConfirms() Option {
+	return func(b *Bridge) {
+		b.confirms = true
+	}
+}
+
+// WithPrefetch limits the messages delivered to the consumers of the bridge
+// and not acknowledged yet to n
+func WithPrefetch(n int) Option {
+	return func(b *Bridge) {
+		b.prefetch = n
+	}
+}
+
+// WithMetadata makes the bridge map the metadata of the relayed events with
+// m, e.g. lowering the priority of the consumed ones. The published events
+// are copied before, the dispatched ones are not modified.
+func WithMetadata(m ed.MetadataMapper) Option {
+	return func(b *Bridge) {
+		b.metadata = m
+	}
+}
+
+// Bridge relays the events between the dispatcher and the AMQP broker
+type Bridge struct {
+	d        *ed.EventDispatcher
+	ch       Channel
+	route    RouteFunc
+	confirms bool
+	prefetch int
+	metadata ed.MetadataMapper
+
+	// received holds the events being dispatched from the broker, so they
+	// are not published back
+	received sync.Map
+
+	// publishing serializes the publishes, so the confirms arrive in order
+	publishing sync.Mutex
+	confirmed  chan amqp091.Confirmation
+
+	mu        sync.Mutex
+	subs      []*ed.Subscription
+	consumers []string
+	edges     []func()
+	wg        sync.WaitGroup
+}
+
+// Publish publishes the events with given name (may contain many space
+// separated names) with their routes. The errors are reported with
+// ReportError. Returns this bridge instance
+func (b *Bridge) Publish(n string) *Bridge {
+	s := b.d.Subscribe(n, b.publish)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+	for _, name := range strings.Fields(n) {
+		r := b.route(name)
+		b.edges = append(b.edges, b.d.AddTopologyEdge(ed.TopologyEdge{From: name, To: "amqp:" + r.Exchange + "/" + r.Key, Kind: ed.TopologyBridge}))
+	}
+
+	return b
+}
+
+// Consume dispatches the messages of the queue as events, under the
+// consumer tag. The messages are acknowledged once dispatched, the ones
+// failing to decode or dispatch are rejected without requeueing, so the
+// broker may dead letter them.
+func (b *Bridge) Consume(queue string, consumer string) error {
+	if b.prefetch > 0 {
+		if err := b.ch.Qos(b.prefetch, 0, false); err != nil {
+			return fmt.Errorf("amqp: qos: %w", err)
+		}
+	}
+	deliveries, err := b.ch.Consume(queue, consumer, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("amqp: consume %s: %w", queue, err)
+	}
+
+	b.mu.Lock()
+	b.consumers = append(b.consumers, consumer)
+	b.edges = append(b.edges, b.d.AddTopologyEdge(ed.TopologyEdge{From: "amqp:" + queue, To: ed.TopologyAny, Kind: ed.TopologyBridge}))
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for dl := range deliveries {
+			b.receive(dl)
+		}
+	}()
+
+	return nil
+}
+
+// Close stops publishing the events, cancels the consumers and waits until
+// the deliveries in progress are dispatched. The channel is left open.
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	subs, consumers, edges := b.subs, b.consumers, b.edges
+	b.subs, b.consumers, b.edges = nil, nil, nil
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.Unsubscribe()
+	}
+	for _, remove := range edges {
+		remove()
+	}
+	var errs []error
+	for _, consumer := range consumers {
+		if err := b.ch.Cancel(consumer, false); err != nil {
+			errs = append(errs, fmt.Errorf("amqp: cancel %s: %w", consumer, err))
+		}
+	}
+	b.wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// publish is the listener publishing the event with its route
+func (b *Bridge) publish(e ed.Event) {
+	if _, ok := b.received.Load(e); ok {
+		return
+	}
+	if pe, ok := e.(*ed.ParamsEvent); ok && b.metadata != nil {
+		e = ed.MapMetadata(pe.Clone(), b.metadata)
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		b.d.ReportError(e, fmt.Errorf("amqp: encode %s: %w", e.Name(), err))
+		return
+	}
+	msg := amqp091.Publishing{ContentType: ContentType, Type: e.Name(), Body: body}
+	if ce, ok := e.(ed.Correlated); ok {
+		msg.MessageId, msg.CorrelationId = ce.ID(), ce.CorrelationID()
+	}
+
+	r := b.route(e.Name())
+	b.publishing.Lock()
+	defer b.publishing.Unlock()
+	if err := b.ch.PublishWithContext(context.Background(), r.Exchange, r.Key, false, false, msg); err != nil {
+		b.d.ReportError(e, fmt.Errorf("amqp: publish %s: %w", e.Name(), err))
+		return
+	}
+	if b.confirmed == nil {
+		return
+	}
+	c, ok := <-b.confirmed
+	switch {
+	case ok == false:
+		b.d.ReportError(e, fmt.Errorf("%w: publish %s", ErrChannelClosed, e.Name()))
+	case c.Ack == false:
+		b.d.ReportError(e, fmt.Errorf("%w: publish %s", ErrNacked, e.Name()))
+	}
+}
+
+// receive dispatches the delivered message and acknowledges it
+func (b *Bridge) receive(dl amqp091.Delivery) {
+	e := &ed.ParamsEvent{}
+	if err := json.Unmarshal(dl.Body, e); err != nil {
+		dl.Reject(false)
+		b.d.ReportError(ed.NewParamsEvent(ErrorEventName), fmt.Errorf("amqp: decode %s: %w", dl.RoutingKey, err))
+		return
+	}
+	if e.Name() == "" {
+		e = e.WithName(dl.Type)
+	}
+	if b.metadata != nil {
+		ed.MapMetadata(e, b.metadata)
+	}
+
+	b.received.Store(e, true)
+	_, err := b.d.TryDispatch(e)
+	b.received.Delete(e)
+	if err != nil {
+		dl.Reject(false)
+		b.d.ReportError(e, fmt.Errorf("amqp: dispatch %s: %w", e.Name(), err))
+		return
+	}
+	dl.Ack(false)
+}
+
+// New creates the bridge relaying the events between the dispatcher d and
+// the broker over the channel ch. The events are published to given
+// exchange with their names as the routing keys, unless configured
+// WithRoute. The channel is put into the confirm mode if configured
+// WithConfirms.
+func New(d *ed.EventDispatcher, ch Channel, exchange string, opts ...Option) (*Bridge, error) {
+	b := &Bridge{d: d, ch: ch, route: func(n string) Route {
+		return Route{exchange, n}
+	}}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.confirms {
+		if err := ch.Confirm(false); err != nil {
+			return nil, fmt.Errorf("amqp: confirm: %w", err)
+		}
+		b.confirmed = ch.NotifyPublish(make(chan amqp091.Confirmation, 1))
+	}
+
+	return b, nil
+}
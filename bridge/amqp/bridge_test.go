@@ -0,0 +1,156 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// published is the message published to the channel
+type published struct {
+	exchange string
+	key      string
+	msg      amqp091.Publishing
+}
+
+// channel is the Channel recording the published messages and delivering
+// the messages sent to its deliveries
+type channel struct {
+	published  []published
+	confirmed  chan amqp091.Confirmation
+	nack       bool
+	prefetch   int
+	deliveries chan amqp091.Delivery
+}
+
+func (ch *channel) Confirm(noWait bool) error {
+	return nil
+}
+
+func (ch *channel) NotifyPublish(confirm chan amqp091.Confirmation) chan amqp091.Confirmation {
+	ch.confirmed = confirm
+	return confirm
+}
+
+func (ch *channel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp091.Publishing) error {
+	ch.published = append(ch.published, published{exchange, key, msg})
+	if ch.confirmed != nil {
+		ch.confirmed <- amqp091.Confirmation{DeliveryTag: uint64(len(ch.published)), Ack: ch.nack == false}
+	}
+	return nil
+}
+
+func (ch *channel) Qos(prefetchCount, prefetchSize int, global bool) error {
+	ch.prefetch = prefetchCount
+	return nil
+}
+
+func (ch *channel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+	return ch.deliveries, nil
+}
+
+func (ch *channel) Cancel(consumer string, noWait bool) error {
+	close(ch.deliveries)
+	return nil
+}
+
+// acknowledger records the acknowledgements of the deliveries
+type acknowledger struct {
+	acked    []uint64
+	rejected []uint64
+}
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = append(a.acked, tag)
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.rejected = append(a.rejected, tag)
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	a.rejected = append(a.rejected, tag)
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	ch := &channel{}
+	b, err := New(d, ch, "events", WithConfirms())
+	assert.Nil(err)
+	b.Publish("order.paid")
+
+	e := ed.NewParamsEvent("order.paid").SetParam("id", "12")
+	e.SetCorrelation("e1", "c1", "")
+	d.Dispatch(e)
+	assert.Equal(1, len(ch.published), "The event should be published!")
+	p := ch.published[0]
+	assert.Equal("events", p.exchange, "The event should be published to the exchange!")
+	assert.Equal("order.paid", p.key, "The event name should be the routing key!")
+	assert.Equal("order.paid", p.msg.Type, "The message type should be the event name!")
+	assert.Equal("e1", p.msg.MessageId, "The message id should be the event id!")
+	assert.Equal("c1", p.msg.CorrelationId, "The correlation id should be set!")
+	received := &ed.ParamsEvent{}
+	assert.Nil(received.UnmarshalJSON(p.msg.Body))
+	id, _ := received.GetParam("id")
+	assert.Equal("12", id, "The params should be encoded!")
+
+	ch.nack = true
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	assert.ErrorIs(<-d.Errors(), ErrNacked, "The not confirmed message should be reported!")
+
+	assert.Nil(b.Close())
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	assert.Equal(2, len(ch.published), "The closed bridge should not publish!")
+}
+
+func TestRoute(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	ch := &channel{}
+	b, _ := New(d, ch, "events", WithRoute(func(n string) Route {
+		return Route{"audit", "all"}
+	}))
+	b.Publish("order.paid")
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	assert.Equal("audit", ch.published[0].exchange, "The route should be mapped!")
+	assert.Equal("all", ch.published[0].key, "The route should be mapped!")
+}
+
+func TestConsume(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	ch := &channel{deliveries: make(chan amqp091.Delivery)}
+	b, _ := New(d, ch, "events", WithPrefetch(10))
+	b.Publish("order.paid")
+
+	var got []ed.Event
+	d.On("order.paid", func(e ed.Event) {
+		got = append(got, e)
+	})
+	failure := errors.New("failed")
+	d.On("order.failed", func(e ed.Event) {
+		e.(*ed.ParamsEvent).Fail(failure)
+	})
+	assert.Nil(b.Consume("orders", "bridge"))
+	assert.Equal(10, ch.prefetch, "The prefetch should be set!")
+
+	a := &acknowledger{}
+	ch.deliveries <- amqp091.Delivery{Acknowledger: a, DeliveryTag: 1, Type: "order.paid", Body: []byte(`{"params":{"id":"12"}}`)}
+	ch.deliveries <- amqp091.Delivery{Acknowledger: a, DeliveryTag: 2, Body: []byte(`{`)}
+	ch.deliveries <- amqp091.Delivery{Acknowledger: a, DeliveryTag: 3, Body: []byte(`{"name":"order.failed"}`)}
+	assert.Nil(b.Close())
+
+	assert.Equal(1, len(got), "The consumed message should be dispatched!")
+	id, _ := got[0].(*ed.ParamsEvent).GetParam("id")
+	assert.Equal("12", id, "The params should be decoded!")
+	assert.Equal(0, len(ch.published), "The consumed event should not be published back!")
+	assert.Equal([]uint64{1}, a.acked, "The dispatched message should be acknowledged!")
+	assert.Equal([]uint64{2, 3}, a.rejected, "The failed messages should be rejected!")
+}
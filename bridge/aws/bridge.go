@@ -0,0 +1,302 @@
+// Package aws bridges the event dispatcher with AWS, publishing the
+// dispatched events to the SNS topics in batches and dispatching the
+// messages received from the SQS queues. The events are encoded as JSON and
+// decoded as ed.ParamsEvent. The bridge depends on the Topic and the Queue
+// interfaces only, the adapters of the AWS SDK clients are built with the
+// aws build tag.
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxBatchSize is the max number of the entries published to SNS at
+	// once
+	MaxBatchSize = 10
+
+	// DefaultBatchLatency is the max time the event waits for the batch to
+	// fill up, unless configured WithBatch
+	DefaultBatchLatency = 100 * time.Millisecond
+
+	// DefaultRetryDelay is the delay before receiving the messages again
+	// after the queue failed
+	DefaultRetryDelay = time.Second
+
+	// ErrorEventName is the name of the source event of the errors of the
+	// received messages reported with ReportError
+	ErrorEventName = "aws.error"
+)
+
+// Entry is the message published to the SNS topic
+type Entry struct {
+
+	// ID identifies the entry within the batch
+	ID string
+
+	// Message is the payload of the message
+	Message string
+
+	// GroupID is the message group of the FIFO topic, the messages of the
+	// same group are delivered in order
+	GroupID string
+
+	// DeduplicationID is the id of the event, deduplicating the messages
+	// of the FIFO topic
+	DeduplicationID string
+}
+
+// Topic publishes the entries to the SNS topic, e.g. the *sns.Client
+// adapted with FromSNS
+type Topic interface {
+
+	// PublishBatch publishes at most MaxBatchSize entries at once
+	PublishBatch(ctx context.Context, entries []Entry) error
+}
+
+// Message is the message received from the SQS queue
+type Message struct {
+
+	// ID is the id of the message
+	ID string
+
+	// Body is the payload of the message
+	Body string
+
+	// ReceiptHandle identifies the receipt of the message to delete
+	ReceiptHandle string
+}
+
+// Queue receives the messages from the SQS queue, e.g. the *sqs.Client
+// adapted with FromSQS
+type Queue interface {
+
+	// Receive waits for the messages, until ctx is done
+	Receive(ctx context.Context) ([]Message, error)
+
+	// Delete removes the handled message from the queue
+	Delete(ctx context.Context, m Message) error
+}
+
+// GroupFunc returns the message group of the event, e.g. the id of the
+// aggregate, or an empty string for the standard topics
+type GroupFunc func(e ed.Event) string
+
+// Option configures the bridge
+type Option func(*Bridge)
+
+// WithBatch makes the bridge publish the events in batches of at most size
+// entries, capped at MaxBatchSize, waiting at most latency for the batch
+// to fill up
+func WithBatch(size int, latency time.Duration) Option {
+	return func(b *Bridge) {
+		b.batchSize = min(size, MaxBatchSize)
+		b.batchLatency = latency
+	}
+}
+
+// WithGroup makes the bridge publish the events to the FIFO topics in the
+// message groups returned by f
+func WithGroup(f GroupFunc) Option {
+	return func(b *Bridge) {
+		b.group = f
+	}
+}
+
+// WithMetadata makes the bridge map the metadata of the relayed events with
+// m, e.g. lowering the priority of the received ones. The published events
+// are copied before, the dispatched ones are not modified.
+func WithMetadata(m ed.MetadataMapper) Option {
+	return func(b *Bridge) {
+		b.metadata = m
+	}
+}
+
+// Bridge relays the events between the dispatcher and AWS
+type Bridge struct {
+	d            *ed.EventDispatcher
+	batchSize    int
+	batchLatency time.Duration
+	group        GroupFunc
+	metadata     ed.MetadataMapper
+
+	// received holds the events being dispatched from the queues, so they
+	// are not published back
+	received sync.Map
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	subs    []*ed.Subscription
+	batches []*ed.BatchListener
+	edges   []func()
+}
+
+// Publish publishes the events with given name (may contain many space
+// separated names) to the topic t, in batches. The errors are reported with
+// ReportError. Returns this bridge instance
+func (b *Bridge) Publish(n string, t Topic) *Bridge {
+	bl := ed.NewBatchListener(b.batchSize, b.batchLatency, func(events []ed.Event) {
+		b.publish(t, events)
+	})
+	s := b.d.Subscribe(n, func(e ed.Event) {
+		if _, ok := b.received.Load(e); ok == false {
+			bl.Listen(e)
+		}
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+	b.batches = append(b.batches, bl)
+	for _, name := range strings.Fields(n) {
+		b.edges = append(b.edges, b.d.AddTopologyEdge(ed.TopologyEdge{From: name, To: "sns", Kind: ed.TopologyBridge}))
+	}
+
+	return b
+}
+
+// Consume dispatches the messages received from the queue q as events, in
+// the background until the bridge is closed. The dispatched messages are
+// deleted, the ones failing to decode or dispatch are left to be received
+// again once their visibility timeout expires.
+func (b *Bridge) Consume(q Queue) {
+	remove := b.d.AddTopologyEdge(ed.TopologyEdge{From: "sqs", To: ed.TopologyAny, Kind: ed.TopologyBridge})
+	b.mu.Lock()
+	b.edges = append(b.edges, remove)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for b.ctx.Err() == nil {
+			messages, err := q.Receive(b.ctx)
+			if err != nil {
+				if b.ctx.Err() != nil {
+					return
+				}
+				b.d.ReportError(ed.NewParamsEvent(ErrorEventName), fmt.Errorf("aws: receive: %w", err))
+				select {
+				case <-b.ctx.Done():
+				case <-time.After(DefaultRetryDelay):
+				}
+				continue
+			}
+			for _, m := range messages {
+				b.receive(q, m)
+			}
+		}
+	}()
+}
+
+// Close stops publishing the events and receiving the messages, publishes
+// the pending batches and waits until the messages in progress are
+// dispatched
+func (b *Bridge) Close() {
+	b.mu.Lock()
+	subs, batches, edges := b.subs, b.batches, b.edges
+	b.subs, b.batches, b.edges = nil, nil, nil
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.Unsubscribe()
+	}
+	for _, remove := range edges {
+		remove()
+	}
+	for _, bl := range batches {
+		bl.Close()
+	}
+	b.cancel()
+	b.wg.Wait()
+}
+
+// publish publishes the batch of events to the topic t
+func (b *Bridge) publish(t Topic, events []ed.Event) {
+	entries := make([]Entry, 0, len(events))
+	published := make([]ed.Event, 0, len(events))
+	for i, e := range events {
+		if pe, ok := e.(*ed.ParamsEvent); ok && b.metadata != nil {
+			e = ed.MapMetadata(pe.Clone(), b.metadata)
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			b.d.ReportError(e, fmt.Errorf("aws: encode %s: %w", e.Name(), err))
+			continue
+		}
+		entry := Entry{ID: strconv.Itoa(i), Message: string(data)}
+		if b.group != nil {
+			entry.GroupID = b.group(e)
+		}
+		if ce, ok := e.(ed.Correlated); ok {
+			entry.DeduplicationID = ce.ID()
+		}
+		entries = append(entries, entry)
+		published = append(published, e)
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := t.PublishBatch(context.Background(), entries); err != nil {
+		for _, e := range published {
+			b.d.ReportError(e, fmt.Errorf("aws: publish %s: %w", e.Name(), err))
+		}
+	}
+}
+
+// notification is the envelope of the SNS message delivered to the SQS
+// queue without the raw message delivery
+type notification struct {
+	Type    string
+	Message string
+}
+
+// receive dispatches the received message and deletes it from the queue
+func (b *Bridge) receive(q Queue, m Message) {
+	body := m.Body
+	var n notification
+	if json.Unmarshal([]byte(body), &n) == nil && n.Type == "Notification" {
+		body = n.Message
+	}
+	e := &ed.ParamsEvent{}
+	if err := json.Unmarshal([]byte(body), e); err != nil {
+		b.d.ReportError(ed.NewParamsEvent(ErrorEventName), fmt.Errorf("aws: decode %s: %w", m.ID, err))
+		return
+	}
+	if b.metadata != nil {
+		ed.MapMetadata(e, b.metadata)
+	}
+
+	b.received.Store(e, true)
+	_, err := b.d.TryDispatch(e)
+	b.received.Delete(e)
+	if err != nil {
+		b.d.ReportError(e, fmt.Errorf("aws: dispatch %s: %w", e.Name(), err))
+		return
+	}
+	if err := q.Delete(context.Background(), m); err != nil {
+		b.d.ReportError(e, fmt.Errorf("aws: delete %s: %w", m.ID, err))
+	}
+}
+
+// New creates the bridge relaying the events between the dispatcher d and
+// AWS
+func New(d *ed.EventDispatcher, opts ...Option) *Bridge {
+	b := &Bridge{d: d, batchSize: MaxBatchSize, batchLatency: DefaultBatchLatency}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
@@ -0,0 +1,106 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// topic is the Topic recording the published batches
+type topic struct {
+	sync.Mutex
+	batches [][]Entry
+	err     error
+}
+
+func (t *topic) PublishBatch(ctx context.Context, entries []Entry) error {
+	t.Lock()
+	defer t.Unlock()
+	t.batches = append(t.batches, entries)
+	return t.err
+}
+
+// queue is the Queue receiving the given messages once and recording the
+// deleted ones
+type queue struct {
+	messages []Message
+	deleted  []string
+}
+
+func (q *queue) Receive(ctx context.Context) ([]Message, error) {
+	messages := q.messages
+	q.messages = nil
+	if len(messages) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return messages, nil
+}
+
+func (q *queue) Delete(ctx context.Context, m Message) error {
+	q.deleted = append(q.deleted, m.ID)
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	tp := &topic{}
+	b := New(d, WithBatch(20, time.Hour), WithGroup(func(e ed.Event) string {
+		return "orders"
+	})).Publish("order.paid", tp)
+
+	for i := 0; i < MaxBatchSize+1; i++ {
+		e := ed.NewParamsEvent("order.paid").SetParam("i", i)
+		e.SetCorrelation("e"+string(rune('a'+i)), "", "")
+		d.Dispatch(e)
+	}
+	assert.Equal(1, len(tp.batches), "The full batch should be published!")
+	assert.Equal(MaxBatchSize, len(tp.batches[0]), "The batch should be capped!")
+	b.Close()
+	assert.Equal(2, len(tp.batches), "The pending batch should be published on close!")
+	entry := tp.batches[1][0]
+	assert.Equal("orders", entry.GroupID, "The message group should be set!")
+	assert.Equal("ek", entry.DeduplicationID, "The event id should deduplicate the message!")
+	e := &ed.ParamsEvent{}
+	assert.Nil(e.UnmarshalJSON([]byte(entry.Message)))
+	i, _ := e.GetParam("i")
+	assert.Equal(float64(MaxBatchSize), i, "The event should be encoded!")
+
+	tp.err = errors.New("throttled")
+	b = New(d).Publish("order.paid", tp)
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	b.Close()
+	assert.ErrorIs(<-d.Errors(), tp.err, "The publish error should be reported!")
+}
+
+func TestConsume(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	tp := &topic{}
+	var got []ed.Event
+	d.On("order.paid", func(e ed.Event) {
+		got = append(got, e)
+	})
+	q := &queue{messages: []Message{
+		{ID: "1", Body: `{"name":"order.paid","params":{"order":"12"}}`},
+		{ID: "2", Body: `{"Type":"Notification","Message":"{\"name\":\"order.paid\"}"}`},
+		{ID: "3", Body: `{`},
+	}}
+	b := New(d).Publish("order.paid", tp)
+	b.Consume(q)
+	assert.Eventually(func() bool {
+		return len(d.Errors()) != 0
+	}, time.Second, time.Millisecond, "The decode error should be reported!")
+	b.Close()
+
+	assert.Equal(2, len(got), "The received messages should be dispatched!")
+	order, _ := got[0].(*ed.ParamsEvent).GetParam("order")
+	assert.Equal("12", order, "The params should be decoded!")
+	assert.Equal([]string{"1", "2"}, q.deleted, "Only the dispatched messages should be deleted!")
+	assert.Equal(0, len(tp.batches), "The received events should not be published back!")
+}
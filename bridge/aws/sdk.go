@@ -0,0 +1,97 @@
+//go:build aws
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// MaxWaitSeconds is the max time the SQS queue adapted with FromSQS waits
+// for the messages to arrive, enabling the long polling
+const MaxWaitSeconds = 20
+
+// snsTopic adapts the *sns.Client publishing to the topic to the Topic
+type snsTopic struct {
+	c   *sns.Client
+	arn string
+}
+
+// PublishBatch publishes at most MaxBatchSize entries at once
+func (t snsTopic) PublishBatch(ctx context.Context, entries []Entry) error {
+	in := &sns.PublishBatchInput{TopicArn: awssdk.String(t.arn)}
+	for _, e := range entries {
+		re := snstypes.PublishBatchRequestEntry{Id: awssdk.String(e.ID), Message: awssdk.String(e.Message)}
+		if e.GroupID != "" {
+			re.MessageGroupId = awssdk.String(e.GroupID)
+		}
+		if e.GroupID != "" && e.DeduplicationID != "" {
+			re.MessageDeduplicationId = awssdk.String(e.DeduplicationID)
+		}
+		in.PublishBatchRequestEntries = append(in.PublishBatchRequestEntries, re)
+	}
+
+	out, err := t.c.PublishBatch(ctx, in)
+	if err != nil {
+		return err
+	}
+	if len(out.Failed) != 0 {
+		return fmt.Errorf("%d of %d entries failed: %s", len(out.Failed), len(entries), awssdk.ToString(out.Failed[0].Message))
+	}
+
+	return nil
+}
+
+// FromSNS adapts the SNS client to the Topic publishing to the topic with
+// given ARN
+func FromSNS(c *sns.Client, arn string) Topic {
+	return snsTopic{c, arn}
+}
+
+// sqsQueue adapts the *sqs.Client receiving from the queue to the Queue
+type sqsQueue struct {
+	c   *sqs.Client
+	url string
+}
+
+// Receive waits for the messages, until ctx is done
+func (q sqsQueue) Receive(ctx context.Context) ([]Message, error) {
+	out, err := q.c.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            awssdk.String(q.url),
+		MaxNumberOfMessages: MaxBatchSize,
+		WaitTimeSeconds:     MaxWaitSeconds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]Message, len(out.Messages))
+	for i, m := range out.Messages {
+		messages[i] = Message{
+			ID:            awssdk.ToString(m.MessageId),
+			Body:          awssdk.ToString(m.Body),
+			ReceiptHandle: awssdk.ToString(m.ReceiptHandle),
+		}
+	}
+
+	return messages, nil
+}
+
+// Delete removes the handled message from the queue
+func (q sqsQueue) Delete(ctx context.Context, m Message) error {
+	_, err := q.c.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      awssdk.String(q.url),
+		ReceiptHandle: awssdk.String(m.ReceiptHandle),
+	})
+
+	return err
+}
+
+// FromSQS adapts the SQS client to the Queue receiving from the queue with
+// given URL, with the long polling
+func FromSQS(c *sqs.Client, url string) Queue {
+	return sqsQueue{c, url}
+}
@@ -0,0 +1,199 @@
+// Package mqtt bridges the event dispatcher with the MQTT broker, publishing
+// the dispatched events to the topics and dispatching the messages received
+// from the subscribed topics. The events are encoded as JSON and decoded as
+// ed.ParamsEvent.
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	ed "github.com/gacek85/eventdispatcher"
+	"strings"
+	"sync"
+)
+
+const (
+	// TopicSeparator separates the levels of the MQTT topic
+	TopicSeparator = "/"
+
+	// ErrorEventName is the name of the source event of the errors of the
+	// received messages reported with ReportError
+	ErrorEventName = "mqtt.error"
+)
+
+// TopicFunc maps the name of the event to the topic it is published to
+type TopicFunc func(n string) string
+
+// DefaultTopic maps the event name to the topic by replacing the namespace
+// separators with the topic ones, e.g. "order.paid" to "order/paid"
+func DefaultTopic(n string) string {
+	return strings.ReplaceAll(n, ed.NamespaceSeparator, TopicSeparator)
+}
+
+// DefaultName maps the topic to the event name, reversing DefaultTopic. It
+// names the received events encoded without the name.
+func DefaultName(topic string) string {
+	return strings.ReplaceAll(topic, TopicSeparator, ed.NamespaceSeparator)
+}
+
+// Option configures the bridge
+type Option func(*Bridge)
+
+// WithQoS makes the bridge publish and subscribe with given quality of
+// service level, 0 (at most once, the default), 1 (at least once) or 2
+// (exactly once)
+func WithQoS(qos byte) Option {
+	return func(b *Bridge) {
+		b.qos = qos
+	}
+}
+
+// WithRetained makes the broker retain the last message published to each
+// topic, so it is delivered to the clients subscribing later
+func WithRetained() Option {
+	return func(b *Bridge) {
+		b.retained = true
+	}
+}
+
+// WithTopic makes the bridge map the event names to the topics with f
+func WithTopic(f TopicFunc) Option {
+	return func(b *Bridge) {
+		b.topic = f
+	}
+}
+
+// WithMetadata makes the bridge map the metadata of the relayed events with
+// m, e.g. lowering the priority of the received ones. The published events
+// are copied before, the dispatched ones are not modified.
+func WithMetadata(m ed.MetadataMapper) Option {
+	return func(b *Bridge) {
+		b.metadata = m
+	}
+}
+
+// Bridge relays the events between the dispatcher and the MQTT broker
+type Bridge struct {
+	d        *ed.EventDispatcher
+	c        paho.Client
+	qos      byte
+	retained bool
+	topic    TopicFunc
+	metadata ed.MetadataMapper
+
+	// received holds the events being dispatched from the broker, so they
+	// are not published back
+	received sync.Map
+
+	mu      sync.Mutex
+	subs    []*ed.Subscription
+	filters []string
+	edges   []func()
+}
+
+// Publish publishes the events with given name (may contain many space
+// separated names) to their topics. The errors are reported with
+// ReportError. Returns this bridge instance
+func (b *Bridge) Publish(n string) *Bridge {
+	s := b.d.Subscribe(n, b.publish)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+	for _, name := range strings.Fields(n) {
+		b.edges = append(b.edges, b.d.AddTopologyEdge(ed.TopologyEdge{From: name, To: "mqtt:" + b.topic(name), Kind: ed.TopologyBridge}))
+	}
+
+	return b
+}
+
+// Subscribe subscribes to the topic filter, e.g. "sensors/+/reading", and
+// dispatches the received messages as events
+func (b *Bridge) Subscribe(filter string) error {
+	t := b.c.Subscribe(filter, b.qos, b.receive)
+	if t.Wait(); t.Error() != nil {
+		return fmt.Errorf("mqtt: subscribe %s: %w", filter, t.Error())
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.filters = append(b.filters, filter)
+	b.edges = append(b.edges, b.d.AddTopologyEdge(ed.TopologyEdge{From: "mqtt:" + filter, To: ed.TopologyAny, Kind: ed.TopologyBridge}))
+
+	return nil
+}
+
+// Close stops publishing the events and unsubscribes from all topic
+// filters. The client is left connected.
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	subs, filters, edges := b.subs, b.filters, b.edges
+	b.subs, b.filters, b.edges = nil, nil, nil
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.Unsubscribe()
+	}
+	for _, remove := range edges {
+		remove()
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	t := b.c.Unsubscribe(filters...)
+	if t.Wait(); t.Error() != nil {
+		return fmt.Errorf("mqtt: unsubscribe: %w", t.Error())
+	}
+
+	return nil
+}
+
+// publish is the listener publishing the event to its topic
+func (b *Bridge) publish(e ed.Event) {
+	if _, ok := b.received.Load(e); ok {
+		return
+	}
+	if pe, ok := e.(*ed.ParamsEvent); ok && b.metadata != nil {
+		e = ed.MapMetadata(pe.Clone(), b.metadata)
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		b.d.ReportError(e, fmt.Errorf("mqtt: encode %s: %w", e.Name(), err))
+		return
+	}
+	t := b.c.Publish(b.topic(e.Name()), b.qos, b.retained, payload)
+	if t.Wait(); t.Error() != nil {
+		b.d.ReportError(e, fmt.Errorf("mqtt: publish %s: %w", e.Name(), t.Error()))
+	}
+}
+
+// receive is the message handler dispatching the received message
+func (b *Bridge) receive(c paho.Client, m paho.Message) {
+	e := &ed.ParamsEvent{}
+	if err := json.Unmarshal(m.Payload(), e); err != nil {
+		b.d.ReportError(ed.NewParamsEvent(ErrorEventName), fmt.Errorf("mqtt: decode %s: %w", m.Topic(), err))
+		return
+	}
+	if e.Name() == "" {
+		e = e.WithName(DefaultName(m.Topic()))
+	}
+	if b.metadata != nil {
+		ed.MapMetadata(e, b.metadata)
+	}
+
+	b.received.Store(e, true)
+	defer b.received.Delete(e)
+	b.d.Dispatch(e)
+}
+
+// New creates the bridge relaying the events between the dispatcher d and
+// the broker the client c is connected to
+func New(d *ed.EventDispatcher, c paho.Client, opts ...Option) *Bridge {
+	b := &Bridge{d: d, c: c, topic: DefaultTopic}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
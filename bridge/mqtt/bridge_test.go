@@ -0,0 +1,134 @@
+package mqtt
+
+import (
+	"errors"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// token is the completed paho.Token
+type token struct {
+	err error
+}
+
+func (t token) Wait() bool                     { return true }
+func (t token) WaitTimeout(time.Duration) bool { return true }
+func (t token) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+func (t token) Error() error { return t.err }
+
+// message is the paho.Message received from the broker
+type message struct {
+	topic   string
+	qos     byte
+	payload []byte
+}
+
+func (m message) Duplicate() bool   { return false }
+func (m message) Qos() byte         { return m.qos }
+func (m message) Retained() bool    { return false }
+func (m message) Topic() string     { return m.topic }
+func (m message) MessageID() uint16 { return 0 }
+func (m message) Payload() []byte   { return m.payload }
+func (m message) Ack()              {}
+
+// broker is the paho.Client delivering the published messages to the
+// handlers subscribed to their exact topics
+type broker struct {
+	paho.Client
+	published []message
+	handlers  map[string]paho.MessageHandler
+	err       error
+}
+
+func (c *broker) Publish(topic string, qos byte, retained bool, payload interface{}) paho.Token {
+	m := message{topic, qos, payload.([]byte)}
+	c.published = append(c.published, m)
+	if h, ok := c.handlers[topic]; ok {
+		h(c, m)
+	}
+	return token{c.err}
+}
+
+func (c *broker) Subscribe(topic string, qos byte, h paho.MessageHandler) paho.Token {
+	c.handlers[topic] = h
+	return token{c.err}
+}
+
+func (c *broker) Unsubscribe(topics ...string) paho.Token {
+	for _, topic := range topics {
+		delete(c.handlers, topic)
+	}
+	return token{c.err}
+}
+
+func newBroker() *broker {
+	return &broker{handlers: make(map[string]paho.MessageHandler)}
+}
+
+func TestPublish(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	c := newBroker()
+	b := New(d, c, WithQoS(1), WithMetadata(func(md *ed.Metadata, source ed.Event) {
+		md.Priority = ed.PriorityLow
+	})).Publish("order.paid order.shipped")
+
+	e := ed.NewParamsEvent("order.paid").SetParam("id", "12")
+	d.Dispatch(e)
+	d.Dispatch(ed.NewParamsEvent("order.canceled"))
+	assert.Equal(1, len(c.published), "Only the published events should be relayed!")
+	m := c.published[0]
+	assert.Equal("order/paid", m.topic, "The event should be published to its topic!")
+	assert.Equal(byte(1), m.qos, "The event should be published with the QoS!")
+
+	received := &ed.ParamsEvent{}
+	assert.Nil(received.UnmarshalJSON(m.payload))
+	id, _ := received.GetParam("id")
+	assert.Equal("12", id, "The params should be encoded!")
+	assert.Equal(ed.PriorityLow, received.Priority(), "The metadata should be mapped!")
+	assert.Equal(ed.PriorityNormal, e.Priority(), "The dispatched event should not be modified!")
+
+	edge := ed.TopologyEdge{From: "order.paid", To: "mqtt:order/paid", Kind: ed.TopologyBridge}
+	assert.Contains(d.Topology().Edges, edge, "The bridge should be linked in the topology!")
+
+	assert.Nil(b.Close())
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	assert.Equal(1, len(c.published), "The closed bridge should not publish!")
+	assert.NotContains(d.Topology().Edges, edge, "The closed bridge should not be linked!")
+
+	c.err = errors.New("disconnected")
+	New(d, c).Publish("order.paid")
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	assert.ErrorIs(<-d.Errors(), c.err, "The publish error should be reported!")
+}
+
+func TestSubscribe(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	c := newBroker()
+	b := New(d, c).Publish("sensor.reading")
+	assert.Nil(b.Subscribe("sensor/reading"))
+
+	var got []ed.Event
+	d.On("sensor.reading", func(e ed.Event) {
+		got = append(got, e)
+	})
+	c.Publish("sensor/reading", 0, false, []byte(`{"params":{"value":21.5}}`))
+	assert.Equal(1, len(got), "The received message should be dispatched!")
+	value, _ := got[0].(*ed.ParamsEvent).GetParam("value")
+	assert.Equal(21.5, value, "The params should be decoded!")
+	assert.Equal(1, len(c.published), "The received event should not be published back!")
+
+	c.Publish("sensor/reading", 0, false, []byte(`{`))
+	assert.NotNil(<-d.Errors(), "The decode error should be reported!")
+
+	assert.Nil(b.Close())
+	assert.Equal(0, len(c.handlers), "The bridge should unsubscribe from the topics!")
+}
@@ -0,0 +1,231 @@
+// Package pubsub bridges the event dispatcher with Google Cloud Pub/Sub,
+// publishing the dispatched events to the topics and dispatching the
+// messages received from the subscriptions. The events are encoded as JSON
+// and decoded as ed.ParamsEvent. The bridge depends on the Topic and the
+// Subscription interfaces only, the adapters of the Pub/Sub client are
+// built with the gcp build tag.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"strings"
+	"sync"
+)
+
+const (
+	// AttrName is the attribute of the published messages holding the
+	// event name
+	AttrName = "event"
+
+	// ErrorEventName is the name of the source event of the errors of the
+	// received messages reported with ReportError
+	ErrorEventName = "pubsub.error"
+)
+
+// Message is the Pub/Sub message
+type Message struct {
+
+	// Data is the payload of the message
+	Data []byte
+
+	// Attributes are the key-value metadata of the message
+	Attributes map[string]string
+
+	// OrderingKey makes the messages with the same key delivered in the
+	// order they have been published, if the topic enables the ordering
+	OrderingKey string
+}
+
+// PublishResult is the result of the message published in the background
+type PublishResult interface {
+
+	// Get waits until the message is published and returns its server id
+	Get(ctx context.Context) (string, error)
+}
+
+// Topic publishes the messages, batching them in the background, e.g. the
+// *pubsub.Topic adapted with FromTopic
+type Topic interface {
+
+	// Publish schedules the message for publishing
+	Publish(ctx context.Context, m Message) PublishResult
+}
+
+// Subscription receives the messages, e.g. the *pubsub.Subscription
+// adapted with FromSubscription
+type Subscription interface {
+
+	// Receive calls f for the received messages, concurrently, until ctx
+	// is done. The message is acknowledged if f returns nil and negatively
+	// acknowledged otherwise, so it is redelivered.
+	Receive(ctx context.Context, f func(ctx context.Context, m Message) error) error
+}
+
+// OrderingKeyFunc returns the ordering key of the event, e.g. the id of the
+// aggregate, or an empty string for the unordered one
+type OrderingKeyFunc func(e ed.Event) string
+
+// Option configures the bridge
+type Option func(*Bridge)
+
+// WithOrderingKey makes the bridge publish the events with the ordering
+// keys returned by f
+func WithOrderingKey(f OrderingKeyFunc) Option {
+	return func(b *Bridge) {
+		b.orderingKey = f
+	}
+}
+
+// WithMetadata makes the bridge map the metadata of the relayed events with
+// m, e.g. lowering the priority of the received ones. The published events
+// are copied before, the dispatched ones are not modified.
+func WithMetadata(m ed.MetadataMapper) Option {
+	return func(b *Bridge) {
+		b.metadata = m
+	}
+}
+
+// Bridge relays the events between the dispatcher and Pub/Sub
+type Bridge struct {
+	d           *ed.EventDispatcher
+	orderingKey OrderingKeyFunc
+	metadata    ed.MetadataMapper
+
+	// received holds the events being dispatched from Pub/Sub, so they are
+	// not published back
+	received sync.Map
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	subs  []*ed.Subscription
+	edges []func()
+}
+
+// Publish publishes the events with given name (may contain many space
+// separated names) to the topic t. The messages are published in the
+// background, the errors are reported with ReportError. Returns this bridge
+// instance
+func (b *Bridge) Publish(n string, t Topic) *Bridge {
+	s := b.d.Subscribe(n, func(e ed.Event) {
+		b.publish(t, e)
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+	for _, name := range strings.Fields(n) {
+		b.edges = append(b.edges, b.d.AddTopologyEdge(ed.TopologyEdge{From: name, To: "pubsub", Kind: ed.TopologyBridge}))
+	}
+
+	return b
+}
+
+// Consume dispatches the messages received from the subscription s as
+// events, in the background until the bridge is closed. The messages
+// failing to decode or dispatch are redelivered.
+func (b *Bridge) Consume(s Subscription) {
+	remove := b.d.AddTopologyEdge(ed.TopologyEdge{From: "pubsub", To: ed.TopologyAny, Kind: ed.TopologyBridge})
+	b.mu.Lock()
+	b.edges = append(b.edges, remove)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		err := s.Receive(b.ctx, b.receive)
+		if err != nil && b.ctx.Err() == nil {
+			b.d.ReportError(ed.NewParamsEvent(ErrorEventName), fmt.Errorf("pubsub: receive: %w", err))
+		}
+	}()
+}
+
+// Close stops publishing the events and receiving the messages and waits
+// until the messages in progress are published or dispatched
+func (b *Bridge) Close() {
+	b.mu.Lock()
+	subs, edges := b.subs, b.edges
+	b.subs, b.edges = nil, nil
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.Unsubscribe()
+	}
+	for _, remove := range edges {
+		remove()
+	}
+	b.cancel()
+	b.wg.Wait()
+}
+
+// publish publishes the event to the topic t and waits for the result in
+// the background
+func (b *Bridge) publish(t Topic, e ed.Event) {
+	if _, ok := b.received.Load(e); ok {
+		return
+	}
+	if pe, ok := e.(*ed.ParamsEvent); ok && b.metadata != nil {
+		e = ed.MapMetadata(pe.Clone(), b.metadata)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		b.d.ReportError(e, fmt.Errorf("pubsub: encode %s: %w", e.Name(), err))
+		return
+	}
+	m := Message{Data: data, Attributes: map[string]string{AttrName: e.Name()}}
+	if b.orderingKey != nil {
+		m.OrderingKey = b.orderingKey(e)
+	}
+
+	r := t.Publish(context.Background(), m)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		if _, err := r.Get(context.Background()); err != nil {
+			b.d.ReportError(e, fmt.Errorf("pubsub: publish %s: %w", e.Name(), err))
+		}
+	}()
+}
+
+// receive dispatches the received message
+func (b *Bridge) receive(ctx context.Context, m Message) error {
+	e := &ed.ParamsEvent{}
+	if err := json.Unmarshal(m.Data, e); err != nil {
+		err = fmt.Errorf("pubsub: decode: %w", err)
+		b.d.ReportError(ed.NewParamsEvent(ErrorEventName), err)
+		return err
+	}
+	if e.Name() == "" {
+		e = e.WithName(m.Attributes[AttrName])
+	}
+	if b.metadata != nil {
+		ed.MapMetadata(e, b.metadata)
+	}
+
+	b.received.Store(e, true)
+	defer b.received.Delete(e)
+	if _, err := b.d.TryDispatch(e); err != nil {
+		err = fmt.Errorf("pubsub: dispatch %s: %w", e.Name(), err)
+		b.d.ReportError(e, err)
+		return err
+	}
+
+	return nil
+}
+
+// New creates the bridge relaying the events between the dispatcher d and
+// Pub/Sub
+func New(d *ed.EventDispatcher, opts ...Option) *Bridge {
+	b := &Bridge{d: d}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
@@ -0,0 +1,101 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// result is the PublishResult of the topic
+type result struct {
+	err error
+}
+
+func (r result) Get(ctx context.Context) (string, error) {
+	return "id", r.err
+}
+
+// topic is the Topic recording the published messages
+type topic struct {
+	sync.Mutex
+	published []Message
+	err       error
+}
+
+func (t *topic) Publish(ctx context.Context, m Message) PublishResult {
+	t.Lock()
+	defer t.Unlock()
+	t.published = append(t.published, m)
+	return result{t.err}
+}
+
+// subscription is the Subscription receiving the given messages and
+// recording whether they have been acknowledged
+type subscription struct {
+	messages []Message
+	acked    []bool
+}
+
+func (s *subscription) Receive(ctx context.Context, f func(ctx context.Context, m Message) error) error {
+	for _, m := range s.messages {
+		s.acked = append(s.acked, f(ctx, m) == nil)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	tp := &topic{}
+	b := New(d, WithOrderingKey(func(e ed.Event) string {
+		id, _ := e.(*ed.ParamsEvent).GetParam("order")
+		return id.(string)
+	})).Publish("order.paid", tp)
+
+	d.Dispatch(ed.NewParamsEvent("order.paid").SetParam("order", "12"))
+	b.Close()
+	d.Dispatch(ed.NewParamsEvent("order.paid").SetParam("order", "13"))
+	assert.Equal(1, len(tp.published), "Only the events dispatched before closing should be published!")
+	m := tp.published[0]
+	assert.Equal("order.paid", m.Attributes[AttrName], "The event name should be the attribute!")
+	assert.Equal("12", m.OrderingKey, "The ordering key should be set!")
+	e := &ed.ParamsEvent{}
+	assert.Nil(e.UnmarshalJSON(m.Data))
+	assert.Equal("order.paid", e.Name(), "The event should be encoded!")
+
+	tp.err = errors.New("unavailable")
+	b = New(d).Publish("order.paid", tp)
+	d.Dispatch(ed.NewParamsEvent("order.paid").SetParam("order", "14"))
+	b.Close()
+	assert.ErrorIs(<-d.Errors(), tp.err, "The publish error should be reported!")
+}
+
+func TestConsume(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	tp := &topic{}
+	var mu sync.Mutex
+	var got []ed.Event
+	d.On("order.paid", func(e ed.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e)
+	})
+	s := &subscription{messages: []Message{
+		{Data: []byte(`{"params":{"order":"12"}}`), Attributes: map[string]string{AttrName: "order.paid"}},
+		{Data: []byte(`{`)},
+	}}
+	b := New(d).Publish("order.paid", tp)
+	b.Consume(s)
+	b.Close()
+
+	assert.Equal(1, len(got), "The received message should be dispatched!")
+	order, _ := got[0].(*ed.ParamsEvent).GetParam("order")
+	assert.Equal("12", order, "The params should be decoded!")
+	assert.Equal([]bool{true, false}, s.acked, "Only the dispatched message should be acknowledged!")
+	assert.Equal(0, len(tp.published), "The received event should not be published back!")
+}
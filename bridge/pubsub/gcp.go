@@ -0,0 +1,47 @@
+//go:build gcp
+
+package pubsub
+
+import (
+	"cloud.google.com/go/pubsub"
+	"context"
+)
+
+// gcpTopic adapts the *pubsub.Topic to the Topic
+type gcpTopic struct {
+	t *pubsub.Topic
+}
+
+// Publish schedules the message for publishing
+func (t gcpTopic) Publish(ctx context.Context, m Message) PublishResult {
+	return t.t.Publish(ctx, &pubsub.Message{Data: m.Data, Attributes: m.Attributes, OrderingKey: m.OrderingKey})
+}
+
+// FromTopic adapts the Pub/Sub topic to the Topic. The batching is
+// configured with its PublishSettings, the ordering keys require its
+// EnableMessageOrdering to be set.
+func FromTopic(t *pubsub.Topic) Topic {
+	return gcpTopic{t}
+}
+
+// gcpSubscription adapts the *pubsub.Subscription to the Subscription
+type gcpSubscription struct {
+	s *pubsub.Subscription
+}
+
+// Receive calls f for the received messages until ctx is done
+func (s gcpSubscription) Receive(ctx context.Context, f func(ctx context.Context, m Message) error) error {
+	return s.s.Receive(ctx, func(ctx context.Context, pm *pubsub.Message) {
+		if err := f(ctx, Message{Data: pm.Data, Attributes: pm.Attributes, OrderingKey: pm.OrderingKey}); err != nil {
+			pm.Nack()
+			return
+		}
+		pm.Ack()
+	})
+}
+
+// FromSubscription adapts the Pub/Sub subscription to the Subscription. The
+// concurrency is configured with its ReceiveSettings.
+func FromSubscription(s *pubsub.Subscription) Subscription {
+	return gcpSubscription{s}
+}
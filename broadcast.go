@@ -0,0 +1,71 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sort"
+)
+
+// DispatchAll dispatches the event under each of given names in order,
+// e.g. to notify the listeners of both the old and the new event name.
+// Each name gets its own copy of the event, with its own ID if the event
+// has one, so the copies are neither deduplicated nor correlated as the
+// same event. Returns the copies after all listeners do their jobs, in the
+// order of the names.
+func (d *EventDispatcher) DispatchAll(names []string, e Event) []Event {
+	events := make([]Event, 0, len(names))
+	for _, n := range names {
+		ne, _ := tryDispatch(d, n, copyEvent(e), nil)
+		events = append(events, ne)
+	}
+
+	return events
+}
+
+// Broadcast delivers the event to every registered listener regardless of
+// the name it has been registered for, e.g. the system wide shutdown
+// notification. The event passes the global filters and the filters of its
+// name first. Listeners registered for many names are called once per
+// registration, by phase and in the order of registration, followed by the
+// listeners of all events. Returns the number of listeners called.
+func (d *EventDispatcher) Broadcast(e Event) int {
+	e, _, ok := filter(d, e.Name(), e)
+	if ok == false {
+		return 0
+	}
+
+	type call struct {
+		n  string
+		le listenerEntry
+	}
+	var calls []call
+	rlockShards(d)
+	for _, s := range d.shards {
+		for n, listeners := range s.listeners {
+			for _, le := range listeners {
+				calls = append(calls, call{n, le})
+			}
+		}
+	}
+	runlockShards(d)
+	sort.Slice(calls, func(i, j int) bool {
+		if calls[i].le.phase != calls[j].le.phase {
+			return calls[i].le.phase < calls[j].le.phase
+		}
+		return calls[i].le.id < calls[j].le.id
+	})
+
+	d.RWMutex.RLock()
+	mws := d.middlewares
+	d.RWMutex.RUnlock()
+	var c int
+	for _, call := range calls {
+		if e.IsPropagationStopped() && call.le.phase != PhasePost {
+			continue
+		}
+		c++
+		invoke(d, mws, ListenerCall{call.n, call.le.id}, call.le.l, e)
+	}
+
+	return c + dispatchAny(d, e.Name(), e, mws, nil)
+}
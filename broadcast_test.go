@@ -0,0 +1,61 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDispatchAll(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var names []string
+	d.On("user.created account.opened", func(e Event) {
+		names = append(names, e.Name())
+	})
+	d.DispatchAll([]string{"user.created", "account.opened", "other"}, NewParamsEvent("user.created"))
+	assert.Equal([]string{"user.created", "user.created"}, names, "The event should be dispatched under each name!")
+}
+
+func TestDispatchAllCopies(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(time.Hour, 10), WithCorrelation(0))
+	var c int
+	d.On("user.created account.opened", func(e Event) {
+		c++
+		e.(*ParamsEvent).SetParam(e.Name(), true)
+	})
+	e := NewParamsEvent("user.created")
+	e.SetCorrelation("id", "root", "")
+	events := d.DispatchAll([]string{"user.created", "account.opened"}, e)
+	assert.Equal(2, c, "The copies should not be deduplicated!")
+	assert.Equal(2, len(events), "A copy should be returned for each name!")
+	first, second := events[0].(*ParamsEvent), events[1].(*ParamsEvent)
+	assert.NotEqual(first.ID(), second.ID(), "Each copy should get its own ID!")
+	assert.Equal("root", second.CorrelationID(), "The copies should keep the correlation!")
+	assert.False(e.HasParam("user.created"), "The original event should not be changed!")
+}
+
+func TestBroadcast(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	d.On("a", func(e Event) {
+		got = append(got, "a")
+	})
+	d.On("b", func(e Event) {
+		got = append(got, "b")
+	})
+	d.OnPhase("c", PhasePre, func(e Event) {
+		got = append(got, "c")
+	})
+	assert.Equal(3, d.Broadcast(NewParamsEvent("shutdown")), "All listeners should be called!")
+	assert.Equal([]string{"c", "a", "b"}, got, "The listeners should be called by phase and registration order!")
+
+	d.AddFilterOn("shutdown", func(e Event) (Event, bool) {
+		return e, false
+	})
+	assert.Equal(0, d.Broadcast(NewParamsEvent("shutdown")), "The filtered event should not be broadcast!")
+}
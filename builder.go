@@ -0,0 +1,122 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// ImmutableEvent is the event built with the EventBuilder. Its name,
+// source and params cannot be changed once built, so it may be shared by
+// the listeners safely. Only its propagation may be stopped.
+type ImmutableEvent struct {
+	name                 string
+	source               string
+	params               map[string]interface{}
+	isPropagationStopped bool
+}
+
+// Name returns the name of the event
+func (event *ImmutableEvent) Name() string {
+	return event.name
+}
+
+// Source returns the name of the component the event comes from, e.g.
+// "billing", empty if not set
+func (event *ImmutableEvent) Source() string {
+	return event.source
+}
+
+// IsPropagationStopped informs weather the event should
+// be further propagated or not
+func (event *ImmutableEvent) IsPropagationStopped() bool {
+	return event.isPropagationStopped
+}
+
+// StopPropagation sets a flag that make the event no longer
+// propagate.
+func (event *ImmutableEvent) StopPropagation() {
+	event.isPropagationStopped = true
+}
+
+// HasParam defines if a param with given key exists
+func (event *ImmutableEvent) HasParam(k string) bool {
+	_, ok := event.params[k]
+	return ok
+}
+
+// GetParam returns a parameter value for given key and true, or nil and
+// false if the param does not exist
+func (event *ImmutableEvent) GetParam(k string) (interface{}, bool) {
+	v, ok := event.params[k]
+	return v, ok
+}
+
+// Params returns a copy of all params of the event
+func (event *ImmutableEvent) Params() map[string]interface{} {
+	params := make(map[string]interface{}, len(event.params))
+	for k, v := range event.params {
+		params[k] = v
+	}
+	return params
+}
+
+// EventBuilder builds the ImmutableEvent
+type EventBuilder struct {
+	name   string
+	source string
+	params map[string]interface{}
+	schema *Schema
+}
+
+// Param sets the param k to v. Returns this builder instance
+func (b *EventBuilder) Param(k string, v interface{}) *EventBuilder {
+	b.params[k] = v
+	return b
+}
+
+// Source sets the name of the component the event comes from. Returns this
+// builder instance
+func (b *EventBuilder) Source(s string) *EventBuilder {
+	b.source = s
+	return b
+}
+
+// Schema makes Build validate the params against the schema s. Returns this
+// builder instance
+func (b *EventBuilder) Schema(s *Schema) *EventBuilder {
+	b.schema = s
+	return b
+}
+
+// Build returns the event. Returns the RegistrationError with
+// ErrEmptyName if the name is empty and the ValidationError if the params
+// do not match the schema.
+func (b *EventBuilder) Build() (*ImmutableEvent, error) {
+	e := &ImmutableEvent{name: b.name, source: b.source, params: make(map[string]interface{}, len(b.params))}
+	for k, v := range b.params {
+		e.params[k] = v
+	}
+	if len(getNames(e.name)) == 0 {
+		return nil, &RegistrationError{e.name, ErrEmptyName}
+	}
+	if b.schema != nil {
+		if err := b.schema.Validate(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// MustBuild returns the event like Build does, panicking on the error. Meant
+// for the events built from constants.
+func (b *EventBuilder) MustBuild() *ImmutableEvent {
+	e, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// NewEvent creates the builder of the event with the name n
+func NewEvent(n string) *EventBuilder {
+	return &EventBuilder{name: n, params: make(map[string]interface{})}
+}
@@ -0,0 +1,47 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestEventBuilder(t *testing.T) {
+	assert := assert.New(t)
+	b := NewEvent("invoice.paid").Param("id", 12).Source("billing")
+	e, err := b.Build()
+	assert.Nil(err, "The event should be built!")
+	assert.Equal("invoice.paid", e.Name())
+	assert.Equal("billing", e.Source())
+	v, ok := e.GetParam("id")
+	assert.True(ok)
+	assert.Equal(12, v)
+
+	b.Param("id", 13)
+	e.Params()["id"] = 14
+	v, _ = e.GetParam("id")
+	assert.Equal(12, v, "The built event should not change!")
+
+	var got Event
+	d := NewDispatcher()
+	d.On("invoice.paid", func(e Event) {
+		got = e
+	})
+	d.Dispatch(e)
+	assert.Same(e, got, "The built event should be dispatched!")
+}
+
+func TestEventBuilderValidation(t *testing.T) {
+	assert := assert.New(t)
+	_, err := NewEvent(" ").Build()
+	assert.ErrorIs(err, ErrEmptyName, "The empty name should be rejected!")
+	schema := NewSchema().Require("id", reflect.TypeOf(0))
+	_, err = NewEvent("invoice.paid").Param("id", "12").Schema(schema).Build()
+	var ve *ValidationError
+	assert.ErrorAs(err, &ve, "The params should be validated against the schema!")
+	assert.Panics(func() {
+		NewEvent("").MustBuild()
+	}, "MustBuild should panic on the error!")
+}
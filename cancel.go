@@ -0,0 +1,70 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCanceled is wrapped by the error returned by DispatchCancelable when
+// a listener canceled the event
+var ErrCanceled = errors.New("eventdispatcher: event canceled")
+
+// Cancelable is the event the listeners may veto, e.g. "before save" event
+// canceling the operation the emitter was about to perform
+type Cancelable interface {
+	Event
+
+	// Cancel vetoes the event for given reason and stops its propagation
+	Cancel(reason string)
+
+	// IsCanceled informs whether the event has been canceled
+	IsCanceled() bool
+
+	// CancelReason returns the reason the event has been canceled for
+	CancelReason() string
+}
+
+// CancelableEvent is the default implementation of the Cancelable
+type CancelableEvent struct {
+	*ParamsEvent
+	canceled bool
+	reason   string
+}
+
+// Cancel vetoes the event for given reason and stops its propagation
+func (event *CancelableEvent) Cancel(reason string) {
+	event.canceled = true
+	event.reason = reason
+	event.StopPropagation()
+}
+
+// IsCanceled informs whether the event has been canceled
+func (event *CancelableEvent) IsCanceled() bool {
+	return event.canceled
+}
+
+// CancelReason returns the reason the event has been canceled for
+func (event *CancelableEvent) CancelReason() string {
+	return event.reason
+}
+
+// DispatchCancelable dispatches the cancelable event. Returns the error
+// wrapping ErrCanceled along with the reason if any listener canceled it or
+// the error of TryDispatch if it has not been dispatched.
+func (d *EventDispatcher) DispatchCancelable(e Cancelable) error {
+	if _, err := d.TryDispatch(e); err != nil {
+		return err
+	}
+	if e.IsCanceled() {
+		return fmt.Errorf("%w: %s", ErrCanceled, e.CancelReason())
+	}
+
+	return nil
+}
+
+// NewCancelableEvent creates the cancelable event with given name
+func NewCancelableEvent(n string) *CancelableEvent {
+	return &CancelableEvent{ParamsEvent: NewParamsEvent(n)}
+}
@@ -0,0 +1,32 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDispatchCancelable(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.On("before.save", func(e Event) {
+		if v, _ := e.(*CancelableEvent).GetParam("readonly"); v == true {
+			e.(Cancelable).Cancel("record is read only")
+		}
+	})
+	d.On("before.save", func(e Event) {
+		c++
+	})
+	assert.Nil(d.DispatchCancelable(NewCancelableEvent("before.save")), "The event should not be canceled!")
+
+	e := NewCancelableEvent("before.save")
+	e.SetParam("readonly", true)
+	err := d.DispatchCancelable(e)
+	assert.True(errors.Is(err, ErrCanceled), "The veto should be returned!")
+	assert.Contains(err.Error(), "record is read only", "The reason should be returned!")
+	assert.True(e.IsCanceled())
+	assert.Equal(1, c, "The canceled event should not propagate!")
+}
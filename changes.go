@@ -0,0 +1,173 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// ChangeKind is the kind of the change of the event param
+type ChangeKind int
+
+const (
+	// ParamAdded is the change of the param missing before
+	ParamAdded ChangeKind = iota
+
+	// ParamModified is the change of the existing param
+	ParamModified
+
+	// ParamRemoved is the removal of the existing param
+	ParamRemoved
+)
+
+// String returns the name of the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case ParamAdded:
+		return "added"
+	case ParamModified:
+		return "modified"
+	case ParamRemoved:
+		return "removed"
+	}
+
+	return "unknown"
+}
+
+// ParamChange describes the change of the event param since the tracking
+// started. Old is nil for the added params, New for the removed ones.
+type ParamChange struct {
+	Key  string
+	Kind ChangeKind
+	Old  interface{}
+	New  interface{}
+}
+
+// changeset holds the values of the changed params from before their first
+// change, in the order of the changes
+type changeset struct {
+	keys []string
+	old  map[string]trackedParam
+}
+
+type trackedParam struct {
+	v  interface{}
+	ok bool
+}
+
+// record remembers the value of the param k unless it changed before
+func (c *changeset) record(params map[string]interface{}, k string) {
+	v, ok := params[k]
+	c.remember(k, v, ok)
+}
+
+// pending informs whether the value of the param k is still to be
+// remembered
+func (c *changeset) pending(k string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.old[k]
+
+	return ok == false
+}
+
+// remember remembers the value v of the param k, ok is false if the param
+// was missing, unless it changed before
+func (c *changeset) remember(k string, v interface{}, ok bool) {
+	if c.pending(k) == false {
+		return
+	}
+	c.old[k] = trackedParam{v, ok}
+	c.keys = append(c.keys, k)
+}
+
+// TrackChanges starts recording the changes of the params, discarding the
+// ones recorded before. Returns this event instance
+func (event *ParamsEvent) TrackChanges() *ParamsEvent {
+	event.changes = &changeset{old: make(map[string]trackedParam)}
+	return event
+}
+
+// TracksChanges informs whether the changes of the params are recorded
+func (event *ParamsEvent) TracksChanges() bool {
+	return event.changes != nil
+}
+
+// Changes returns the changes of the params since TrackChanges, in the
+// order the params were first changed. The param set and then removed
+// again is not reported. The nested values modified with SetPath are
+// reported as the modification of the param holding them, the old value
+// being its copy from before the first change.
+func (event *ParamsEvent) Changes() []ParamChange {
+	if event.changes == nil {
+		return nil
+	}
+	var changes []ParamChange
+	for _, k := range event.changes.keys {
+		old := event.changes.old[k]
+		v, ok := event.params[k]
+		switch {
+		case old.ok == false && ok:
+			changes = append(changes, ParamChange{k, ParamAdded, nil, v})
+		case old.ok && ok == false:
+			changes = append(changes, ParamChange{k, ParamRemoved, old.v, nil})
+		case old.ok && ok:
+			changes = append(changes, ParamChange{k, ParamModified, old.v, v})
+		}
+	}
+
+	return changes
+}
+
+// TrackChanges starts recording the changes of the params.
+// Returns this event instance
+func (event *ConcurrentParamsEvent) TrackChanges() *ConcurrentParamsEvent {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.TrackChanges()
+	return event
+}
+
+// TracksChanges informs whether the changes of the params are recorded
+func (event *ConcurrentParamsEvent) TracksChanges() bool {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.TracksChanges()
+}
+
+// Changes returns the changes of the params since TrackChanges
+func (event *ConcurrentParamsEvent) Changes() []ParamChange {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.Changes()
+}
+
+// WithChangeTracking makes the dispatcher start tracking the changes of the
+// params of the dispatched ParamsEvent and ConcurrentParamsEvent, unless
+// tracked already, so the emitter may inspect with Changes how the
+// listeners enriched the event
+func WithChangeTracking() Option {
+	return func(d *EventDispatcher) {
+		d.changeTracking = true
+	}
+}
+
+// trackChanges starts tracking the changes of the params of the event e
+// if the dispatcher is configured to
+func trackChanges(d *EventDispatcher, e Event) {
+	if d.changeTracking == false {
+		return
+	}
+	switch ev := e.(type) {
+	case *ParamsEvent:
+		if ev.TracksChanges() == false {
+			ev.TrackChanges()
+		}
+	case *ConcurrentParamsEvent:
+		ev.Lock()
+		defer ev.Unlock()
+		if ev.event.TracksChanges() == false {
+			ev.event.TrackChanges()
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTrackChanges(t *testing.T) {
+	assert := assert.New(t)
+	e := NewParamsEvent(TestEventName)
+	e.SetParam("kept", 1).SetParam("modified", 1).SetParam("removed", 1)
+	assert.Nil(e.Changes(), "The changes should not be tracked by default!")
+
+	e.TrackChanges()
+	e.SetParam("modified", 2).SetParam("modified", 3)
+	e.SetParam("added", 1)
+	e.RemoveParam("removed")
+	e.SetParam("temporary", 1).RemoveParam("temporary")
+	assert.Nil(e.SetPath("nested.key", 1))
+
+	assert.Equal([]ParamChange{
+		{"modified", ParamModified, 1, 3},
+		{"added", ParamAdded, nil, 1},
+		{"removed", ParamRemoved, 1, nil},
+		{"nested", ParamAdded, nil, map[string]interface{}{"key": 1}},
+	}, e.Changes(), "Invalid changeset!")
+	assert.Equal("modified", ParamModified.String())
+
+	assert.False(e.Clone().TracksChanges(), "The clone should not be tracked!")
+	e.Reset()
+	assert.False(e.TracksChanges(), "Reset should stop tracking!")
+}
+
+func TestTrackNestedChanges(t *testing.T) {
+	assert := assert.New(t)
+	type address struct {
+		City string
+	}
+	e := NewParamsEvent(TestEventName)
+	e.SetParam("user", map[string]interface{}{"name": "john", "tags": []string{"new"}})
+	e.SetParam("address", &address{"Berlin"})
+	e.TrackChanges()
+	assert.Nil(e.SetPath("user.name", "jane"))
+	assert.Nil(e.SetPath("user.age", 30))
+	assert.Nil(e.SetPath("address.City", "Paris"))
+
+	assert.Equal([]ParamChange{
+		{"user", ParamModified, map[string]interface{}{"name": "john", "tags": []string{"new"}}, map[string]interface{}{"name": "jane", "tags": []string{"new"}, "age": 30}},
+		{"address", ParamModified, &address{"Berlin"}, &address{"Paris"}},
+	}, e.Changes(), "The old values should be copied before the nested changes!")
+}
+
+func TestWithChangeTracking(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithChangeTracking())
+	d.On(TestEventName, func(e Event) {
+		e.(*ParamsEvent).SetParam("enriched", true)
+	})
+
+	e := NewParamsEvent(TestEventName).SetParam("source", "test")
+	d.Dispatch(e)
+	assert.Equal([]ParamChange{{"enriched", ParamAdded, nil, true}}, e.Changes(), "The changes of the listeners should be tracked!")
+
+	d.On("concurrent", func(e Event) {
+		e.(*ConcurrentParamsEvent).SetParam("enriched", true)
+	})
+	ce := NewConcurrentParamsEvent("concurrent")
+	d.Dispatch(ce)
+	assert.Equal(1, len(ce.Changes()), "The changes of the concurrent event should be tracked!")
+
+	e = NewParamsEvent(TestEventName)
+	NewDispatcher().Dispatch(e)
+	assert.False(e.TracksChanges(), "The changes should be tracked only if configured!")
+}
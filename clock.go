@@ -0,0 +1,53 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"time"
+)
+
+// Clock is the source of time used by the dispatcher, replaceable in tests
+type Clock interface {
+
+	// Now returns the current time
+	Now() time.Time
+
+	// Sleep pauses the current goroutine for at least the duration d
+	Sleep(d time.Duration)
+
+	// AfterFunc calls f in its own goroutine after the duration d
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is the timer created by Clock.AfterFunc
+type Timer interface {
+
+	// Stop prevents the timer from firing. Returns false if the timer has
+	// already fired or been stopped.
+	Stop() bool
+}
+
+// SystemClock is the Clock using the time package
+type SystemClock struct{}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep pauses the current goroutine for at least the duration d
+func (SystemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// AfterFunc calls f in its own goroutine after the duration d
+func (SystemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// WithClock makes the dispatcher use the clock c instead of the system one
+func WithClock(c Clock) Option {
+	return func(d *EventDispatcher) {
+		d.clock = c
+	}
+}
@@ -0,0 +1,48 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync/atomic"
+)
+
+// Clone creates the new dispatcher with the options opts and the copies of
+// the listeners registered in this one, including the listeners of all
+// events, e.g. to seed the per test or per request dispatchers from the
+// template. The registrations keep their identifiers and the once
+// listeners not called yet are called once per dispatcher. The listeners
+// bound to this dispatcher by its helpers, like OnRetry or Join, still act
+// on this one. The configuration, like the filters or the middlewares, is
+// not copied.
+func (d *EventDispatcher) Clone(opts ...Option) *EventDispatcher {
+	c := NewDispatcher(opts...)
+
+	rlockShards(d)
+	atomic.StoreUint64(&c.lastID, atomic.LoadUint64(&d.lastID))
+	copied := make(map[string]listenersCollection)
+	for _, s := range d.shards {
+		for n, listeners := range s.listeners {
+			for _, le := range listeners {
+				if le.once != nil {
+					le.l = executeRemove(c, n, le.id, le.once)
+				}
+				if le.group != nil {
+					le.l = groupCall(c, le.group)
+				}
+				copied[n] = append(copied[n], le)
+			}
+		}
+	}
+	runlockShards(d)
+
+	for n, listeners := range copied {
+		s := shardOf(c, n)
+		s.listeners[n] = listeners
+	}
+	if p := d.anyListeners.Load(); p != nil {
+		listeners := append(listenersCollection(nil), *p...)
+		c.anyListeners.Store(&listeners)
+	}
+
+	return c
+}
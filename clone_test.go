@@ -0,0 +1,35 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDispatcherClone(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	d.On("a", func(e Event) {
+		got = append(got, "a")
+	})
+	d.Once("b", func(e Event) {
+		got = append(got, "b")
+	})
+	d.OnAny(func(e Event) {
+		got = append(got, "any")
+	})
+
+	c := d.Clone()
+	assert.Equal(d.Order("a"), c.Order("a"), "The registrations should keep their identifiers!")
+	c.Dispatch(NewParamsEvent("b"))
+	c.Dispatch(NewParamsEvent("b"))
+	assert.Equal([]string{"b", "any", "any"}, got, "The once listener should be called once in the clone!")
+	assert.True(d.HasListeners("b"), "The once listener of the original should be kept!")
+
+	c.On("a", func(e Event) {})
+	assert.Equal(2, c.ListenerCount("a"), "The clone should get the new listener!")
+	assert.Equal(1, d.ListenerCount("a"), "The original should not get the listener of the clone!")
+	assert.NotContains(d.Order("a"), c.Order("a")[1], "The new identifiers should not collide!")
+}
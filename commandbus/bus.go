@@ -0,0 +1,124 @@
+// Package commandbus contains the command bus built on the event dispatcher
+// request handling: each command name has exactly one handler returning
+// the result, with middlewares wrapping all handler calls.
+package commandbus
+
+import (
+	"errors"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+)
+
+// ErrResultType is returned by Execute when the handler result is not of
+// the expected type
+var ErrResultType = errors.New("commandbus: unexpected result type")
+
+// Handler handles the command and returns its result
+type Handler func(cmd ed.Event) (interface{}, error)
+
+// Middleware wraps each handler call. It must call next to proceed with
+// the handling.
+type Middleware func(cmd ed.Event, next Handler) (interface{}, error)
+
+// Bus dispatches the commands to their handlers
+type Bus struct {
+	mu          sync.RWMutex
+	d           *ed.EventDispatcher
+	middlewares []Middleware
+}
+
+// RegisterHandler registers the handler h of the commands with given name.
+// Returns ed.ErrHandlerExists wrapped if the name already has a handler.
+func (b *Bus) RegisterHandler(name string, h Handler) error {
+	return b.d.Handle(name, func(cmd ed.Event) (ed.Response, error) {
+		b.mu.RLock()
+		mws := b.middlewares
+		b.mu.RUnlock()
+
+		return chain(mws, h)(cmd)
+	})
+}
+
+// RemoveHandler removes the handler of the commands with given name
+func (b *Bus) RemoveHandler(name string) {
+	b.d.RemoveHandler(name)
+}
+
+// Use registers the middleware wrapping the handler calls, inside the
+// already registered ones
+func (b *Bus) Use(m Middleware) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.middlewares = append(b.middlewares[:len(b.middlewares):len(b.middlewares)], m)
+}
+
+// Execute passes the command to its handler and returns the result.
+// Returns ed.ErrNoHandler wrapped if the command has no handler.
+func (b *Bus) Execute(cmd ed.Event) (interface{}, error) {
+	return b.d.Request(cmd)
+}
+
+// Dispatcher returns the dispatcher the bus is built on
+func (b *Bus) Dispatcher() *ed.EventDispatcher {
+	return b.d
+}
+
+// chain wraps the handler h with the middlewares mws, the first one being
+// the outermost
+func chain(mws []Middleware, h Handler) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		m, next := mws[i], h
+		h = func(cmd ed.Event) (interface{}, error) {
+			return m(cmd, next)
+		}
+	}
+
+	return h
+}
+
+// Execute passes the command to its handler on the bus b and returns the
+// result of type R. Returns ErrResultType wrapped if the handler returned
+// the result of other type.
+func Execute[R any](b *Bus, cmd ed.Event) (R, error) {
+	var r R
+	res, err := b.Execute(cmd)
+	if err != nil {
+		return r, err
+	}
+	if res == nil {
+		return r, nil
+	}
+	r, ok := res.(R)
+	if ok == false {
+		return r, fmt.Errorf("%w: %T for command %s", ErrResultType, res, cmd.Name())
+	}
+
+	return r, nil
+}
+
+// HandlerFunc adapts the typed function f to the Handler. The command not
+// of type C makes the handler return an error.
+func HandlerFunc[C ed.Event, R any](f func(cmd C) (R, error)) Handler {
+	return func(cmd ed.Event) (interface{}, error) {
+		c, ok := cmd.(C)
+		if ok == false {
+			return nil, fmt.Errorf("commandbus: unexpected command type %T for command %s", cmd, cmd.Name())
+		}
+
+		return f(c)
+	}
+}
+
+// New creates the command bus on a new dispatcher configured with given
+// options
+func New(opts ...ed.Option) *Bus {
+	return NewWithDispatcher(ed.NewDispatcher(opts...))
+}
+
+// NewWithDispatcher creates the command bus on the dispatcher d, so the
+// commands share its filters and schemas
+func NewWithDispatcher(d *ed.EventDispatcher) *Bus {
+	return &Bus{d: d}
+}
@@ -0,0 +1,51 @@
+package commandbus
+
+import (
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type createUser struct {
+	*ed.ParamsEvent
+	email string
+}
+
+func newCreateUser(email string) *createUser {
+	return &createUser{ed.NewParamsEvent("user.create"), email}
+}
+
+func TestBus(t *testing.T) {
+	assert := assert.New(t)
+	b := New()
+	var calls []string
+	b.Use(func(cmd ed.Event, next Handler) (interface{}, error) {
+		calls = append(calls, "outer")
+		return next(cmd)
+	})
+	b.Use(func(cmd ed.Event, next Handler) (interface{}, error) {
+		calls = append(calls, "inner")
+		return next(cmd)
+	})
+	err := b.RegisterHandler("user.create", HandlerFunc(func(cmd *createUser) (int, error) {
+		calls = append(calls, "handler")
+		return 42, nil
+	}))
+	assert.Nil(err)
+	assert.True(errors.Is(b.RegisterHandler("user.create", nil), ed.ErrHandlerExists), "Only one handler per command should be allowed!")
+
+	id, err := Execute[int](b, newCreateUser("john@example.com"))
+	assert.Nil(err)
+	assert.Equal(42, id, "The typed result should be returned!")
+	assert.Equal([]string{"outer", "inner", "handler"}, calls, "Invalid middlewares order!")
+
+	_, err = Execute[string](b, newCreateUser("john@example.com"))
+	assert.True(errors.Is(err, ErrResultType), "The result type mismatch should be reported!")
+	_, err = b.Execute(ed.NewParamsEvent("user.create"))
+	assert.NotNil(err, "The command type mismatch should be reported!")
+
+	b.RemoveHandler("user.create")
+	_, err = b.Execute(newCreateUser("john@example.com"))
+	assert.True(errors.Is(err, ed.ErrNoHandler))
+}
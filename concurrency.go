@@ -0,0 +1,65 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// concurrencyLimiter runs at most max invocations of the listener at once,
+// queueing the remaining ones in the dispatch order
+type concurrencyLimiter struct {
+	sync.Mutex
+	max      int
+	inFlight int
+	queue    []Event
+}
+
+// OnAsync registers a listener for given event name called asynchronously,
+// with at most max invocations in flight at once (unlimited if max is less
+// than 1). Invocations beyond the limit are queued and run in the dispatch
+// order as the running ones finish. Use Drain to wait for them.
+func (d *EventDispatcher) OnAsync(n string, l Listener, max int) {
+	for _, name := range getNames(n) {
+		cl := &concurrencyLimiter{max: max}
+		on(d, name, func(e Event) {
+			runLimited(d, cl, l, e)
+		})
+	}
+}
+
+// runLimited starts the invocation of the listener l if the limit allows,
+// otherwise queues it
+func runLimited(d *EventDispatcher, cl *concurrencyLimiter, l Listener, e Event) {
+	cl.Lock()
+	if cl.max > 0 && cl.inFlight >= cl.max {
+		cl.queue = append(cl.queue, e)
+		cl.Unlock()
+		return
+	}
+	cl.inFlight++
+	cl.Unlock()
+
+	runQueued(d, cl, l, e)
+}
+
+// runQueued invokes the listener l in the background and, once done, runs
+// the next queued invocation in the same slot
+func runQueued(d *EventDispatcher, cl *concurrencyLimiter, l Listener, e Event) {
+	async(d, func() {
+		call(d, l, e)
+
+		cl.Lock()
+		if len(cl.queue) == 0 {
+			cl.inFlight--
+			cl.Unlock()
+			return
+		}
+		next := cl.queue[0]
+		cl.queue = cl.queue[1:]
+		cl.Unlock()
+
+		// Started before this invocation is done, so Drain keeps waiting
+		runQueued(d, cl, l, next)
+	})
+}
@@ -0,0 +1,52 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestOnAsyncLimit(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var mu sync.Mutex
+	var inFlight, max, c int
+	release := make(chan struct{})
+	d.OnAsync(TestEventName, func(e Event) {
+		mu.Lock()
+		inFlight++
+		if inFlight > max {
+			max = inFlight
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		inFlight--
+		c++
+		mu.Unlock()
+	}, 2)
+	for i := 0; i < 10; i++ {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	}
+	close(release)
+	d.Drain()
+	assert.Equal(10, c, "All queued invocations should be run!")
+	assert.True(max <= 2, "At most 2 invocations should be in flight!")
+}
+
+func TestOnAsyncOrder(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var order []interface{}
+	d.OnAsync(TestEventName, func(e Event) {
+		v, _ := e.(*ParamsEvent).GetParam("i")
+		order = append(order, v)
+	}, 1)
+	for i := 0; i < 5; i++ {
+		d.Dispatch(NewParamsEvent(TestEventName).SetParam("i", i))
+	}
+	d.Drain()
+	assert.Equal([]interface{}{0, 1, 2, 3, 4}, order, "A single slot should keep the dispatch order!")
+}
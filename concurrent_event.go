@@ -0,0 +1,172 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// ConcurrentParamsEvent is the ParamsEvent safe for concurrent use, e.g.
+// by listeners called asynchronously reading and writing the params at the
+// same time
+type ConcurrentParamsEvent struct {
+	sync.RWMutex
+	event *ParamsEvent
+}
+
+// Name returns the name of the event
+func (event *ConcurrentParamsEvent) Name() string {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.Name()
+}
+
+// IsPropagationStopped informs weather the event should
+// be further propagated or not
+func (event *ConcurrentParamsEvent) IsPropagationStopped() bool {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.IsPropagationStopped()
+}
+
+// StopPropagation sets a flag that make the event no longer
+// propagate.
+func (event *ConcurrentParamsEvent) StopPropagation() {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.StopPropagation()
+}
+
+// SetParam registers a parameter for the event.
+// Returns this event instance
+func (event *ConcurrentParamsEvent) SetParam(k string, v interface{}) *ConcurrentParamsEvent {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.SetParam(k, v)
+	return event
+}
+
+// UpdateParam atomically replaces the param with given key with the value
+// returned by f, which receives the current value and whether it existed.
+// Returns this event instance
+func (event *ConcurrentParamsEvent) UpdateParam(k string, f func(v interface{}, ok bool) interface{}) *ConcurrentParamsEvent {
+	event.Lock()
+	defer event.Unlock()
+
+	v, ok := event.event.GetParam(k)
+	event.event.SetParam(k, f(v, ok))
+	return event
+}
+
+// RemoveParam deletes a param with given key. Does nothing, if
+// the param does not exst. Returns this event instance
+func (event *ConcurrentParamsEvent) RemoveParam(k string) *ConcurrentParamsEvent {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.RemoveParam(k)
+	return event
+}
+
+// HasParam defines if a param with given key exists. Returns a boolean value
+func (event *ConcurrentParamsEvent) HasParam(k string) bool {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.HasParam(k)
+}
+
+// GetParam returns a parameter value for given key. If the param does not exist,
+// returns an empty string. Second value returned contains boolean value that says
+// if the param existed.
+func (event *ConcurrentParamsEvent) GetParam(k string) (value interface{}, ok bool) {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.GetParam(k)
+}
+
+// Params returns a copy of all params of the event
+func (event *ConcurrentParamsEvent) Params() map[string]interface{} {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.Params()
+}
+
+// ID returns the unique identifier of the event
+func (event *ConcurrentParamsEvent) ID() string {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.ID()
+}
+
+// CorrelationID returns the identifier shared by all events of the cascade
+// started by the same root event
+func (event *ConcurrentParamsEvent) CorrelationID() string {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.CorrelationID()
+}
+
+// CausationID returns the identifier of the event that caused this one
+func (event *ConcurrentParamsEvent) CausationID() string {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.CausationID()
+}
+
+// SetCorrelation sets the correlation metadata of the event
+func (event *ConcurrentParamsEvent) SetCorrelation(id string, correlationID string, causationID string) {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.SetCorrelation(id, correlationID, causationID)
+}
+
+// Sequence returns the sequence number stamped by DispatchOrdered
+func (event *ConcurrentParamsEvent) Sequence() uint64 {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.Sequence()
+}
+
+// SetSequence sets the sequence number of the event
+func (event *ConcurrentParamsEvent) SetSequence(seq uint64) {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.SetSequence(seq)
+}
+
+// Priority returns the priority of the event dispatched asynchronously
+func (event *ConcurrentParamsEvent) Priority() Priority {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.Priority()
+}
+
+// SetPriority sets the priority of the event dispatched asynchronously.
+// Returns this event instance
+func (event *ConcurrentParamsEvent) SetPriority(p Priority) *ConcurrentParamsEvent {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.SetPriority(p)
+	return event
+}
+
+// NewConcurrentParamsEvent is a factory for creating the event safe for
+// concurrent use
+func NewConcurrentParamsEvent(n string) *ConcurrentParamsEvent {
+	return &ConcurrentParamsEvent{event: NewParamsEvent(n)}
+}
@@ -0,0 +1,44 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConcurrentParamsEvent(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	for i := 0; i < 10; i++ {
+		k := fmt.Sprintf("key_%d", i)
+		d.On(TestEventName, func(e Event) {
+			ce := e.(*ConcurrentParamsEvent)
+			ce.SetParam(k, true)
+			ce.UpdateParam("counter", func(v interface{}, ok bool) interface{} {
+				if ok == false {
+					return 1
+				}
+				return v.(int) + 1
+			})
+			ce.HasParam("key_0")
+		})
+	}
+	e := NewConcurrentParamsEvent(TestEventName)
+	for i := 0; i < 10; i++ {
+		d.DispatchAsync(e)
+	}
+	d.Drain()
+
+	v, ok := e.GetParam("counter")
+	assert.True(ok)
+	assert.Equal(100, v, "All concurrent updates should be applied!")
+	assert.Equal(11, len(e.Params()), "All params should be set!")
+	e.RemoveParam("counter")
+	assert.False(e.HasParam("counter"))
+
+	var c Correlated = e
+	CausedBy(c, NewParamsEvent("cause"))
+	assert.NotEqual("", c.ID(), "The concurrent event should support correlation!")
+}
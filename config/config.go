@@ -0,0 +1,194 @@
+// Package config registers the event listeners declared in the YAML or
+// JSON configuration file, resolving the handler names against the
+// registry filled by the application at startup. The wiring may then be
+// changed without recompiling.
+//
+// Example configuration:
+//
+//	listeners:
+//	  - event: user.created
+//	    handler: send_welcome_email
+//	    priority: 10
+//	  - event: user.created
+//	    handler: index_user
+//	    async: true
+//	    max_in_flight: 4
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownHandler is returned when the configuration refers to the
+// handler missing in the registry
+var ErrUnknownHandler = errors.New("config: unknown handler")
+
+// ErrInvalidBinding is returned when the binding options are invalid
+var ErrInvalidBinding = errors.New("config: invalid binding")
+
+// Binding binds the handler to the event name
+type Binding struct {
+
+	// Event is the event name (may contain many space separated names)
+	Event string `yaml:"event" json:"event"`
+
+	// Handler is the name of the handler in the registry
+	Handler string `yaml:"handler" json:"handler"`
+
+	// Priority orders the bindings of the same event, the ones with the
+	// higher priority are registered, and so called, first
+	Priority int `yaml:"priority" json:"priority"`
+
+	// Once makes the handler be called only once
+	Once bool `yaml:"once" json:"once"`
+
+	// Async makes the handler be called asynchronously
+	Async bool `yaml:"async" json:"async"`
+
+	// MaxInFlight limits the concurrent calls of the async handler
+	MaxInFlight int `yaml:"max_in_flight" json:"max_in_flight"`
+
+	// Group is the name of the listener group the handler is added to
+	Group string `yaml:"group" json:"group"`
+}
+
+// Config is the listeners configuration
+type Config struct {
+	Listeners []Binding `yaml:"listeners" json:"listeners"`
+}
+
+// Registry maps the handler names to the listeners
+type Registry struct {
+	sync.RWMutex
+	handlers map[string]ed.Listener
+}
+
+// Register adds the listener l under given handler name, replacing the one
+// registered before
+func (r *Registry) Register(name string, l ed.Listener) *Registry {
+	r.Lock()
+	defer r.Unlock()
+
+	r.handlers[name] = l
+	return r
+}
+
+// Handler returns the listener registered under given name and false if
+// there is none
+func (r *Registry) Handler(name string) (ed.Listener, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	l, ok := r.handlers[name]
+	return l, ok
+}
+
+// Apply registers the listeners of the configuration in the dispatcher d.
+// All bindings are checked first, so nothing is registered if any of them
+// is invalid or refers to the unknown handler.
+func (c *Config) Apply(d *ed.EventDispatcher, r *Registry) error {
+	var problems []error
+	listeners := make([]ed.Listener, len(c.Listeners))
+	for i, b := range c.Listeners {
+		l, ok := r.Handler(b.Handler)
+		if ok == false {
+			problems = append(problems, fmt.Errorf("%w: %q", ErrUnknownHandler, b.Handler))
+		}
+		if err := check(b); err != nil {
+			problems = append(problems, err)
+		}
+		listeners[i] = l
+	}
+	if len(problems) != 0 {
+		return errors.Join(problems...)
+	}
+
+	// Stable, so the bindings of equal priority keep the file order
+	order := make([]int, len(c.Listeners))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.Listeners[order[i]].Priority > c.Listeners[order[j]].Priority
+	})
+	for _, i := range order {
+		bind(d, c.Listeners[i], listeners[i])
+	}
+
+	return nil
+}
+
+// check validates the options of the binding b
+func check(b Binding) error {
+	if strings.TrimSpace(b.Event) == "" {
+		return fmt.Errorf("%w: handler %q has no event", ErrInvalidBinding, b.Handler)
+	}
+	if b.Async && (b.Once || b.Group != "") {
+		return fmt.Errorf("%w: async handler %q can not be once nor grouped", ErrInvalidBinding, b.Handler)
+	}
+	if b.MaxInFlight != 0 && b.Async == false {
+		return fmt.Errorf("%w: max_in_flight of handler %q requires async", ErrInvalidBinding, b.Handler)
+	}
+
+	return nil
+}
+
+// bind registers the listener l according to the binding b
+func bind(d *ed.EventDispatcher, b Binding, l ed.Listener) {
+	switch {
+	case b.Async:
+		d.OnAsync(b.Event, l, b.MaxInFlight)
+	case b.Group != "" && b.Once:
+		d.Group(b.Group).Once(b.Event, l)
+	case b.Group != "":
+		d.Group(b.Group).On(b.Event, l)
+	case b.Once:
+		d.Once(b.Event, l)
+	default:
+		d.On(b.Event, l)
+	}
+}
+
+// Parse parses the configuration in given format, "yaml" or "json"
+func Parse(data []byte, format string) (*Config, error) {
+	c := &Config{}
+	var err error
+	switch format {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, c)
+	case "json":
+		err = json.Unmarshal(data, c)
+	default:
+		return nil, fmt.Errorf("config: unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	return c, nil
+}
+
+// Load reads the configuration file, the format is taken from its
+// extension
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	return Parse(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// NewRegistry creates an empty handler registry
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]ed.Listener)}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testYAML = `
+listeners:
+  - event: user.created
+    handler: audit
+  - event: user.created
+    handler: welcome
+    priority: 10
+  - event: user.deleted
+    handler: audit
+    once: true
+    group: auditing
+`
+
+func TestLoadAndApply(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "listeners.yaml")
+	assert.Nil(os.WriteFile(path, []byte(testYAML), 0o600))
+	c, err := Load(path)
+	assert.Nil(err)
+
+	var calls []string
+	r := NewRegistry().
+		Register("audit", func(e ed.Event) {
+			calls = append(calls, "audit "+e.Name())
+		}).
+		Register("welcome", func(e ed.Event) {
+			calls = append(calls, "welcome "+e.Name())
+		})
+	d := ed.NewDispatcher()
+	assert.Nil(c.Apply(d, r))
+
+	d.Dispatch(ed.NewParamsEvent("user.created"))
+	d.Dispatch(ed.NewParamsEvent("user.deleted"))
+	d.Dispatch(ed.NewParamsEvent("user.deleted"))
+	assert.Equal([]string{"welcome user.created", "audit user.created", "audit user.deleted"}, calls,
+		"The listeners should be registered by priority with their options!")
+}
+
+func TestParseJSON(t *testing.T) {
+	assert := assert.New(t)
+	c, err := Parse([]byte(`{"listeners": [{"event": "a", "handler": "h", "async": true, "max_in_flight": 4}]}`), "json")
+	assert.Nil(err)
+	assert.Equal([]Binding{{Event: "a", Handler: "h", Async: true, MaxInFlight: 4}}, c.Listeners)
+}
+
+func TestApplyInvalid(t *testing.T) {
+	assert := assert.New(t)
+	c := &Config{Listeners: []Binding{
+		{Event: "a", Handler: "missing"},
+		{Event: "a", Handler: "h", MaxInFlight: 2},
+		{Event: "b", Handler: "h"},
+	}}
+	d := ed.NewDispatcher()
+	err := c.Apply(d, NewRegistry().Register("h", func(e ed.Event) {}))
+	assert.True(errors.Is(err, ErrUnknownHandler), "Unknown handlers should be reported!")
+	assert.True(errors.Is(err, ErrInvalidBinding), "Invalid options should be reported!")
+	assert.False(d.HasListeners("b"), "Nothing should be registered if the configuration is invalid!")
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	_, err := Parse([]byte(""), "toml")
+	assert.NotNil(t, err, "Unsupported formats should be rejected!")
+}
@@ -0,0 +1,25 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+)
+
+// dispatcherKey is the context key of the dispatcher
+type dispatcherKey struct{}
+
+// ContextWithDispatcher returns the copy of the context ctx carrying the
+// dispatcher d, e.g. the request scoped one, so it travels with the context
+// instead of being looked up with GetDispatcher
+func ContextWithDispatcher(ctx context.Context, d *EventDispatcher) context.Context {
+	return context.WithValue(ctx, dispatcherKey{}, d)
+}
+
+// FromContext returns the dispatcher carried by the context ctx and false
+// if there is none
+func FromContext(ctx context.Context) (*EventDispatcher, bool) {
+	d, ok := ctx.Value(dispatcherKey{}).(*EventDispatcher)
+
+	return d, ok && d != nil
+}
@@ -0,0 +1,27 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	ctx := ContextWithDispatcher(context.Background(), d)
+	got, ok := FromContext(ctx)
+	assert.True(ok, "The dispatcher should be found!")
+	assert.Same(d, got, "The dispatcher carried by the context should be returned!")
+
+	inner := NewDispatcher()
+	got, _ = FromContext(ContextWithDispatcher(ctx, inner))
+	assert.Same(inner, got, "The innermost dispatcher should be returned!")
+
+	_, ok = FromContext(context.Background())
+	assert.False(ok, "No dispatcher should be found!")
+	_, ok = FromContext(ContextWithDispatcher(ctx, nil))
+	assert.False(ok, "The nil dispatcher should not be found!")
+}
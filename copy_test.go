@@ -0,0 +1,31 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeepCopy(t *testing.T) {
+	assert := assert.New(t)
+	type item struct {
+		Tags  []string
+		Attrs map[string]int
+	}
+	v := map[string]interface{}{
+		"items": []*item{{[]string{"a"}, map[string]int{"x": 1}}},
+		"array": [1][]int{{1}},
+		"nil":   nil,
+	}
+	c := deepCopy(v).(map[string]interface{})
+	assert.Equal(v, c, "The copy should equal the original!")
+
+	c["items"].([]*item)[0].Tags[0] = "changed"
+	c["items"].([]*item)[0].Attrs["x"] = 2
+	c["array"].([1][]int)[0][0] = 2
+	assert.Equal("a", v["items"].([]*item)[0].Tags[0], "The nested slices should be copied!")
+	assert.Equal(1, v["items"].([]*item)[0].Attrs["x"], "The nested maps should be copied!")
+	assert.Equal(1, v["array"].([1][]int)[0][0], "The arrays of slices should be copied!")
+	assert.Nil(deepCopy(nil), "The nil should be copied as nil!")
+}
@@ -0,0 +1,187 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// DefaultCorrelationHistorySize is the number of dispatched events the
+// dispatcher created WithCorrelation remembers for CausationChain
+const DefaultCorrelationHistorySize = 1024
+
+// Correlated is implemented by the events carrying the correlation and
+// causation identifiers linking the events of a cascade
+type Correlated interface {
+	Event
+
+	// ID returns the unique identifier of the event
+	ID() string
+
+	// CorrelationID returns the identifier shared by all events of the
+	// cascade started by the same root event
+	CorrelationID() string
+
+	// CausationID returns the identifier of the event that caused this one
+	CausationID() string
+
+	// SetCorrelation sets the correlation metadata of the event
+	SetCorrelation(id string, correlationID string, causationID string)
+}
+
+// correlationHistory remembers the recently dispatched correlated events
+// by their identifiers
+type correlationHistory struct {
+	sync.Mutex
+	size   int
+	events map[string]Correlated
+	order  []string
+}
+
+// causeKey is the context key of the cause event
+type causeKey struct{}
+
+// WithCorrelation makes the dispatcher assign identifiers to dispatched
+// Correlated events lacking them and remember the last size of them, so
+// the cascades can be inspected with CausationChain. The events are linked
+// explicitly, with CausedBy or by dispatching them with DispatchContext.
+// Zero size means DefaultCorrelationHistorySize.
+func WithCorrelation(size int) Option {
+	return func(d *EventDispatcher) {
+		if size <= 0 {
+			size = DefaultCorrelationHistorySize
+		}
+		d.correlation = &correlationHistory{size: size, events: make(map[string]Correlated)}
+	}
+}
+
+// CausedBy links the event e with the cause event it is dispatched in
+// reaction to, e.g. from the listener called asynchronously, which the
+// dispatcher created WithCorrelation cannot link on its own. The event
+// gets the correlation identifier of the cause and its identifier as the
+// causation one. Does nothing unless both events are Correlated. Returns
+// the event e.
+func CausedBy(e Event, cause Event) Event {
+	ce, ok := e.(Correlated)
+	if ok == false {
+		return e
+	}
+	cc, ok := cause.(Correlated)
+	if ok == false {
+		return e
+	}
+	assignID(cc)
+	id := ce.ID()
+	if id == "" {
+		id = NewEventID()
+	}
+	ce.SetCorrelation(id, cc.CorrelationID(), cc.ID())
+
+	return e
+}
+
+// ContextWithCause returns the copy of the context ctx carrying the event
+// cause, so the events dispatched with DispatchContext are caused by it. The
+// group listeners receive the context carrying the event they handle.
+func ContextWithCause(ctx context.Context, cause Event) context.Context {
+	return context.WithValue(ctx, causeKey{}, cause)
+}
+
+// CauseFromContext returns the cause event carried by the context ctx and
+// false if there is none
+func CauseFromContext(ctx context.Context) (Event, bool) {
+	e, ok := ctx.Value(causeKey{}).(Event)
+
+	return e, ok && e != nil
+}
+
+// DispatchContext dispatches the event like Dispatch does, linking it with
+// CausedBy to the cause event carried by the context ctx, unless the event
+// has the correlation identifiers already
+func (d *EventDispatcher) DispatchContext(ctx context.Context, e Event) Event {
+	cause, ok := CauseFromContext(ctx)
+	if ce, correlated := e.(Correlated); ok && correlated && ce.ID() == "" && ce.CorrelationID() == "" && ce.CausationID() == "" {
+		CausedBy(ce, cause)
+	}
+
+	return d.Dispatch(e)
+}
+
+// CausationChain returns the events that led to the event e, from the root
+// event of the cascade to e itself. The chain is cut where the causing
+// event is no longer remembered. Returns nil unless the dispatcher was
+// created WithCorrelation and e is Correlated.
+func (d *EventDispatcher) CausationChain(e Event) []Event {
+	ce, ok := e.(Correlated)
+	if ok == false || d.correlation == nil {
+		return nil
+	}
+
+	h := d.correlation
+	h.Lock()
+	defer h.Unlock()
+	chain := []Event{ce}
+	for ce.CausationID() != "" && len(chain) <= h.size {
+		cause, ok := h.events[ce.CausationID()]
+		if ok == false {
+			break
+		}
+		chain = append([]Event{cause}, chain...)
+		ce = cause
+	}
+
+	return chain
+}
+
+// correlate assigns the identifiers to the event and remembers it if the
+// dispatcher is configured to
+func correlate(d *EventDispatcher, e Event) {
+	ce, ok := e.(Correlated)
+	if ok == false || d.correlation == nil {
+		return
+	}
+
+	h := d.correlation
+	h.Lock()
+	defer h.Unlock()
+	assignID(ce)
+	remember(h, ce)
+}
+
+// remember adds the event to the history unless it is there already
+func remember(h *correlationHistory, ce Correlated) {
+	if _, ok := h.events[ce.ID()]; ok {
+		return
+	}
+	if len(h.order) >= h.size {
+		delete(h.events, h.order[0])
+		h.order = h.order[1:]
+	}
+	h.events[ce.ID()] = ce
+	h.order = append(h.order, ce.ID())
+}
+
+// assignID generates the identifier of the event lacking it. The event
+// without the correlation identifier starts its own cascade.
+func assignID(e Correlated) {
+	if e.ID() != "" {
+		return
+	}
+	id := NewEventID()
+	correlationID := e.CorrelationID()
+	if correlationID == "" {
+		correlationID = id
+	}
+	e.SetCorrelation(id, correlationID, e.CausationID())
+}
+
+// NewEventID generates a random event identifier
+func NewEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
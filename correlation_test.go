@@ -0,0 +1,77 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCausationChain(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithCorrelation(0))
+	var last Event
+	d.On("order.placed", func(e Event) {
+		d.Dispatch(CausedBy(NewParamsEvent("order.paid"), e))
+	})
+	d.On("order.paid", func(e Event) {
+		d.Dispatch(CausedBy(NewParamsEvent("email.sent"), e))
+	})
+	d.On("email.sent", func(e Event) {
+		last = e
+	})
+	root := NewParamsEvent("order.placed")
+	d.Dispatch(root)
+
+	assert.NotEqual("", root.ID(), "The dispatcher should assign the event ID!")
+	assert.Equal(root.ID(), root.CorrelationID(), "The root event should start its own correlation!")
+	le := last.(*ParamsEvent)
+	assert.Equal(root.ID(), le.CorrelationID(), "The caused events should share the root correlation ID!")
+
+	chain := d.CausationChain(last)
+	assert.Equal(3, len(chain), "Invalid causation chain length!")
+	assert.Equal(Event(root), chain[0], "The chain should start with the root event!")
+	assert.Equal("order.paid", chain[1].Name())
+	assert.Equal(chain[1].(*ParamsEvent).ID(), le.CausationID(), "The event should be caused by the previous one!")
+}
+
+func TestCausationChainHistorySize(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithCorrelation(1))
+	root := NewParamsEvent("root")
+	d.Dispatch(root)
+	e := CausedBy(NewParamsEvent("child"), root)
+	d.Dispatch(e)
+	assert.Equal(1, len(d.CausationChain(e)), "The forgotten cause should cut the chain!")
+	assert.Nil(NewDispatcher().CausationChain(e), "The chain should be nil without correlation enabled!")
+}
+
+func TestDispatchContext(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithCorrelation(0))
+	var last Event
+	d.OnGroup("order.placed", func(ctx context.Context, e Event) error {
+		d.DispatchContext(ctx, NewParamsEvent("order.paid"))
+		return nil
+	})
+	d.On("order.paid", func(e Event) {
+		d.DispatchContext(ContextWithCause(context.Background(), e), NewParamsEvent("email.sent"))
+	})
+	d.On("email.sent", func(e Event) {
+		last = e
+	})
+	root := NewParamsEvent("order.placed")
+	d.Dispatch(root)
+
+	chain := d.CausationChain(last)
+	assert.Equal(3, len(chain), "The events dispatched with the context should be linked!")
+	assert.Equal(Event(root), chain[0], "The chain should start with the root event!")
+	assert.Equal("order.paid", chain[1].Name())
+	assert.Equal(root.ID(), last.(*ParamsEvent).CorrelationID(), "The caused events should share the root correlation ID!")
+
+	next := NewParamsEvent("order.placed")
+	d.DispatchContext(context.Background(), next)
+	assert.Equal("", next.CausationID(), "The event dispatched without the cause should start its own cascade!")
+	assert.Equal(next.ID(), next.CorrelationID(), "The event dispatched without the cause should start its own cascade!")
+}
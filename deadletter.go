@@ -0,0 +1,86 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDeadLetterQueueSize is the max number of dead letters kept by the
+// dispatcher, unless configured with WithDeadLetterQueue
+const DefaultDeadLetterQueueSize = 1024
+
+// DeadLetter is the event the listener failed to handle after exhausting
+// all retries
+type DeadLetter struct {
+
+	// Name is the name the event was dispatched under
+	Name string
+
+	// Event is the event the listener failed to handle
+	Event Event
+
+	// Err is the error of the last attempt
+	Err error
+
+	// Attempts is the number of attempts made
+	Attempts int
+
+	// Time is the time the event has been dead lettered at
+	Time time.Time
+}
+
+// deadLetterQueue keeps the most recent dead letters
+type deadLetterQueue struct {
+	sync.Mutex
+	size    int
+	letters []DeadLetter
+}
+
+// WithDeadLetterQueue sets the max number of dead letters kept by the
+// dispatcher. The oldest ones are dropped when the queue is full.
+func WithDeadLetterQueue(size int) Option {
+	return func(d *EventDispatcher) {
+		d.deadLetters.size = size
+	}
+}
+
+// DeadLetters returns the dead letters, oldest first
+func (d *EventDispatcher) DeadLetters() []DeadLetter {
+	q := d.deadLetters
+	q.Lock()
+	defer q.Unlock()
+
+	return append([]DeadLetter(nil), q.letters...)
+}
+
+// TakeDeadLetters returns the dead letters, oldest first, and removes them
+// from the queue
+func (d *EventDispatcher) TakeDeadLetters() []DeadLetter {
+	q := d.deadLetters
+	q.Lock()
+	defer q.Unlock()
+
+	letters := q.letters
+	q.letters = nil
+
+	return letters
+}
+
+// deadLetter stores the dead letter dl, dropping the oldest one if the
+// queue is full
+func deadLetter(d *EventDispatcher, dl DeadLetter) {
+	q := d.deadLetters
+	q.Lock()
+	defer q.Unlock()
+
+	if q.size <= 0 {
+		return
+	}
+	if len(q.letters) >= q.size {
+		q.letters = append(q.letters[1:len(q.letters):len(q.letters)], dl)
+		return
+	}
+	q.letters = append(q.letters, dl)
+}
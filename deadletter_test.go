@@ -0,0 +1,19 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeadLetterQueueSize(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeadLetterQueue(2))
+	for i := 0; i < 3; i++ {
+		deadLetter(d, DeadLetter{Name: TestEventName, Attempts: i})
+	}
+	letters := d.DeadLetters()
+	assert.Equal(2, len(letters), "The queue should be limited!")
+	assert.Equal(1, letters[0].Attempts, "The oldest dead letter should be dropped!")
+}
@@ -0,0 +1,80 @@
+// Package debughttp exposes the state of the event dispatcher as JSON over
+// HTTP and expvar, for troubleshooting the running service
+package debughttp
+
+import (
+	"encoding/json"
+	"expvar"
+	ed "github.com/gacek85/eventdispatcher"
+	"net/http"
+	"time"
+)
+
+// DefaultPath is the path the handler is registered at by Register
+const DefaultPath = "/debug/eventdispatcher"
+
+// Record is the dispatch record of the history
+type Record struct {
+	Name      string        `json:"name"`
+	Time      time.Time     `json:"time"`
+	Listeners int           `json:"listeners"`
+	Duration  time.Duration `json:"duration"`
+	Errors    []string      `json:"errors,omitempty"`
+	Params    string        `json:"params,omitempty"`
+}
+
+// State is the state of the dispatcher exposed by the handler
+type State struct {
+	Stats      ed.Stats            `json:"stats"`
+	EventNames map[string]string   `json:"event_names"`
+	Listeners  map[string][]string `json:"listeners"`
+	History    []Record            `json:"history"`
+}
+
+// Snapshot returns the current state of the dispatcher d. The history is
+// empty unless the dispatcher has been created WithHistory.
+func Snapshot(d *ed.EventDispatcher) State {
+	st := State{
+		Stats:      d.Stats(),
+		EventNames: ed.EventNames(),
+		Listeners:  d.ListenerNames(),
+		History:    []Record{},
+	}
+	for _, r := range d.History() {
+		rec := Record{Name: r.Name, Time: r.Time, Listeners: r.Listeners, Duration: r.Duration, Params: r.Params}
+		for _, err := range r.Errors {
+			rec.Errors = append(rec.Errors, err.Error())
+		}
+		st.History = append(st.History, rec)
+	}
+
+	return st
+}
+
+// Handler returns the handler responding with the state of the dispatcher
+// d as JSON
+func Handler(d *ed.EventDispatcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(Snapshot(d)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Register registers the handler of the dispatcher d at DefaultPath in the
+// mux
+func Register(mux *http.ServeMux, d *ed.EventDispatcher) {
+	mux.Handle(DefaultPath, Handler(d))
+}
+
+// Publish publishes the state of the dispatcher d as the expvar variable
+// with given name, exposed at /debug/vars along with the others. Panics if
+// the name is already taken, like expvar.Publish does.
+func Publish(name string, d *ed.EventDispatcher) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return Snapshot(d)
+	}))
+}
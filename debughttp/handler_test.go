@@ -0,0 +1,51 @@
+package debughttp
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher(ed.WithStats(), ed.WithHistory(10))
+	d.On("order.paid", func(e ed.Event) {
+		d.ReportError(e, errors.New("failed"))
+	})
+	d.Dispatch(ed.NewParamsEvent("order.paid"))
+	mux := http.NewServeMux()
+	Register(mux, d)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DefaultPath, nil))
+	assert.Equal(http.StatusOK, rec.Code, "The state should be served!")
+	assert.Equal("application/json", rec.Header().Get("Content-Type"))
+	var st State
+	assert.Nil(json.Unmarshal(rec.Body.Bytes(), &st), "The state should be JSON!")
+	assert.Equal(uint64(1), st.Stats.DispatchesByName["order.paid"], "The stats should be exposed!")
+	assert.Len(st.Listeners["order.paid"], 1, "The listeners should be exposed!")
+	assert.Contains(st.EventNames, ed.ErrorEventName, "The event names should be exposed!")
+	assert.Len(st.History, 2, "The history should be exposed!")
+	assert.Equal([]string{"failed"}, st.History[1].Errors, "The errors of the history should be exposed!")
+}
+
+// published counts the runs of TestPublish, so each publishes under its own
+// name, expvar panicking on the name taken
+var published int
+
+func TestPublish(t *testing.T) {
+	d := ed.NewDispatcher()
+	d.On("a", func(e ed.Event) {})
+	published++
+	name := fmt.Sprintf("eventdispatcher_test_%d", published)
+	Publish(name, d)
+	var st State
+	assert.Nil(t, json.Unmarshal([]byte(expvar.Get(name).String()), &st), "The state should be published!")
+	assert.Len(t, st.Listeners["a"], 1, "The listeners should be published!")
+}
@@ -0,0 +1,88 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateEvent is returned by TryDispatch when the event has been
+// dropped because the event with the same ID has already been dispatched
+var ErrDuplicateEvent = errors.New("eventdispatcher: duplicate event")
+
+// dedupeCache remembers the IDs of the dispatched events for the window,
+// keeping at most size most recent ones
+type dedupeCache struct {
+	sync.Mutex
+	window time.Duration
+	size   int
+	order  *list.List
+	seen   map[string]*list.Element
+}
+
+// seenID is the remembered event ID with the time it has been seen at
+type seenID struct {
+	id string
+	at time.Time
+}
+
+// WithDeduplication makes the dispatcher dispatch the events carrying the
+// same ID only once within the window. At most size IDs are remembered,
+// the least recently seen ones are forgotten first. Only the events
+// implementing Correlated with the ID assigned before dispatching, e.g. by
+// the bridges or CausedBy, are deduplicated. Panics if the size is not
+// positive.
+func WithDeduplication(window time.Duration, size int) Option {
+	if size <= 0 {
+		panic(fmt.Sprintf("eventdispatcher: deduplication size must be positive, got %d", size))
+	}
+	return func(d *EventDispatcher) {
+		d.dedupe = &dedupeCache{
+			window: window,
+			size:   size,
+			order:  list.New(),
+			seen:   make(map[string]*list.Element),
+		}
+	}
+}
+
+// dedupe returns ErrDuplicateEvent if the event with the same ID as e has
+// been dispatched within the window, otherwise remembers its ID
+func dedupe(d *EventDispatcher, e Event) error {
+	c := d.dedupe
+	if c == nil {
+		return nil
+	}
+	ce, ok := e.(Correlated)
+	if ok == false || ce.ID() == "" {
+		return nil
+	}
+
+	now := d.clock.Now()
+	c.Lock()
+	defer c.Unlock()
+
+	id := ce.ID()
+	if el, ok := c.seen[id]; ok {
+		s := el.Value.(*seenID)
+		if now.Sub(s.at) < c.window {
+			c.order.MoveToFront(el)
+			return ErrDuplicateEvent
+		}
+		s.at = now
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.seen[id] = c.order.PushFront(&seenID{id, now})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.seen, oldest.Value.(*seenID).id)
+	}
+
+	return nil
+}
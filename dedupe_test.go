@@ -0,0 +1,77 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newEventWithID(id string) *ParamsEvent {
+	e := NewParamsEvent(TestEventName)
+	e.SetCorrelation(id, id, "")
+	return e
+}
+
+func TestDeduplication(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(time.Hour, 10))
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	_, err := d.TryDispatch(newEventWithID("a"))
+	assert.Nil(err)
+	_, err = d.TryDispatch(newEventWithID("a"))
+	assert.Equal(ErrDuplicateEvent, err, "The duplicate should be rejected!")
+	d.Dispatch(newEventWithID("b"))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(4, c, "Only the duplicate should be dropped!")
+}
+
+func TestDeduplicationWindow(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(0, 10))
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	d.Dispatch(newEventWithID("a"))
+	d.Dispatch(newEventWithID("a"))
+	assert.Equal(2, c, "The IDs should be forgotten after the window!")
+}
+
+func TestDeduplicationSize(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(time.Hour, 2))
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	for _, id := range []string{"a", "b", "c", "a"} {
+		d.Dispatch(newEventWithID(id))
+	}
+	assert.Equal(4, c, "The least recently seen ID should be forgotten!")
+	d.Dispatch(newEventWithID("c"))
+	assert.Equal(4, c, "Recently seen IDs should be remembered!")
+}
+
+func TestDeduplicationInvalidSize(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() {
+		WithDeduplication(time.Hour, 0)
+	}, "The deduplication remembering no IDs should be rejected!")
+}
+
+func TestDeduplicationClone(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(time.Hour, 10))
+	e := newEventWithID("a")
+	d.Dispatch(e)
+	_, err := d.TryDispatch(e.Clone())
+	assert.Nil(err, "The clone should not be deduplicated as the original!")
+	_, err = d.TryDispatch(e)
+	assert.ErrorIs(err, ErrDuplicateEvent, "The original should still be deduplicated!")
+}
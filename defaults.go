@@ -0,0 +1,57 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// SetDefaults registers the default params of the events with given name
+// (may contain many space separated names), replacing the ones registered
+// before. The defaults are set on every dispatched ParamsEvent and
+// ConcurrentParamsEvent of that name lacking them, before it is validated
+// against its schema, so the params set by the emitter take precedence.
+// The nil or empty params remove the defaults.
+func (d *EventDispatcher) SetDefaults(n string, params map[string]interface{}) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	for _, name := range getNames(n) {
+		name = normalize(d, name)
+		if len(params) == 0 {
+			delete(d.defaults, name)
+			continue
+		}
+		defaults := make(map[string]interface{}, len(params))
+		for k, v := range params {
+			defaults[k] = deepCopy(v)
+		}
+		d.defaults[name] = defaults
+	}
+}
+
+// applyDefaults sets the copies of the default params registered for given
+// name n on the event e lacking them, so the listeners changing the nested
+// values in place do not change the defaults of the next events
+func applyDefaults(d *EventDispatcher, n string, e Event) {
+	d.RWMutex.RLock()
+	defaults := d.defaults[n]
+	d.RWMutex.RUnlock()
+	if len(defaults) == 0 {
+		return
+	}
+
+	switch ev := e.(type) {
+	case *ParamsEvent:
+		for k, v := range defaults {
+			if ev.HasParam(k) == false {
+				ev.SetParam(k, deepCopy(v))
+			}
+		}
+	case *ConcurrentParamsEvent:
+		for k, v := range defaults {
+			ev.UpdateParam(k, func(old interface{}, ok bool) interface{} {
+				if ok {
+					return old
+				}
+				return deepCopy(v)
+			})
+		}
+	}
+}
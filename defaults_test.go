@@ -0,0 +1,51 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestSetDefaults(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.SetDefaults(TestEventName+" other", map[string]interface{}{"method": "GET", "scheme": "https"})
+	d.SetSchema(TestEventName, NewSchema().Require("method", reflect.TypeOf("")), ValidationReject)
+
+	e := NewParamsEvent(TestEventName).SetParam("method", "POST")
+	_, err := d.TryDispatch(e)
+	assert.Nil(err, "The schema should see the default params!")
+	assert.Equal(map[string]interface{}{"method": "POST", "scheme": "https"}, e.Params(), "The defaults should not override the params!")
+
+	ce := NewConcurrentParamsEvent("other")
+	d.Dispatch(ce)
+	assert.Equal(map[string]interface{}{"method": "GET", "scheme": "https"}, ce.Params(), "The defaults should be set on the concurrent event!")
+
+	d.SetDefaults(TestEventName, nil)
+	e = NewParamsEvent(TestEventName).SetParam("method", "POST")
+	d.Dispatch(e)
+	assert.False(e.HasParam("scheme"), "The removed defaults should not be set!")
+}
+
+func TestSetDefaultsNested(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	params := map[string]interface{}{"headers": map[string]interface{}{"accept": "json"}}
+	d.SetDefaults(TestEventName, params)
+	params["headers"].(map[string]interface{})["accept"] = "xml"
+	d.On(TestEventName, func(e Event) {
+		assert.Nil(e.(*ParamsEvent).SetPath("headers.accept", "html"))
+	})
+
+	d.Dispatch(NewParamsEvent(TestEventName))
+	e := d.Dispatch(NewParamsEvent(TestEventName)).(*ParamsEvent)
+	v, _ := e.GetPath("headers.accept")
+	assert.Equal("html", v, "The listener should change the copy of the defaults!")
+	e = NewParamsEvent(TestEventName)
+	d.OffAll(TestEventName)
+	d.Dispatch(e)
+	v, _ = e.GetPath("headers.accept")
+	assert.Equal("json", v, "The nested defaults should be copied for each event!")
+}
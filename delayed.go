@@ -0,0 +1,147 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// delayed holds the timers of the events scheduled with DispatchAfter
+type delayed struct {
+	sync.Mutex
+	store  DelayStore
+	timers map[string]Timer
+}
+
+// WithDelayStore makes the dispatcher persist the events scheduled with
+// DispatchAfter in the store s. Call RestoreDelayed on startup to re-arm
+// the events scheduled before the restart.
+func WithDelayStore(s DelayStore) Option {
+	return func(d *EventDispatcher) {
+		d.delayed.store = s
+	}
+}
+
+// DispatchAfter schedules the event to be dispatched once the delay
+// elapses. Returns the identifier of the scheduled event, its ID if it is
+// Correlated. With the delay store the event must be JSON encodable and is
+// dispatched as *ParamsEvent decoded from JSON, exactly once per ID.
+func (d *EventDispatcher) DispatchAfter(e Event, delay time.Duration) (string, error) {
+	id := NewEventID()
+	if ce, ok := e.(Correlated); ok {
+		assignID(ce)
+		id = ce.ID()
+	}
+	if d.delayed.store == nil {
+		arm(d, id, delay, func() (Event, error) {
+			return e, nil
+		})
+		return id, nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("eventdispatcher: delayed event: %w", err)
+	}
+	de := DelayedEvent{id, d.clock.Now().Add(delay), data}
+	if err := d.delayed.store.Save(de); err != nil {
+		return "", fmt.Errorf("eventdispatcher: delayed event: %w", err)
+	}
+	armStored(d, de)
+
+	return id, nil
+}
+
+// CancelDelayed cancels the scheduled event with given id. Returns false if
+// it has already been dispatched or canceled.
+func (d *EventDispatcher) CancelDelayed(id string) (bool, error) {
+	dl := d.delayed
+	dl.Lock()
+	t, ok := dl.timers[id]
+	delete(dl.timers, id)
+	dl.Unlock()
+	if ok == false {
+		return false, nil
+	}
+	t.Stop()
+	if dl.store == nil {
+		return true, nil
+	}
+
+	return dl.store.Claim(id)
+}
+
+// RestoreDelayed re-arms the events found in the delay store, e.g. after
+// the restart. The events overdue are dispatched immediately. Returns the
+// number of events re-armed.
+func (d *EventDispatcher) RestoreDelayed() (int, error) {
+	if d.delayed.store == nil {
+		return 0, nil
+	}
+	events, err := d.delayed.store.Load()
+	if err != nil {
+		return 0, fmt.Errorf("eventdispatcher: delayed events: %w", err)
+	}
+	for _, de := range events {
+		armStored(d, de)
+	}
+
+	return len(events), nil
+}
+
+// armStored arms the timer of the persisted event, claiming it when due
+func armStored(d *EventDispatcher, de DelayedEvent) {
+	arm(d, de.ID, de.At.Sub(d.clock.Now()), func() (Event, error) {
+		ok, err := d.delayed.store.Claim(de.ID)
+		if err != nil || ok == false {
+			return nil, err
+		}
+		e := &ParamsEvent{}
+		if err := json.Unmarshal(de.Event, e); err != nil {
+			return nil, err
+		}
+
+		return e, nil
+	})
+}
+
+// arm schedules the event returned by f to be dispatched after the delay.
+// Nothing is dispatched if f returns nil.
+func arm(d *EventDispatcher, id string, delay time.Duration, f func() (Event, error)) {
+	if delay < 0 {
+		delay = 0
+	}
+	dl := d.delayed
+	dl.Lock()
+	defer dl.Unlock()
+
+	if t, ok := dl.timers[id]; ok {
+		t.Stop()
+	}
+	var t Timer
+	t = d.clock.AfterFunc(delay, func() {
+		dl.Lock()
+		if dl.timers[id] != t {
+			dl.Unlock()
+			return
+		}
+		delete(dl.timers, id)
+		dl.Unlock()
+
+		e, err := f()
+		if err != nil {
+			// The source only carries the ID, the event may not be decoded
+			source := NewParamsEvent("")
+			source.SetCorrelation(id, "", "")
+			d.ReportError(source, fmt.Errorf("eventdispatcher: delayed event %s: %w", id, err))
+			return
+		}
+		if e != nil {
+			d.Dispatch(e)
+		}
+	})
+	dl.timers[id] = t
+}
@@ -0,0 +1,77 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatchAfter(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c atomic.Int32
+	d.On(TestEventName, func(e Event) {
+		c.Add(1)
+	})
+	id, err := d.DispatchAfter(NewParamsEvent(TestEventName), 10*time.Millisecond)
+	assert.Nil(err, "Scheduling should succeed!")
+	assert.NotEmpty(id, "The scheduled event should get the ID!")
+	assert.Equal(int32(0), c.Load(), "The event should not be dispatched before the delay!")
+	assert.Eventually(func() bool {
+		return c.Load() == 1
+	}, time.Second, time.Millisecond, "The event should be dispatched after the delay!")
+}
+
+func TestCancelDelayed(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDelayStore(NewMemoryDelayStore()))
+	var c atomic.Int32
+	d.On(TestEventName, func(e Event) {
+		c.Add(1)
+	})
+	id, _ := d.DispatchAfter(NewParamsEvent(TestEventName), 10*time.Millisecond)
+	ok, err := d.CancelDelayed(id)
+	assert.True(ok, "The scheduled event should be canceled!")
+	assert.Nil(err, "Canceling should succeed!")
+	ok, _ = d.CancelDelayed(id)
+	assert.False(ok, "The event should be canceled once!")
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(int32(0), c.Load(), "The canceled event should not be dispatched!")
+}
+
+func TestRestoreDelayed(t *testing.T) {
+	assert := assert.New(t)
+	s, err := NewFileDelayStore(t.TempDir())
+	assert.Nil(err, "The store should be created!")
+	got := make(chan *ParamsEvent, 2)
+	listener := func(e Event) {
+		got <- e.(*ParamsEvent)
+	}
+	before := NewDispatcher(WithDelayStore(s))
+	before.On(TestEventName, listener)
+	id, err := before.DispatchAfter(NewParamsEvent(TestEventName).SetParam("k", "v"), 20*time.Millisecond)
+	assert.Nil(err, "Scheduling should succeed!")
+
+	// The restarted process shares the store while the old one still runs
+	after := NewDispatcher(WithDelayStore(s))
+	after.On(TestEventName, listener)
+	n, err := after.RestoreDelayed()
+	assert.Nil(err, "Restoring should succeed!")
+	assert.Equal(1, n, "The pending event should be restored!")
+
+	select {
+	case e := <-got:
+		assert.Equal(id, e.ID(), "The restored event should keep its ID!")
+		v, _ := e.GetParam("k")
+		assert.Equal("v", v, "The restored event should keep its params!")
+	case <-time.After(time.Second):
+		assert.Fail("The restored event should be dispatched!")
+	}
+	time.Sleep(30 * time.Millisecond)
+	assert.Len(got, 0, "The event should be dispatched exactly once!")
+	n, _ = after.RestoreDelayed()
+	assert.Equal(0, n, "The dispatched event should be removed from the store!")
+}
@@ -0,0 +1,166 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DelayedEvent is the persisted event scheduled with DispatchAfter
+type DelayedEvent struct {
+
+	// ID is the identifier of the scheduled event
+	ID string `json:"id"`
+
+	// At is the time the event is due at
+	At time.Time `json:"at"`
+
+	// Event is the JSON encoded event
+	Event json.RawMessage `json:"event"`
+}
+
+// DelayStore persists the events scheduled with DispatchAfter, so they
+// survive the process restarts
+type DelayStore interface {
+
+	// Save persists the scheduled event
+	Save(de DelayedEvent) error
+
+	// Claim removes the scheduled event with given id. Returns true only
+	// for the first claim, so the event is fired exactly once even when
+	// many processes share the store.
+	Claim(id string) (bool, error)
+
+	// Load returns all scheduled events not claimed yet
+	Load() ([]DelayedEvent, error)
+}
+
+// MemoryDelayStore is the DelayStore keeping the scheduled events in
+// memory, e.g. for tests
+type MemoryDelayStore struct {
+	sync.Mutex
+	events map[string]DelayedEvent
+}
+
+// Save stores the scheduled event
+func (s *MemoryDelayStore) Save(de DelayedEvent) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.events[de.ID] = de
+	return nil
+}
+
+// Claim removes the scheduled event with given id
+func (s *MemoryDelayStore) Claim(id string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	_, ok := s.events[id]
+	delete(s.events, id)
+	return ok, nil
+}
+
+// Load returns all scheduled events
+func (s *MemoryDelayStore) Load() ([]DelayedEvent, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	events := make([]DelayedEvent, 0, len(s.events))
+	for _, de := range s.events {
+		events = append(events, de)
+	}
+	return events, nil
+}
+
+// FileDelayStore is the DelayStore keeping each scheduled event in its own
+// JSON file in the directory. Claiming removes the file, which succeeds
+// only once even across processes.
+type FileDelayStore struct {
+	dir string
+}
+
+// Save writes the scheduled event to its file, atomically
+func (s *FileDelayStore) Save(de DelayedEvent) error {
+	data, err := json.Marshal(de)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(de.ID))
+}
+
+// Claim removes the file of the scheduled event
+func (s *FileDelayStore) Claim(id string) (bool, error) {
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Load reads all scheduled events from the directory
+func (s *FileDelayStore) Load() ([]DelayedEvent, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var events []DelayedEvent
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// Claimed meanwhile
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var de DelayedEvent
+		if err := json.Unmarshal(data, &de); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		events = append(events, de)
+	}
+
+	return events, nil
+}
+
+// path returns the path of the file of the scheduled event with given id
+func (s *FileDelayStore) path(id string) string {
+	return filepath.Join(s.dir, strings.NewReplacer("/", "_", "\\", "_").Replace(id)+".json")
+}
+
+// NewMemoryDelayStore creates an empty in memory delay store
+func NewMemoryDelayStore() *MemoryDelayStore {
+	return &MemoryDelayStore{events: make(map[string]DelayedEvent)}
+}
+
+// NewFileDelayStore creates the delay store keeping the scheduled events in
+// the directory dir, creating it if needed
+func NewFileDelayStore(dir string) (*FileDelayStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileDelayStore{dir}, nil
+}
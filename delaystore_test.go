@@ -0,0 +1,31 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDelayStores(t *testing.T) {
+	fs, err := NewFileDelayStore(t.TempDir())
+	assert.Nil(t, err, "The file store should be created!")
+	for name, s := range map[string]DelayStore{"memory": NewMemoryDelayStore(), "file": fs} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			de := DelayedEvent{"a/b", time.Unix(100, 0).UTC(), []byte(`{"name":"test"}`)}
+			assert.Nil(s.Save(de), "Saving should succeed!")
+			events, err := s.Load()
+			assert.Nil(err, "Loading should succeed!")
+			assert.Equal([]DelayedEvent{de}, events, "The saved event should be loaded!")
+			ok, err := s.Claim("a/b")
+			assert.True(ok, "The first claim should succeed!")
+			assert.Nil(err, "Claiming should succeed!")
+			ok, _ = s.Claim("a/b")
+			assert.False(ok, "The event should be claimed once!")
+			events, _ = s.Load()
+			assert.Empty(events, "The claimed event should be removed!")
+		})
+	}
+}
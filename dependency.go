@@ -0,0 +1,193 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrDependencyCycle is returned by RunAfter when the dependency would make
+// the listeners depend on themselves
+var ErrDependencyCycle = errors.New("eventdispatcher: dependency cycle")
+
+// dependencies holds the names of the listeners each named listener runs
+// after, by event name
+type dependencies struct {
+	sync.RWMutex
+	after map[string]map[string][]string
+}
+
+// OnNamed registers the listener l for given event name (may contain many
+// space separated names) under the listener name, the RunAfter constraints
+// refer to. Returns the subscription removing the listener.
+func (d *EventDispatcher) OnNamed(n string, name string, l Listener) *Subscription {
+	s := &Subscription{d: d}
+	for _, en := range getNames(n) {
+		id := nextID(d)
+		addEntry(d, en, listenerEntry{id: id, l: l, name: name})
+		s.registrations = append(s.registrations, registration{en, id})
+	}
+
+	return s
+}
+
+// RunAfter makes the listeners named listener be called after the ones
+// named dependency for given event name (may contain many space separated
+// names). The listener name is the one given to OnNamed or the ListenerName
+// of the listener registered otherwise. The listeners are reordered
+// topologically, the ones not constrained keeping their relative order,
+// also when registered later. Phases still take precedence, the listeners
+// are only reordered within their phase. Returns ErrDependencyCycle, adding
+// no dependency, if the listeners would depend on themselves.
+func (d *EventDispatcher) RunAfter(n string, listener string, dependency string) error {
+	var names []string
+	for _, name := range getNames(n) {
+		names = append(names, normalize(d, name))
+	}
+
+	deps := d.dependencies
+	deps.Lock()
+	for _, name := range names {
+		if cycle := findCycle(deps.after[name], listener, dependency); cycle != nil {
+			deps.Unlock()
+			return fmt.Errorf("%w: %s for %s", ErrDependencyCycle, strings.Join(cycle, " -> "), name)
+		}
+	}
+	for _, name := range names {
+		if deps.after[name] == nil {
+			deps.after[name] = make(map[string][]string)
+		}
+		deps.after[name][listener] = append(deps.after[name][listener], dependency)
+	}
+	deps.Unlock()
+
+	for _, name := range names {
+		s := shardOf(d, name)
+		s.Lock()
+		s.listeners[name] = orderDependencies(d, name, s.listeners[name])
+		s.Unlock()
+	}
+
+	return nil
+}
+
+// findCycle returns the path of the listener names making the cycle if
+// the listener ran after the dependency, nil if there is none. The
+// dependency runs after the listener if it depends on it, directly or not.
+func findCycle(after map[string][]string, listener string, dependency string) []string {
+	visited := make(map[string]bool)
+	var visit func(name string, path []string) []string
+	visit = func(name string, path []string) []string {
+		path = append(path, name)
+		if name == listener {
+			return path
+		}
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+		for _, dep := range after[name] {
+			if cycle := visit(dep, path); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return visit(dependency, []string{listener})
+}
+
+// entryName returns the name the dependencies of the listener entry refer
+// to
+func entryName(le listenerEntry) string {
+	if le.name != "" {
+		return le.name
+	}
+	if le.once != nil {
+		return ListenerName(le.once)
+	}
+
+	return ListenerName(le.l)
+}
+
+// orderDependencies returns the copy of the listeners of the event name n
+// sorted topologically by the dependencies within each phase, or the
+// listeners themselves if there are no dependencies for the name. The
+// listeners slice may be in use by a running dispatch, so it is never
+// modified in place.
+func orderDependencies(d *EventDispatcher, n string, listeners listenersCollection) listenersCollection {
+	deps := d.dependencies
+	deps.RLock()
+	after := deps.after[n]
+	var ordered listenersCollection
+	if len(after) != 0 {
+		ordered = make(listenersCollection, 0, len(listeners))
+		for start := 0; start < len(listeners); {
+			end := start
+			for end < len(listeners) && listeners[end].phase == listeners[start].phase {
+				end++
+			}
+			ordered = append(ordered, sortTopologically(listeners[start:end], after)...)
+			start = end
+		}
+	}
+	deps.RUnlock()
+
+	if ordered == nil {
+		return listeners
+	}
+
+	return ordered
+}
+
+// sortTopologically returns the listeners sorted so each one follows its
+// dependencies, otherwise keeping their order. Each time the first listener
+// with all dependencies called is taken.
+func sortTopologically(listeners listenersCollection, after map[string][]string) listenersCollection {
+	names := make([]string, len(listeners))
+	pending := make(map[string]int)
+	for i, le := range listeners {
+		names[i] = entryName(le)
+		pending[names[i]]++
+	}
+
+	sorted := make(listenersCollection, 0, len(listeners))
+	taken := make([]bool, len(listeners))
+	for len(sorted) < len(listeners) {
+		next := -1
+		for i := range listeners {
+			if taken[i] == false && ready(after[names[i]], pending) {
+				next = i
+				break
+			}
+		}
+		// Unreachable for the acyclic dependencies, kept as the safety net
+		if next == -1 {
+			for i := range listeners {
+				if taken[i] == false {
+					next = i
+					break
+				}
+			}
+		}
+		taken[next] = true
+		pending[names[next]]--
+		sorted = append(sorted, listeners[next])
+	}
+
+	return sorted
+}
+
+// ready informs whether none of the listeners named deps is pending
+func ready(deps []string, pending map[string]int) bool {
+	for _, dep := range deps {
+		if pending[dep] > 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var dependencyCalls []string
+
+func dependencyFirst(e Event) {
+	dependencyCalls = append(dependencyCalls, "first")
+}
+
+func dependencySecond(e Event) {
+	dependencyCalls = append(dependencyCalls, "second")
+}
+
+func TestRunAfter(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	named := func(name string) Listener {
+		return func(e Event) {
+			got = append(got, name)
+		}
+	}
+	d.OnNamed(TestEventName, "audit", named("audit"))
+	d.OnNamed(TestEventName, "save", named("save"))
+	d.OnNamed(TestEventName, "notify", named("notify"))
+
+	assert.Nil(d.RunAfter(TestEventName, "audit", "notify"))
+	assert.Nil(d.RunAfter(TestEventName, "notify", "save"))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"save", "notify", "audit"}, got, "The listeners should run after their dependencies!")
+
+	d.OnNamed(TestEventName, "validate", named("validate"))
+	assert.Nil(d.RunAfter(TestEventName, "save", "validate"))
+	s := d.OnNamed(TestEventName, "index", named("index"))
+	got = nil
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"validate", "save", "notify", "audit", "index"}, got, "The listeners should be reordered, the unconstrained ones keeping their order!")
+
+	err := d.RunAfter(TestEventName, "validate", "audit")
+	assert.ErrorIs(err, ErrDependencyCycle, "The cycle should be rejected!")
+	assert.Contains(err.Error(), "validate -> audit -> notify -> save -> validate")
+	assert.ErrorIs(d.RunAfter(TestEventName, "save", "save"), ErrDependencyCycle, "The self dependency should be rejected!")
+
+	s.Unsubscribe()
+	got = nil
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"validate", "save", "notify", "audit"}, got, "The order should not change on error!")
+}
+
+func TestRunAfterListenerName(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	dependencyCalls = nil
+	d.On(TestEventName, dependencySecond)
+	d.Once(TestEventName, dependencyFirst)
+	d.OnPhase(TestEventName, PhasePre, func(e Event) {
+		dependencyCalls = append(dependencyCalls, "pre")
+	})
+
+	assert.Nil(d.RunAfter(TestEventName, ListenerName(dependencySecond), ListenerName(dependencyFirst)))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"pre", "first", "second"}, dependencyCalls, "The listeners should be referred to by their function names!")
+}
+
+func TestRunAfterWildcardAndAlias(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	var got []string
+	named := func(name string) Listener {
+		return func(e Event) {
+			got = append(got, name)
+		}
+	}
+	d.OnNamed("user.created", "notify", named("notify"))
+	d.OnNamed("user.*", "audit", named("audit"))
+	d.OnNamed("user.signed_up", "welcome", named("welcome"))
+	d.Alias("user.signed_up", "user.created", AliasBoth)
+
+	assert.Nil(d.RunAfter("user.created", "notify", "audit"))
+	assert.Nil(d.RunAfter("user.created", "notify", "welcome"))
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal([]string{"audit", "welcome", "notify"}, got, "The wildcard and alias listeners should be ordered too!")
+
+	got = nil
+	d.Dispatch(NewParamsEvent("user.signed_up"))
+	assert.Equal([]string{"notify", "audit", "welcome"}, got, "The dependencies of other names should not apply!")
+}
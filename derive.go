@@ -0,0 +1,129 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDerivationCycle is returned by Derive when the derived event would
+// eventually cause its source event again
+var ErrDerivationCycle = errors.New("eventdispatcher: derivation cycle")
+
+// Transform creates the derived event from the source one. Returning nil
+// derives no event.
+type Transform func(Event) Event
+
+// derivation is the registered derivation of the event named to
+type derivation struct {
+	to string
+	id uint64
+}
+
+// Derive makes the dispatcher dispatch the event derived with the
+// transform under the name to whenever the event named from is dispatched,
+// e.g. translating "order.paid" into "email.send_receipt". The nil
+// transform derives the ParamsEvent with the params of the source. The
+// derived event inherits the priority and the correlation of the source,
+// see WithInheritance. Returns
+// ErrDerivationCycle if the derivation, along with the registered ones,
+// would make any event derive itself. The returned subscription removes
+// the derivation.
+func (d *EventDispatcher) Derive(from string, to string, transform Transform) (*Subscription, error) {
+	from, to = normalize(d, from), normalize(d, to)
+	if transform == nil {
+		transform = copyParams(to)
+	}
+
+	d.RWMutex.Lock()
+	if path := derivationPath(d, to, from, nil); path != nil {
+		d.RWMutex.Unlock()
+		return nil, fmt.Errorf("%w: %s -> %s", ErrDerivationCycle, from, strings.Join(path, " -> "))
+	}
+	id := nextID(d)
+	d.derivations[from] = append(d.derivations[from], derivation{to, id})
+	d.RWMutex.Unlock()
+
+	onID(d, from, id, func(e Event) {
+		de := transform(e)
+		if de == nil {
+			return
+		}
+		tryDispatch(d, to, d.inheritance.Apply(de, e), nil)
+	})
+	s := &Subscription{d: d, registrations: []registration{{from, id}}}
+	s.stop = func() bool {
+		return removeDerivation(d, from, id)
+	}
+
+	return s, nil
+}
+
+// derivationPath returns the path of the derivations leading from the
+// event name n to the target one, or nil if there is none. With the
+// wildcard matching the derivations from the patterns matching the name
+// are followed as well.
+func derivationPath(d *EventDispatcher, n string, target string, visited map[string]bool) []string {
+	if n == target || (d.wildcards && matchPattern(target, n)) {
+		return []string{n}
+	}
+	if visited == nil {
+		visited = make(map[string]bool)
+	}
+	if visited[n] {
+		return nil
+	}
+	visited[n] = true
+
+	for from, derivations := range d.derivations {
+		if from != n && (d.wildcards == false || matchPattern(from, n) == false) {
+			continue
+		}
+		for _, dv := range derivations {
+			if path := derivationPath(d, dv.to, target, visited); path != nil {
+				return append([]string{n}, path...)
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeDerivation removes the derivation with given id from the event
+// named from. Returns false if it has already been removed.
+func removeDerivation(d *EventDispatcher, from string, id uint64) bool {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	derivations := d.derivations[from]
+	for i, dv := range derivations {
+		if dv.id == id {
+			d.derivations[from] = append(derivations[:i:i], derivations[i+1:]...)
+			if len(d.derivations[from]) == 0 {
+				delete(d.derivations, from)
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// copyParams returns the transform deriving the ParamsEvent named n with
+// the params of the source
+func copyParams(n string) Transform {
+	return func(e Event) Event {
+		de := NewParamsEvent(n)
+		if pe, ok := e.(interface {
+			Params() map[string]interface{}
+		}); ok {
+			for k, v := range pe.Params() {
+				de.SetParam(k, v)
+			}
+		}
+
+		return de
+	}
+}
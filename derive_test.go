@@ -0,0 +1,63 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []*ParamsEvent
+	d.On("email.send_receipt", func(e Event) {
+		got = append(got, e.(*ParamsEvent))
+	})
+	s, err := d.Derive("order.paid", "email.send_receipt", nil)
+	assert.Nil(err, "The derivation should be registered!")
+	paid := NewParamsEvent("order.paid").SetParam("order", 12)
+	d.Dispatch(paid)
+	assert.Len(got, 1, "The derived event should be dispatched!")
+	v, _ := got[0].GetParam("order")
+	assert.Equal(12, v, "The derived event should get the params of the source!")
+	assert.Equal(paid.ID(), got[0].CausationID(), "The derived event should be caused by the source!")
+
+	s.Unsubscribe()
+	d.Dispatch(NewParamsEvent("order.paid"))
+	assert.Len(got, 1, "The removed derivation should not dispatch!")
+	_, err = d.Derive("email.send_receipt", "order.paid", nil)
+	assert.Nil(err, "The removed derivation should not make a cycle!")
+}
+
+func TestDeriveTransform(t *testing.T) {
+	d := NewDispatcher()
+	var c int
+	d.On("big", func(e Event) {
+		c++
+	})
+	d.Derive("order", "big", func(e Event) Event {
+		if v, _ := e.(*ParamsEvent).GetParam("amount"); v.(int) < 100 {
+			return nil
+		}
+		return NewParamsEvent("big")
+	})
+	d.Dispatch(NewParamsEvent("order").SetParam("amount", 10))
+	d.Dispatch(NewParamsEvent("order").SetParam("amount", 1000))
+	assert.Equal(t, 1, c, "Only the transformed events should be derived!")
+}
+
+func TestDeriveCycle(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	_, err := d.Derive("a", "a", nil)
+	assert.ErrorIs(err, ErrDerivationCycle, "The event should not derive itself!")
+	d.Derive("a", "b", nil)
+	d.Derive("b", "c", nil)
+	_, err = d.Derive("c", "a", nil)
+	assert.ErrorIs(err, ErrDerivationCycle, "The indirect cycle should be detected!")
+	assert.Contains(err.Error(), "c -> a -> b -> c", "The error should show the cycle!")
+	_, err = d.Derive("c.*", "c.x", nil)
+	assert.ErrorIs(err, ErrDerivationCycle, "The pattern matching the derived name should be detected!")
+	assert.False(d.HasListeners("c"), "The cyclic derivation should not be registered!")
+}
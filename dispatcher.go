@@ -4,17 +4,16 @@ package eventdispatcher
 
 import (
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 const (
 	DefaultDispatcherKey = "event_dispatcher"
 )
 
-// Listener type for defining functions as listeners
-type Listener func(Event)
-
 // Dispatcher interface defines the event dispatcher behavior
 type Dispatcher interface {
 
@@ -30,8 +29,9 @@ type Dispatcher interface {
 	// the Listener type function.
 	Once(n string, l Listener)
 
-	// Off removes the registered event listener for given event name.
-	Off(n string, l Listener)
+	// Off removes all registrations of the event listener for given event
+	// name. Returns the number of listeners removed.
+	Off(n string, l Listener) int
 
 	// RemoveAll removes all listeners for given name.
 	OffAll(n string)
@@ -42,15 +42,93 @@ type Dispatcher interface {
 	HasListeners(n string) bool
 }
 
-type listenersCollection []Listener
+// listenerEntry is the registered listener along with the identifier
+// distinguishing it from all other registrations. The once listener is the
+// one wrapped by Once, so the wrapper may be recreated. The group listener
+// is the one registered with OnGroup, called directly by DispatchGroup. The
+// name is the one given to OnNamed.
+type listenerEntry struct {
+	id    uint64
+	l     Listener
+	phase Phase
+	once  Listener
+	group GroupListener
+	name  string
+}
+
+type listenersCollection []listenerEntry
 
 // The EventDispatcher type is the default implementation of the
 // DispatcherInterface
 type EventDispatcher struct {
 	sync.RWMutex
-	listeners map[string]listenersCollection
+	lastID       uint64
+	shards       []*listenerShard
+	rateLimiters map[string]*rateLimiter
+	filters      []Filter
+	namedFilters map[string][]Filter
+	schemas      map[string]schemaEntry
+	handlers     map[string]RequestHandler
+	children     []Dispatcher
+	groups       map[string]*ListenerGroup
+	subscribers  map[Subscriber][]registration
+	clock        Clock
+	correlation  *correlationHistory
+	history      *history
+	reports      reports
+	middlewares  []Middleware
+
+	retryPolicies map[string]RetryPolicy
+	deadLetters   *deadLetterQueue
+	ordered       *orderedLanes
+	strict        StrictMode
+	wildcards     bool
+	dedupe        *dedupeCache
+	modules       map[string][]registration
+	aliases       atomic.Pointer[map[string][]string]
+	delayed       *delayed
+	tenants       map[string]bool
+	authorizer    Authorizer
+	derivations   map[string][]derivation
+	stats         *stats
+	labels        bool
+	normalizer    NameNormalizer
+	anyListeners  atomic.Pointer[listenersCollection]
+	router        Router
+	sealed        atomic.Bool
+	hooks         *unsubscribeHooks
+	lifecycle     Dispatcher
+	groupLimit    int
+	sampling      map[string]float64
+	sensitive     map[string]bool
+	defaults      map[string]map[string]interface{}
+	dependencies  *dependencies
+	inheritance   Inheritance
+	edges         map[uint64]TopologyEdge
+	forwarded     sync.Map
+
+	changeTracking bool
+
+	recoverPanics bool
+	panicHandler  PanicHandler
+	errors        chan error
+
+	paused          bool
+	pauseBuffer     []pausedEvent
+	pauseBufferSize int
+	overflow        OverflowPolicy
+
+	syncMode    bool
+	queue       *asyncQueue
+	pendingMu   sync.Mutex
+	pendingCond *sync.Cond
+	pending     int
+	watermarks  *watermarks
 }
 
+// Option configures the EventDispatcher created with NewDispatcher
+type Option func(*EventDispatcher)
+
 // Forces the instance to be aware of event dispatcher
 type DispatcherAware interface {
 
@@ -58,11 +136,12 @@ type DispatcherAware interface {
 	Dispatcher() Dispatcher
 }
 
-// On registers a listener for given event name.
+// On registers a listener for given event name. The RegistrationError,
+// e.g. of the nil listener, is reported with ReportError, use OnE to get it
+// returned.
 func (d *EventDispatcher) On(n string, l Listener) {
-	names := getNames(n)
-	for _, name := range names {
-		on(d, name, l)
+	if err := d.OnE(n, l); err != nil {
+		d.ReportError(NewParamsEvent(n), err)
 	}
 }
 
@@ -80,124 +159,366 @@ func getNames(n string) []string {
 	return results
 }
 
-// on binds listener to given event name n
-func on(d *EventDispatcher, n string, l Listener) {
-	d.RWMutex.Lock()
-	defer d.RWMutex.Unlock()
-	d.listeners[n] = append(d.listeners[n], l)
+// on binds listener to given event name n. Returns the identifier of the
+// registration.
+func on(d *EventDispatcher, n string, l Listener) uint64 {
+	id := nextID(d)
+	onID(d, n, id, l)
+
+	return id
+}
+
+// nextID generates a new listener registration identifier
+func nextID(d *EventDispatcher) uint64 {
+	return atomic.AddUint64(&d.lastID, 1)
+}
+
+// onID binds listener to given event name n under given identifier
+func onID(d *EventDispatcher, n string, id uint64, l Listener) {
+	addEntry(d, n, listenerEntry{id: id, l: l})
+}
+
+// addEntry adds the listener entry to the listeners of event name n, after
+// all entries of the same or earlier phase
+func addEntry(d *EventDispatcher, n string, le listenerEntry) {
+	n = normalize(d, n)
+	if ok, _ := checkName(d, n, nil); ok == false {
+		return
+	}
+	if rejectSealed(d, n) {
+		return
+	}
+
+	s := shardOf(d, n)
+	s.Lock()
+	s.listeners[n] = orderDependencies(d, n, insertEntry(s.listeners[n], le))
+	s.Unlock()
+
+	listenerAdded(d, n, le.id)
+}
+
+// insertEntry returns the listeners with the entry le added after all
+// entries of the same or earlier phase. The listeners slice may be in use by
+// a running dispatch, so it is only appended to, never modified in place.
+func insertEntry(listeners listenersCollection, le listenerEntry) listenersCollection {
+	i := len(listeners)
+	for i > 0 && listeners[i-1].phase > le.phase {
+		i--
+	}
+	if i == len(listeners) {
+		return append(listeners, le)
+	}
+	added := make(listenersCollection, 0, len(listeners)+1)
+
+	return append(append(append(added, listeners[:i]...), le), listeners[i:]...)
+}
+
+// registration identifies the listener registered for event name n
+type registration struct {
+	n  string
+	id uint64
+}
+
+// offRegistrations removes all listeners of given registrations
+func offRegistrations(d *EventDispatcher, registrations []registration) {
+	for _, r := range registrations {
+		offID(d, r.n, r.id)
+	}
+}
+
+// offID removes the listener registered under given identifier for event
+// name n
+func offID(d *EventDispatcher, n string, id uint64) {
+	listenersRemoved(d, n, removeID(d, n, id))
+}
+
+// removeID removes the listener registered under given identifier for
+// event name n without calling the unsubscribe hooks. Returns the
+// identifiers removed.
+func removeID(d *EventDispatcher, n string, id uint64) []uint64 {
+	n = normalize(d, n)
+	s := shardOf(d, n)
+	s.Lock()
+	defer s.Unlock()
+
+	// The listeners slice may be in use by a running dispatch, so it is
+	// never modified in place
+	var listeners listenersCollection
+	var removed []uint64
+	for _, le := range s.listeners[n] {
+		if le.id != id {
+			listeners = append(listeners, le)
+			continue
+		}
+		removed = append(removed, le.id)
+	}
+	s.listeners[n] = listeners
+
+	return removed
 }
 
 // Once registers a listener to be executed only once. The first param
 // n is the name of the event the listener will listen on, second is
-// the Listener type function.
+// the Listener type function. The RegistrationError is reported with
+// ReportError, use OnceE to get it returned.
 func (d *EventDispatcher) Once(n string, l Listener) {
-	names := getNames(n)
-	for _, name := range names {
-		nl := executeRemove(d, name, l) // Create a new listener that removes given listener after calling it
-		on(d, n, nl)
+	if err := d.OnceE(n, l); err != nil {
+		d.ReportError(NewParamsEvent(n), err)
 	}
 }
 
-func executeRemove(d *EventDispatcher, n string, l Listener) Listener {
-	var nl func(e Event)
-	nl = func(e Event) {
+// executeRemove wraps the listener l with a function calling it only once,
+// even if the event is dispatched by many goroutines at the same time, and
+// removing the registration with given id from the listeners of event name n
+func executeRemove(d *EventDispatcher, n string, id uint64, l Listener) Listener {
+	var fired int32
+	return func(e Event) {
+		if atomic.CompareAndSwapInt32(&fired, 0, 1) == false {
+			return
+		}
+		// The hooks run after the listener, which may still need the
+		// resources they release
+		removed := removeID(d, n, id)
+		defer listenersRemoved(d, n, removed)
 		l(e)
-		d.RWMutex.RUnlock() // The dispatcher is locked in the Dispatch method, need to unlock it
-		d.Off(n, nl)
-		d.RWMutex.RLock()
 	}
-
-	return nl
 }
 
-// Off removes the registered event listener for given event name.
-func (d *EventDispatcher) Off(n string, l Listener) {
-	d.RWMutex.Lock()
-	defer d.RWMutex.Unlock()
+// Off removes all registrations of the event listener for given event
+// name, including the ones made with Once, and returns the number of
+// listeners removed. The listeners are compared by their code pointers, so
+// all closures created by the same function literal and all method values
+// of the same method are considered equal. Use Subscribe to remove exactly
+// one registration.
+func (d *EventDispatcher) Off(n string, l Listener) int {
+	n = normalize(d, n)
+	if rejectSealed(d, n) {
+		return 0
+	}
+	var removed []uint64
+	defer func() {
+		listenersRemoved(d, n, removed)
+	}()
+	s := shardOf(d, n)
+	s.Lock()
+	defer s.Unlock()
 
 	p := reflect.ValueOf(l).Pointer()
 
-	listeners := d.listeners[n]
-	for i, l := range listeners {
-		lp := reflect.ValueOf(l).Pointer()
-		if lp == p {
-			d.listeners[n] = append(listeners[:i], listeners[i+1:]...)
+	// The listeners slice may be in use by a running dispatch, so it is
+	// never modified in place
+	var listeners listenersCollection
+	for _, le := range s.listeners[n] {
+		// The once entries call the wrapper of the registered listener
+		registered := le.l
+		if le.once != nil {
+			registered = le.once
+		}
+		if reflect.ValueOf(registered).Pointer() != p {
+			listeners = append(listeners, le)
+			continue
 		}
+		removed = append(removed, le.id)
 	}
+	if len(removed) != 0 {
+		s.listeners[n] = listeners
+	}
+
+	return len(removed)
 }
 
 // RemoveAll removes all listeners for given name.
 func (d *EventDispatcher) OffAll(n string) {
-	d.RWMutex.Lock()
-	defer d.RWMutex.Unlock()
-
-	_, ok := d.listeners[n]
+	n = normalize(d, n)
+	if rejectSealed(d, n) {
+		return
+	}
+	var removed []uint64
+	defer func() {
+		listenersRemoved(d, n, removed)
+	}()
+	s := shardOf(d, n)
+	s.Lock()
+	defer s.Unlock()
+
+	listeners, ok := s.listeners[n]
 	if ok != false {
-		delete(d.listeners, n)
+		for _, le := range listeners {
+			removed = append(removed, le.id)
+		}
+		delete(s.listeners, n)
 	}
 }
 
 // HasListeners returns true if any listener for given event name has
 // been assigned and false otherwise. This applies also to once triggered
-// listeners registered with `One` method
+// listeners registered with `One` method and, if the dispatcher matches
+// wildcards, to the listeners of the matching patterns
 func (d *EventDispatcher) HasListeners(n string) bool {
-	listeners, ok := d.listeners[n]
-	if ok == false {
-		return false
-	}
+	return d.ListenerCount(n) != 0
+}
 
-	return len(listeners) != 0
+// ListenerCount returns the number of listeners called when the event with
+// given name is dispatched, including the listeners of the matching patterns
+// if the dispatcher matches wildcards
+func (d *EventDispatcher) ListenerCount(n string) int {
+	return len(listenersFor(d, n))
 }
 
 // Dispatch dispatches the event and returns it after all listeners do their jobs
 func (d *EventDispatcher) Dispatch(e Event) Event {
-	d.RWMutex.RLock()
-	defer d.RWMutex.RUnlock()
+	e, _ = d.TryDispatch(e)
 
-	return dispatch(d, e)
+	return e
 }
 
-// dispatch takes all registered listeners for given event name
-// and dispatches the event
-func dispatch(d *EventDispatcher, e Event) Event {
-	for _, l := range d.listeners[e.Name()] {
-		l(e)
+// TryDispatch dispatches the event like Dispatch does, but returns
+// ErrRateLimited if the event has been dropped by the rate limiter
+// configured for its name, ErrBufferFull if it has been dropped by the
+// paused dispatcher, the ValidationError if it has been rejected by the
+// schema registered for its name and ErrUnknownEventName if its name is not
+// registered and the dispatcher rejects unknown names. ErrDuplicateEvent is
+// returned if the event with the same ID has been dispatched recently. The
+// error the listeners failed the Failable event with is returned after the
+// dispatch. The event is passed through the registered filters first, the
+// returned event is the one produced by them.
+func (d *EventDispatcher) TryDispatch(e Event) (Event, error) {
+	return tryDispatch(d, e.Name(), e, nil)
+}
+
+// tryDispatch runs the dispatching pipeline for the event delivered to the
+// listeners registered for given name n. The listener calls are reported to
+// rep unless it is nil.
+func tryDispatch(d *EventDispatcher, n string, e Event, rep *DispatchReport) (Event, error) {
+	n = normalize(d, n)
+	if ok, err := checkName(d, n, e); ok == false {
+		return e, err
+	}
+	if buffered, err := buffer(d, n, e); buffered {
+		return e, err
 	}
 
-	return e
+	return process(d, n, e, callListeners(d, rep))
 }
 
-// Inner registry of event dispatcher instances
-var dispatchers map[string]*EventDispatcher
+// callListeners returns the step of the pipeline calling the listeners with
+// dispatch, reporting the calls to rep unless it is nil
+func callListeners(d *EventDispatcher, rep *DispatchReport) func(n string, e Event) int {
+	return func(n string, e Event) int {
+		return dispatch(d, n, e, rep)
+	}
+}
 
-// GetDispatcher provides event dispatcher for given key string. If the
-// key string is nil, takes the default key
-func GetDispatcher(k interface{}) *EventDispatcher {
-	var key string
-	if dispatchers == nil {
-		dispatchers = make(map[string]*EventDispatcher)
+// process passes the event e dispatched under the normalized name n, which
+// has not been buffered, through the rest of the pipeline and hands it to
+// call, which calls the listeners and returns the number of them called
+func process(d *EventDispatcher, n string, e Event, call func(n string, e Event) int) (Event, error) {
+	if err := dedupe(d, e); err != nil {
+		return e, err
 	}
-	if k == nil {
-		key = DefaultDispatcherKey
-	} else {
-		key = k.(string)
+	e, n, ok := filter(d, n, e)
+	if ok == false || sample(d, n) == false {
+		return e, nil
+	}
+	applyDefaults(d, n, e)
+	if err := validate(d, n, e); err != nil {
+		return e, err
 	}
+	if ok, err := limit(d, n); ok == false {
+		return e, err
+	}
+	correlate(d, e)
+	trackChanges(d, e)
+
+	r := startRecord(d, n, e)
+	c := call(n, e)
+	finishRecord(d, r, c)
+	forward(d, e)
 
-	return getDispatcher(key)
+	return e, failure(e)
 }
 
-func getDispatcher(k string) *EventDispatcher {
-	d, ok := dispatchers[k]
-	if ok == false {
-		dispatchers[k] = NewDispatcher()
-		d = dispatchers[k]
+// dispatch takes all registered listeners for given event name n
+// and dispatches the event until any of them stops its propagation, except
+// for the listeners of the PhasePost that are always called. Returns
+// the number of listeners called. The listeners are called without holding
+// the lock, so they may safely use the dispatcher themselves. Listeners
+// added or removed meanwhile take effect from the next dispatch on.
+func dispatch(d *EventDispatcher, n string, e Event, rep *DispatchReport) int {
+	listeners := route(d, n, e)
+	d.RWMutex.RLock()
+	mws := d.middlewares
+	d.RWMutex.RUnlock()
+
+	if rep != nil {
+		startReport(d, rep, n, e)
+		defer finishReport(d, rep)
 	}
-	return d
+	var c int
+	for _, le := range listeners {
+		if e.IsPropagationStopped() && le.phase != PhasePost {
+			continue
+		}
+		c++
+		le := le
+		if rep == nil {
+			invoke(d, mws, ListenerCall{n, le.id}, le.l, e)
+			continue
+		}
+		reportListener(d, rep, le.id, e, func() {
+			invoke(d, mws, ListenerCall{n, le.id}, le.l, e)
+		})
+	}
+
+	return c + dispatchAny(d, n, e, mws, rep)
 }
 
-// NewDispatcher creates a new instance of event dispatcher
-func NewDispatcher() *EventDispatcher {
-	return &EventDispatcher{
-		listeners: make(map[string]listenersCollection),
+// GetDispatcher provides event dispatcher for given key string from the
+// DefaultRegistry. If the key string is nil, takes the default key
+func GetDispatcher(k interface{}) *EventDispatcher {
+	key := DefaultDispatcherKey
+	if k != nil {
+		key = k.(string)
 	}
+
+	return DefaultRegistry.Get(key)
+}
+
+// NewDispatcher creates a new instance of event dispatcher configured with
+// given options
+func NewDispatcher(opts ...Option) *EventDispatcher {
+	d := &EventDispatcher{
+		shards:       newShards(DefaultShardCount),
+		rateLimiters: make(map[string]*rateLimiter),
+		sampling:     make(map[string]float64),
+		sensitive:    make(map[string]bool),
+		defaults:     make(map[string]map[string]interface{}),
+		dependencies: &dependencies{after: make(map[string]map[string][]string)},
+		namedFilters: make(map[string][]Filter),
+		schemas:      make(map[string]schemaEntry),
+		handlers:     make(map[string]RequestHandler),
+		groups:       make(map[string]*ListenerGroup),
+		subscribers:  make(map[Subscriber][]registration),
+		errors:       make(chan error, DefaultErrorsBufferSize),
+		clock:        SystemClock{},
+		history:      &history{},
+
+		retryPolicies: make(map[string]RetryPolicy),
+		modules:       make(map[string][]registration),
+		delayed:       &delayed{timers: make(map[string]Timer)},
+		tenants:       make(map[string]bool),
+		derivations:   make(map[string][]derivation),
+		hooks:         &unsubscribeHooks{hooks: make(map[uint64]*unsubscribeHook)},
+		deadLetters:   &deadLetterQueue{size: DefaultDeadLetterQueueSize},
+		ordered:       newOrderedLanes(runtime.GOMAXPROCS(0)),
+
+		pauseBufferSize: DefaultPauseBufferSize,
+	}
+	d.pendingCond = sync.NewCond(&d.pendingMu)
+	for _, opt := range opts {
+		opt(d)
+	}
+	normalizeOptions(d)
+
+	return d
 }
@@ -5,7 +5,10 @@ package eventdispatcher
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -17,7 +20,7 @@ func TestOnOff(t *testing.T) {
 	d := NewDispatcher()
 	assert.False(d.HasListeners(TestEventName), fmt.Sprintf("No listeners assigned yet for %s!", TestEventName))
 	l := func(e Event) {
-		fmt.Sprintf("Event name: %s", e.Name())
+		_ = fmt.Sprintf("Event name: %s", e.Name())
 	}
 	d.On(TestEventName, l)
 	assert.True(d.HasListeners(TestEventName), fmt.Sprintf("There should be listeners assigned for %s!", TestEventName))
@@ -28,7 +31,7 @@ func TestOnOff(t *testing.T) {
 func TestOnMany(t *testing.T) {
 	assert := assert.New(t)
 	d := NewDispatcher()
-	var count int32
+	var count int
 	count = 0
 	d.On("event_1 event_2   event_3", func(e Event) {
 		count++
@@ -53,7 +56,7 @@ func TestOnce(t *testing.T) {
 	assert := assert.New(t)
 	d := NewDispatcher()
 	d.Once(TestEventName, func(e Event) {
-		fmt.Sprintf("Event name: %s", e.Name())
+		_ = fmt.Sprintf("Event name: %s", e.Name())
 	})
 	assert.True(d.HasListeners(TestEventName), fmt.Sprintf("There should be one listener assigned for one call for %s!", TestEventName))
 	e := NewParamsEvent(TestEventName)
@@ -98,3 +101,165 @@ func TestGetDispatcher(t *testing.T) {
 	assert.Equal(d, dn, "The event dispatchers should be the same instance pointers!")
 	_ = GetDispatcher("foo")
 }
+
+// runWithTimeout fails the test if f does not return in a reasonable time,
+// which means the dispatcher deadlocked
+func runWithTimeout(t *testing.T, f func()) {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The dispatcher deadlocked!")
+	}
+}
+
+func TestReentrantDispatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var names []string
+	d.On("outer", func(e Event) {
+		names = append(names, e.Name())
+		d.Dispatch(NewParamsEvent("inner"))
+	})
+	d.On("inner", func(e Event) {
+		names = append(names, e.Name())
+	})
+	runWithTimeout(t, func() {
+		d.Dispatch(NewParamsEvent("outer"))
+	})
+	assert.Equal([]string{"outer", "inner"}, names, "The nested event should be dispatched from within the listener!")
+}
+
+func TestReentrantOnOff(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	added := func(e Event) {
+		c++
+	}
+	var self Listener
+	self = func(e Event) {
+		d.On(TestEventName, added)
+		d.Once(TestEventName, added)
+		d.Off(TestEventName, self)
+		d.OffAll("other_event")
+	}
+	d.On(TestEventName, self)
+	runWithTimeout(t, func() {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	})
+	assert.Equal(0, c, "Listeners added during the dispatch should not be called by it!")
+	runWithTimeout(t, func() {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	})
+	assert.Equal(2, c, "Listeners added during the previous dispatch should be called!")
+}
+
+func TestOnceMany(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.Once("event_1 event_2", func(e Event) {
+		c++
+	})
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent("event_2"))
+	assert.Equal(2, c, "The listener should be called once per event name!")
+	assert.False(d.HasListeners("event_2"), "The listener called should unbind itself for event_2!")
+}
+
+func TestOnceDoesNotRemoveOtherOnceListeners(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	for i := 0; i < 3; i++ {
+		d.Once(TestEventName, func(e Event) {
+			c++
+		})
+	}
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(3, c, "All once listeners should be called!")
+	assert.False(d.HasListeners(TestEventName), "All once listeners should unbind themselves!")
+}
+
+func TestOnceConcurrentDispatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int32
+	d.Once(TestEventName, func(e Event) {
+		atomic.AddInt32(&c, 1)
+	})
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			d.Dispatch(NewParamsEvent(TestEventName))
+		}()
+	}
+	close(start)
+	wg.Wait()
+	assert.Equal(int32(1), atomic.LoadInt32(&c), "The listener should be called exactly once!")
+}
+
+func TestOnceReentrantDispatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.Once(TestEventName, func(e Event) {
+		c++
+		d.Dispatch(NewParamsEvent(TestEventName))
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The reentrant dispatch should not call the listener again!")
+}
+
+func TestOffRemovesAllOccurrences(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	l := func(e Event) {
+		c++
+	}
+	other := func(e Event) {}
+	d.On(TestEventName, l)
+	d.On(TestEventName, other)
+	d.On(TestEventName, l)
+	assert.Equal(2, d.Off(TestEventName, l), "All registrations should be removed!")
+	assert.Equal(0, d.Off(TestEventName, l), "The no-op removal should return 0!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(0, c, "The removed listener should not be called!")
+	assert.True(d.HasListeners(TestEventName), "Other listeners should be kept!")
+}
+
+func TestOffOnce(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	l := func(e Event) {
+		c++
+	}
+	d.Once(TestEventName, l)
+	d.On(TestEventName, l)
+	assert.Equal(2, d.Off(TestEventName, l), "The once listener should be removed too!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(0, c, "The removed listeners should not be called!")
+}
+
+func TestListenerCount(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	assert.Equal(0, d.ListenerCount(TestEventName))
+	d.On(TestEventName, func(e Event) {})
+	d.Once(TestEventName, func(e Event) {})
+	assert.Equal(2, d.ListenerCount(TestEventName), "All listeners should be counted!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, d.ListenerCount(TestEventName), "The once listener should not be counted after the dispatch!")
+}
@@ -0,0 +1,115 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"time"
+)
+
+// WithSyncMode makes the dispatcher run all asynchronous dispatch paths
+// synchronously in the calling goroutine. Meant for tests, so they do not
+// need to wait for the listeners called in the background.
+func WithSyncMode() Option {
+	return func(d *EventDispatcher) {
+		d.syncMode = true
+	}
+}
+
+// WithAsync limits the asynchronous work of the dispatcher to n workers
+// running at once. The work beyond the limit is queued and picked by the
+// free workers by priority. Unlimited by default.
+func WithAsync(n int) Option {
+	return func(d *EventDispatcher) {
+		if n > 0 {
+			d.queue = &asyncQueue{workers: n}
+		}
+	}
+}
+
+// DispatchAsync dispatches the event in the background, with the priority
+// of the event if it is Prioritized. Use Drain to wait until all
+// asynchronously dispatched events are processed.
+func (d *EventDispatcher) DispatchAsync(e Event) {
+	asyncPriority(d, priorityOf(e), func() {
+		d.Dispatch(e)
+	})
+}
+
+// Drain blocks until all pending asynchronous work of the dispatcher is
+// done, including the work started while draining
+func (d *EventDispatcher) Drain() {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	for d.pending > 0 {
+		d.pendingCond.Wait()
+	}
+}
+
+// async runs f in the background with the normal priority and tracks it as
+// the pending work of the dispatcher. In sync mode f is run immediately.
+func async(d *EventDispatcher, f func()) {
+	asyncPriority(d, PriorityNormal, f)
+}
+
+// asyncPriority runs f in the background with the priority p and tracks it
+// as the pending work of the dispatcher. In sync mode f is run immediately.
+func asyncPriority(d *EventDispatcher, p Priority, f func()) {
+	if d.syncMode {
+		f()
+		return
+	}
+
+	begin(d)
+	if d.queue == nil {
+		go func() {
+			defer done(d)
+			f()
+		}()
+		return
+	}
+	if enqueue(d.queue, p, f) == false {
+		go work(d, f)
+	}
+}
+
+// later runs f in the background with the normal priority after the delay
+// t, without occupying a goroutine of the dispatcher meanwhile. The delayed
+// work is tracked as pending. In sync mode the calling goroutine sleeps for
+// the delay and runs f.
+func later(d *EventDispatcher, t time.Duration, f func()) {
+	if d.syncMode {
+		d.clock.Sleep(t)
+		f()
+		return
+	}
+
+	begin(d)
+	d.clock.AfterFunc(t, func() {
+		async(d, f)
+		done(d)
+	})
+}
+
+// begin marks a piece of work as pending
+func begin(d *EventDispatcher) {
+	d.pendingMu.Lock()
+	d.pending++
+	w, depth := crossWatermark(d), d.pending
+	d.pendingMu.Unlock()
+
+	signalWatermark(d, w, depth)
+}
+
+// done marks a piece of the pending work as done
+func done(d *EventDispatcher) {
+	d.pendingMu.Lock()
+	d.pending--
+	if d.pending == 0 {
+		d.pendingCond.Broadcast()
+	}
+	w, depth := crossWatermark(d), d.pending
+	d.pendingMu.Unlock()
+
+	signalWatermark(d, w, depth)
+}
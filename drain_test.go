@@ -0,0 +1,61 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatchAsyncDrain(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int32
+	d.On("outer", func(e Event) {
+		d.DispatchAsync(NewParamsEvent("inner"))
+	})
+	d.On("inner", func(e Event) {
+		atomic.AddInt32(&c, 1)
+	})
+	for i := 0; i < 10; i++ {
+		d.DispatchAsync(NewParamsEvent("outer"))
+	}
+	d.Drain()
+	assert.Equal(int32(10), atomic.LoadInt32(&c), "Drain should wait for the events dispatched while draining!")
+}
+
+func TestSyncMode(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode())
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	d.DispatchAsync(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The event should be dispatched synchronously in sync mode!")
+	d.Drain()
+}
+
+func TestWithAsync(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithAsync(2))
+	var inFlight, max int32
+	d.On(TestEventName, func(e Event) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	})
+	for i := 0; i < 10; i++ {
+		d.DispatchAsync(NewParamsEvent(TestEventName))
+	}
+	d.Drain()
+	assert.True(atomic.LoadInt32(&max) <= 2, "At most 2 workers should run at once!")
+}
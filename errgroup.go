@@ -0,0 +1,118 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// GroupListener is the listener called concurrently by DispatchGroup. It
+// should give up once the context is done and report the failure of
+// handling the event by returning an error. The context carries the event
+// as the cause, see DispatchContext.
+type GroupListener func(ctx context.Context, e Event) error
+
+// WithGroupLimit limits the listeners called at once by DispatchGroup to n.
+// Unlimited by default.
+func WithGroupLimit(n int) Option {
+	return func(d *EventDispatcher) {
+		d.groupLimit = n
+	}
+}
+
+// OnGroup registers the group listener for given event name (may contain
+// many space separated names) and returns the subscription removing it. The
+// regular dispatch calls it with the background context carrying the event
+// as the cause and reports its error with ReportError.
+func (d *EventDispatcher) OnGroup(n string, l GroupListener) *Subscription {
+	s := &Subscription{d: d}
+	for _, name := range getNames(n) {
+		id := nextID(d)
+		addEntry(d, name, listenerEntry{id: id, l: groupCall(d, l), group: l})
+		s.registrations = append(s.registrations, registration{name, id})
+	}
+
+	return s
+}
+
+// DispatchGroup dispatches the event through the same pipeline as
+// TryDispatch, but calls all its listeners concurrently within an errgroup,
+// at most as many at once as the limit set with WithGroupLimit. Once a group
+// listener returns an error, the context passed to the others is canceled
+// and the listeners not started yet are skipped. The OnAny listeners are
+// called after the group is done. Returns the first error, the error of the
+// context if it is done before all listeners are called, or the error of the
+// dispatch if it has not been dispatched. The event buffered by the paused
+// dispatcher is dispatched regularly on Resume. The propagation of the event
+// can not be stopped.
+func (d *EventDispatcher) DispatchGroup(ctx context.Context, e Event) error {
+	n := normalize(d, e.Name())
+	if ok, err := checkName(d, n, e); ok == false {
+		return err
+	}
+	if buffered, err := buffer(d, n, e); buffered {
+		return err
+	}
+	var groupErr error
+	_, err := process(d, n, e, func(n string, e Event) int {
+		var c int
+		c, groupErr = dispatchGroup(ctx, d, n, e)
+		return c
+	})
+	if groupErr != nil {
+		return groupErr
+	}
+
+	return err
+}
+
+// dispatchGroup calls the listeners of the event e dispatched under name n
+// within an errgroup. Returns the number of listeners called and the error
+// of the group.
+func dispatchGroup(ctx context.Context, d *EventDispatcher, n string, e Event) (int, error) {
+	d.RWMutex.RLock()
+	mws := d.middlewares
+	d.RWMutex.RUnlock()
+
+	g, gctx := errgroup.WithContext(ctx)
+	if d.groupLimit > 0 {
+		g.SetLimit(d.groupLimit)
+	}
+	cctx := ContextWithCause(gctx, e)
+	var c int32
+	for _, le := range route(d, n, e) {
+		if gctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			atomic.AddInt32(&c, 1)
+			if le.group != nil {
+				return le.group(cctx, e)
+			}
+			invoke(d, mws, ListenerCall{n, le.id}, le.l, e)
+			return nil
+		})
+	}
+	err := g.Wait()
+	called := int(c) + dispatchAny(d, n, e, mws, nil)
+	if err != nil {
+		return called, err
+	}
+
+	return called, ctx.Err()
+}
+
+// groupCall adapts the group listener l to the regular dispatch
+func groupCall(d *EventDispatcher, l GroupListener) Listener {
+	return func(e Event) {
+		if err := l(ContextWithCause(context.Background(), e), e); err != nil {
+			d.ReportError(e, err)
+		}
+	}
+}
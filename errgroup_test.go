@@ -0,0 +1,109 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatchGroup(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithGroupLimit(2))
+	var c int32
+	var inFlight, maxInFlight int32
+	for i := 0; i < 5; i++ {
+		d.OnGroup(TestEventName, func(ctx context.Context, e Event) error {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			atomic.AddInt32(&c, 1)
+			atomic.AddInt32(&inFlight, -1)
+			return nil
+		})
+	}
+	d.On(TestEventName, func(e Event) {
+		atomic.AddInt32(&c, 1)
+	})
+	err := d.DispatchGroup(context.Background(), NewParamsEvent(TestEventName))
+	assert.Nil(err, "No error should be returned!")
+	assert.Equal(int32(6), c, "All listeners should be called!")
+	assert.True(maxInFlight <= 2, "The limit should be respected!")
+}
+
+func TestDispatchGroupError(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithGroupLimit(1))
+	failure := errors.New("failed")
+	var canceled bool
+	d.OnGroup(TestEventName, func(ctx context.Context, e Event) error {
+		return failure
+	})
+	d.OnGroup(TestEventName, func(ctx context.Context, e Event) error {
+		canceled = true
+		return nil
+	})
+	err := d.DispatchGroup(context.Background(), NewParamsEvent(TestEventName))
+	assert.Equal(failure, err, "The first error should be returned!")
+	assert.False(canceled, "The listeners not started should be skipped!")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = d.DispatchGroup(ctx, NewParamsEvent(TestEventName))
+	assert.ErrorIs(err, context.Canceled, "The error of the context should be returned!")
+}
+
+func TestOnGroupDispatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	failure := errors.New("failed")
+	d.OnGroup(TestEventName, func(ctx context.Context, e Event) error {
+		return failure
+	}).Unsubscribe()
+	assert.False(d.HasListeners(TestEventName), "The subscription should remove the listener!")
+
+	d.OnGroup(TestEventName, func(ctx context.Context, e Event) error {
+		return failure
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	select {
+	case err := <-d.Errors():
+		assert.Equal(failure, err, "The error should be reported!")
+	default:
+		assert.Fail("The error should be reported!")
+	}
+}
+
+func TestDispatchGroupPipeline(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(time.Hour, 10), WithStats())
+	var c, any int32
+	d.OnGroup(TestEventName, func(ctx context.Context, e Event) error {
+		atomic.AddInt32(&c, 1)
+		return nil
+	})
+	d.OnAny(func(e Event) {
+		atomic.AddInt32(&any, 1)
+	})
+
+	assert.Nil(d.DispatchGroup(context.Background(), newEventWithID("a")))
+	assert.Equal(ErrDuplicateEvent, d.DispatchGroup(context.Background(), newEventWithID("a")), "The duplicate should be rejected!")
+	assert.Equal(int32(1), c, "The duplicate should not be delivered!")
+	assert.Equal(int32(1), any, "The OnAny listeners should be called!")
+	assert.Equal(uint64(1), d.Stats().Dispatches, "The dispatch should be recorded!")
+
+	d.Pause()
+	assert.Nil(d.DispatchGroup(context.Background(), newEventWithID("b")))
+	assert.Equal(int32(1), c, "The paused dispatcher should buffer the event!")
+	d.Resume()
+	assert.Equal(int32(2), c, "The buffered event should be delivered on resume!")
+	assert.Equal(int32(2), any)
+}
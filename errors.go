@@ -0,0 +1,113 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+const (
+	// ErrorEventName is the name of the ErrorEvent dispatched when
+	// a listener fails
+	ErrorEventName = "dispatcher.error"
+
+	// DefaultErrorsBufferSize is the size of the channel returned by Errors
+	DefaultErrorsBufferSize = 64
+)
+
+// ErrorEvent is dispatched when a listener reports an error or panics
+// while handling the source event
+type ErrorEvent struct {
+	*ParamsEvent
+
+	// Err is the error reported by the listener
+	Err error
+
+	// Source is the event the listener failed to handle
+	Source Event
+}
+
+// PanicError is the error reported when the listener panics
+type PanicError struct {
+
+	// Value is the value the listener panicked with
+	Value interface{}
+
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+// Error returns the error message
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("eventdispatcher: listener panicked: %v", err.Value)
+}
+
+// WithRecover makes the dispatcher recover from the panics of the
+// listeners and report them as PanicError instead of crashing the
+// dispatching goroutine
+func WithRecover() Option {
+	return func(d *EventDispatcher) {
+		d.recoverPanics = true
+	}
+}
+
+// PanicHandler handles the panic of the listener handling the event e
+type PanicHandler func(e Event, err *PanicError)
+
+// WithPanicHandler makes the dispatcher recover from the panics of the
+// listeners and pass them to the handler h instead of reporting them
+func WithPanicHandler(h PanicHandler) Option {
+	return func(d *EventDispatcher) {
+		d.recoverPanics = true
+		d.panicHandler = h
+	}
+}
+
+// Errors returns the channel receiving the errors reported by the
+// listeners. Errors are dropped if the channel buffer is full.
+func (d *EventDispatcher) Errors() <-chan error {
+	return d.errors
+}
+
+// ReportError reports the error err of the listener handling the event e.
+// Dispatches the ErrorEvent and sends the error to the Errors channel.
+func (d *EventDispatcher) ReportError(e Event, err error) {
+	recordError(d, e, err)
+	reportError(d, e, err)
+	countError(d)
+	dispatchFailed(d, e, err)
+	select {
+	case d.errors <- err:
+	default:
+	}
+
+	// Errors of the error listeners are not dispatched again to avoid loops
+	if _, ok := e.(*ErrorEvent); ok {
+		return
+	}
+	d.Dispatch(NewErrorEvent(e, err))
+}
+
+// call invokes the listener l, recovering from its panic if the dispatcher
+// is configured to
+func call(d *EventDispatcher, l Listener, e Event) {
+	if d.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				if d.panicHandler != nil {
+					d.panicHandler(e, &PanicError{r, debug.Stack()})
+					return
+				}
+				d.ReportError(e, &PanicError{r, debug.Stack()})
+			}
+		}()
+	}
+	l(e)
+}
+
+// NewErrorEvent creates the event informing about the error err of the
+// listener handling the source event
+func NewErrorEvent(source Event, err error) *ErrorEvent {
+	return &ErrorEvent{NewParamsEvent(ErrorEventName), err, source}
+}
@@ -0,0 +1,87 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRecover())
+	var c int
+	var ee *ErrorEvent
+	d.On(TestEventName, func(e Event) {
+		panic("boom")
+	})
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	d.On(ErrorEventName, func(e Event) {
+		ee = e.(*ErrorEvent)
+	})
+	e := NewParamsEvent(TestEventName)
+	assert.NotPanics(func() {
+		d.Dispatch(e)
+	}, "The listener panic should be recovered!")
+	assert.Equal(1, c, "Listeners after the panicking one should be called!")
+	assert.NotNil(ee, "The error event should be dispatched!")
+	assert.Equal(e, ee.Source, "The error event should contain the source event!")
+	pe, ok := ee.Err.(*PanicError)
+	assert.True(ok, "The error should be the PanicError!")
+	assert.Equal("boom", pe.Value, "Invalid panic value!")
+	assert.Equal(ee.Err, <-d.Errors(), "The error should be sent to the errors channel!")
+}
+
+func TestPanicWithoutRecover(t *testing.T) {
+	d := NewDispatcher()
+	d.On(TestEventName, func(e Event) {
+		panic("boom")
+	})
+	assert.Panics(t, func() {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	}, "The listener panic should not be recovered by default!")
+}
+
+func TestReportError(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	err := errors.New("failed")
+	var c int
+	d.On(TestEventName, func(e Event) {
+		d.ReportError(e, err)
+	})
+	d.On(ErrorEventName, func(e Event) {
+		c++
+		d.ReportError(e, err)
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "Errors of the error listeners should not be dispatched again!")
+	assert.Equal(err, <-d.Errors(), "The reported error should be sent to the errors channel!")
+	assert.Equal(err, <-d.Errors(), "The error of the error listener should be sent to the errors channel!")
+}
+
+func TestWithPanicHandler(t *testing.T) {
+	assert := assert.New(t)
+	var pe *PanicError
+	var source Event
+	d := NewDispatcher(WithPanicHandler(func(e Event, err *PanicError) {
+		source, pe = e, err
+	}))
+	var c int
+	d.On(ErrorEventName, func(e Event) {
+		c++
+	})
+	d.On(TestEventName, func(e Event) {
+		panic("boom")
+	})
+	e := NewParamsEvent(TestEventName)
+	assert.NotPanics(func() {
+		d.Dispatch(e)
+	}, "The listener panic should be recovered!")
+	assert.Equal("boom", pe.Value, "The panic should be passed to the handler!")
+	assert.Equal(e, source)
+	assert.Equal(0, c, "The panic should not be reported!")
+}
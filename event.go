@@ -2,6 +2,10 @@
 // reliable event dispatcher
 package eventdispatcher
 
+import (
+	"reflect"
+)
+
 // Event is an interface used by event dispatcher. Contains name and more custom data
 // May be forced to stop being propagated
 type Event interface {
@@ -24,6 +28,13 @@ type ParamsEvent struct {
 	name                 string
 	isPropagationStopped bool
 	params               map[string]interface{}
+	id                   string
+	correlationID        string
+	causationID          string
+	version              int
+	sequence             uint64
+	priority             Priority
+	changes              *changeset
 }
 
 // Name returns the name of the event
@@ -46,6 +57,7 @@ func (event *ParamsEvent) StopPropagation() {
 // AddParam registers a parameter for the event.
 // Returns this event instance
 func (event *ParamsEvent) SetParam(k string, v interface{}) *ParamsEvent {
+	event.changes.record(event.params, k)
 	event.params[k] = v
 	return event
 }
@@ -54,6 +66,7 @@ func (event *ParamsEvent) SetParam(k string, v interface{}) *ParamsEvent {
 // the param does not exst. Returns this event instance
 func (event *ParamsEvent) RemoveParam(k string) *ParamsEvent {
 	if event.HasParam(k) {
+		event.changes.record(event.params, k)
 		delete(event.params, k)
 	}
 	return event
@@ -76,9 +89,153 @@ func (event *ParamsEvent) GetParam(k string) (value interface{}, ok bool) {
 	return v, ok
 }
 
+// Params returns a copy of all params of the event
+func (event *ParamsEvent) Params() map[string]interface{} {
+	params := make(map[string]interface{}, len(event.params))
+	for k, v := range event.params {
+		params[k] = v
+	}
+	return params
+}
+
+// ID returns the unique identifier of the event, empty until assigned by
+// SetCorrelation, CausedBy or the dispatcher created WithCorrelation
+func (event *ParamsEvent) ID() string {
+	return event.id
+}
+
+// CorrelationID returns the identifier shared by all events of the cascade
+// started by the same root event
+func (event *ParamsEvent) CorrelationID() string {
+	return event.correlationID
+}
+
+// CausationID returns the identifier of the event that caused this one
+func (event *ParamsEvent) CausationID() string {
+	return event.causationID
+}
+
+// SetCorrelation sets the correlation metadata of the event
+func (event *ParamsEvent) SetCorrelation(id string, correlationID string, causationID string) {
+	event.id = id
+	event.correlationID = correlationID
+	event.causationID = causationID
+}
+
+// Version returns the version of the event payload shape
+func (event *ParamsEvent) Version() int {
+	return event.version
+}
+
+// SetVersion sets the version of the event payload shape.
+// Returns this event instance
+func (event *ParamsEvent) SetVersion(v int) *ParamsEvent {
+	event.version = v
+	return event
+}
+
+// Sequence returns the sequence number stamped by DispatchOrdered
+func (event *ParamsEvent) Sequence() uint64 {
+	return event.sequence
+}
+
+// SetSequence sets the sequence number of the event
+func (event *ParamsEvent) SetSequence(seq uint64) {
+	event.sequence = seq
+}
+
+// Priority returns the priority of the event dispatched asynchronously
+func (event *ParamsEvent) Priority() Priority {
+	return event.priority
+}
+
+// SetPriority sets the priority of the event dispatched asynchronously.
+// Returns this event instance
+func (event *ParamsEvent) SetPriority(p Priority) *ParamsEvent {
+	event.priority = p
+	return event
+}
+
+// Reset clears the event name, params and the propagation flag, so the
+// instance may be reused. The params map keeps its allocated memory.
+func (event *ParamsEvent) Reset() {
+	event.name = ""
+	event.isPropagationStopped = false
+	event.id, event.correlationID, event.causationID = "", "", ""
+	event.version = InitialVersion
+	event.sequence = 0
+	event.priority = PriorityNormal
+	event.changes = nil
+	for k := range event.params {
+		delete(event.params, k)
+	}
+}
+
+// Clone returns the copy of the event with its own params map, so the copy
+// may be modified without affecting the original shared by the listeners.
+// The param values themselves are not copied. The propagation of the copy
+// is not stopped and its changes are not tracked. The copy of the event
+// with an ID gets its own one, so it is not deduplicated as the original,
+// while keeping its correlation and causation.
+func (event *ParamsEvent) Clone() *ParamsEvent {
+	c := *event
+	c.isPropagationStopped = false
+	c.changes = nil
+	c.params = event.Params()
+	if c.id != "" {
+		c.id = NewEventID()
+	}
+	return &c
+}
+
+// copyEvent returns the copy of the event e dispatched many times, e.g. to
+// many tenants, so the dispatches do not share its params and propagation.
+// The params events are cloned, also the ones embedded by the events of
+// other types. Returns e itself if it
+// is not a pointer to a struct.
+func copyEvent(e Event) Event {
+	switch ev := e.(type) {
+	case *ParamsEvent:
+		return ev.Clone()
+	case *ConcurrentParamsEvent:
+		ev.RLock()
+		defer ev.RUnlock()
+		return &ConcurrentParamsEvent{event: ev.event.Clone()}
+	}
+
+	v := reflect.ValueOf(e)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return e
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	for i := 0; i < c.Elem().NumField(); i++ {
+		f := c.Elem().Field(i)
+		if f.CanSet() && f.Type() == paramsEventType && f.IsNil() == false {
+			f.Set(reflect.ValueOf(f.Interface().(*ParamsEvent).Clone()))
+		}
+	}
+
+	return c.Interface().(Event)
+}
+
+var paramsEventType = reflect.TypeOf((*ParamsEvent)(nil))
+
+// WithName returns the copy of the event with the name n
+func (event *ParamsEvent) WithName(n string) *ParamsEvent {
+	c := event.Clone()
+	c.name = n
+	return c
+}
+
+// WithParam returns the copy of the event with the param k set to v
+func (event *ParamsEvent) WithParam(k string, v interface{}) *ParamsEvent {
+	return event.Clone().SetParam(k, v)
+}
+
 // NewParamsEvent is a factory for creating a basic event
 func NewParamsEvent(n string) *ParamsEvent {
 	p := make(map[string]interface{})
-	e := ParamsEvent{n, false, p} // Propagation never stopped by default
+	e := ParamsEvent{name: n, params: p, version: InitialVersion} // Propagation never stopped by default
 	return &e
 }
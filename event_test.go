@@ -77,3 +77,50 @@ func TestParams(t *testing.T) {
 	rns, _ := e.GetParam(k)
 	assert.Equal(ns, rns.(testType), fmt.Sprintf("The event does not contain valid param %s", k))
 }
+
+func TestClone(t *testing.T) {
+	assert := assert.New(t)
+	e := NewParamsEvent(TestEventName).SetParam("k", "v").SetVersion(2)
+	e.SetCorrelation("id", "correlation", "cause")
+	e.StopPropagation()
+
+	c := e.Clone()
+	assert.NotSame(e, c, "The clone should be a new instance!")
+	assert.Equal(e.Params(), c.Params(), "The clone should have the same params!")
+	assert.Equal(2, c.Version(), "The clone should have the same version!")
+	assert.Equal("correlation", c.CorrelationID(), "The clone should have the same correlation!")
+	assert.NotEqual("id", c.ID(), "The clone should get its own ID!")
+	assert.NotEqual("", c.ID(), "The clone should get its own ID!")
+	assert.False(c.IsPropagationStopped(), "The propagation of the clone should not be stopped!")
+	c.SetParam("k", "changed")
+	v, _ := e.GetParam("k")
+	assert.Equal("v", v, "Changing the clone should not affect the original!")
+
+	renamed := e.WithName("renamed")
+	assert.Equal("renamed", renamed.Name(), "The copy should have the new name!")
+	assert.Equal(TestEventName, e.Name(), "The original should keep its name!")
+	added := e.WithParam("added", 1)
+	assert.True(added.HasParam("added"), "The copy should have the new param!")
+	assert.False(e.HasParam("added"), "The original should not get the new param!")
+}
+
+func TestCopyEvent(t *testing.T) {
+	assert := assert.New(t)
+	e := NewCancelableEvent(TestEventName)
+	e.SetParam("k", "v")
+	e.SetCorrelation("id", "correlation", "")
+
+	c := copyEvent(e).(*CancelableEvent)
+	assert.NotSame(e.ParamsEvent, c.ParamsEvent, "The embedded params event should be copied!")
+	assert.NotEqual("id", c.ID(), "The copy should get its own ID!")
+	assert.Equal("correlation", c.CorrelationID(), "The copy should keep the correlation!")
+	c.Cancel("vetoed")
+	c.SetParam("k", "changed")
+	v, _ := e.GetParam("k")
+	assert.Equal("v", v, "Changing the copy should not affect the original!")
+	assert.False(e.IsCanceled(), "Canceling the copy should not cancel the original!")
+	assert.False(e.IsPropagationStopped(), "Stopping the copy should not stop the original!")
+
+	p := NewPayloadEvent(TestEventName, 1)
+	assert.NotSame(p, copyEvent(p), "The events of other types should be copied!")
+}
@@ -0,0 +1,95 @@
+package eventdispatchertest
+
+import (
+	ed "github.com/gacek85/eventdispatcher"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the fake ed.Clock moved forward manually with Advance. Sleep
+// advances the clock instead of blocking.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*timer
+}
+
+// timer is the fake ed.Timer fired by Clock.Advance
+type timer struct {
+	c       *Clock
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+// Stop prevents the timer from firing
+func (t *timer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	if t.stopped {
+		return false
+	}
+	t.stopped = true
+
+	return true
+}
+
+// Now returns the current fake time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Sleep advances the clock by the duration d
+func (c *Clock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// AfterFunc registers the function f to be called once the clock is
+// advanced by the duration d
+func (c *Clock) AfterFunc(d time.Duration, f func()) ed.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{c: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+// Advance moves the clock forward by the duration d and synchronously
+// calls the functions of all timers due until then, in their time order
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due, pending []*timer
+	for _, t := range c.timers {
+		if t.stopped {
+			continue
+		}
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+		} else {
+			t.stopped = true
+			due = append(due, t)
+		}
+	}
+	c.timers = pending
+	c.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].at.Before(due[j].at)
+	})
+	for _, t := range due {
+		t.f()
+	}
+}
+
+// NewClock creates the fake clock set to given time
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
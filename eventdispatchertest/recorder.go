@@ -0,0 +1,107 @@
+// Package eventdispatchertest contains the tools for unit testing the code
+// using the event dispatcher: the recording dispatcher, listener spies,
+// assertions and the fake clock.
+package eventdispatchertest
+
+import (
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+	"testing"
+)
+
+// Recorder is the Dispatcher recording all dispatched events. The events
+// are also dispatched to the listeners registered on the recorder.
+type Recorder struct {
+	*ed.EventDispatcher
+	mu     sync.Mutex
+	events []ed.Event
+}
+
+// Dispatch records the event and dispatches it to the listeners
+func (r *Recorder) Dispatch(e ed.Event) ed.Event {
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+
+	return r.EventDispatcher.Dispatch(e)
+}
+
+// DispatchedEvents returns all events dispatched so far in order
+func (r *Recorder) DispatchedEvents() []ed.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]ed.Event(nil), r.events...)
+}
+
+// Dispatched returns the events with given name n dispatched so far
+func (r *Recorder) Dispatched(n string) []ed.Event {
+	var events []ed.Event
+	for _, e := range r.DispatchedEvents() {
+		if e.Name() == n {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// Reset forgets all recorded events
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+// AssertDispatched asserts that the event with given name n has been
+// dispatched at least once
+func (r *Recorder) AssertDispatched(t testing.TB, n string) bool {
+	t.Helper()
+	if len(r.Dispatched(n)) == 0 {
+		t.Errorf("Expected event %q to be dispatched, dispatched events: %v", n, names(r.DispatchedEvents()))
+		return false
+	}
+
+	return true
+}
+
+// AssertNotDispatched asserts that the event with given name n has not
+// been dispatched
+func (r *Recorder) AssertNotDispatched(t testing.TB, n string) bool {
+	t.Helper()
+	if c := len(r.Dispatched(n)); c != 0 {
+		t.Errorf("Expected event %q not to be dispatched, dispatched %d time(s)", n, c)
+		return false
+	}
+
+	return true
+}
+
+// AssertDispatchedTimes asserts that the event with given name n has been
+// dispatched exactly c times
+func (r *Recorder) AssertDispatchedTimes(t testing.TB, n string, c int) bool {
+	t.Helper()
+	if dc := len(r.Dispatched(n)); dc != c {
+		t.Errorf("Expected event %q to be dispatched %d time(s), dispatched %d time(s)", n, c, dc)
+		return false
+	}
+
+	return true
+}
+
+// names returns the names of given events
+func names(events []ed.Event) []string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.Name()
+	}
+
+	return names
+}
+
+// NewRecorder creates the recording dispatcher configured with given
+// options
+func NewRecorder(opts ...ed.Option) *Recorder {
+	return &Recorder{EventDispatcher: ed.NewDispatcher(opts...)}
+}
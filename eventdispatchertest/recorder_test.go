@@ -0,0 +1,108 @@
+package eventdispatchertest
+
+import (
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRecorder()
+	s := NewSpy()
+	r.On("user.created", s.Listener)
+	var d ed.Dispatcher = r
+	d.Dispatch(ed.NewParamsEvent("user.created"))
+	d.Dispatch(ed.NewParamsEvent("user.deleted"))
+
+	assert.Equal(2, len(r.DispatchedEvents()), "All dispatched events should be recorded!")
+	assert.True(r.AssertDispatched(t, "user.created"))
+	assert.True(r.AssertDispatchedTimes(t, "user.deleted", 1))
+	assert.True(r.AssertNotDispatched(t, "user.updated"))
+	assert.True(AssertListenerCalledTimes(t, s, 1))
+
+	mt := &testing.T{}
+	assert.False(r.AssertDispatched(mt, "user.updated"), "The assertion should fail for not dispatched event!")
+	assert.False(AssertListenerNotCalled(mt, s), "The assertion should fail for the called listener!")
+
+	r.Reset()
+	assert.Equal(0, len(r.DispatchedEvents()), "Reset should forget the recorded events!")
+}
+
+func TestClock(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClock(start)
+	var fired []int
+	c.AfterFunc(2*time.Second, func() {
+		fired = append(fired, 2)
+	})
+	c.AfterFunc(time.Second, func() {
+		fired = append(fired, 1)
+	})
+	stopped := c.AfterFunc(time.Second, func() {
+		fired = append(fired, 0)
+	})
+	assert.True(stopped.Stop())
+
+	c.Advance(500 * time.Millisecond)
+	assert.Equal(0, len(fired), "No timers should fire before their time!")
+	c.Sleep(3 * time.Second)
+	assert.Equal([]int{1, 2}, fired, "Due timers should fire in order!")
+	assert.Equal(start.Add(3500*time.Millisecond), c.Now(), "Invalid fake time!")
+}
+
+func TestClockRateLimit(t *testing.T) {
+	assert := assert.New(t)
+	c := NewClock(time.Now())
+	r := NewRecorder(ed.WithClock(c), ed.WithRateLimit("tick", 1, 1, ed.RateLimitDrop))
+	s := NewSpy()
+	r.On("tick", s.Listener)
+	r.Dispatch(ed.NewParamsEvent("tick"))
+	r.Dispatch(ed.NewParamsEvent("tick"))
+	c.Advance(time.Second)
+	r.Dispatch(ed.NewParamsEvent("tick"))
+	assert.Equal(2, len(s.Calls()), "The rate limiter should use the fake clock!")
+}
+
+func TestClockRetry(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Now()
+	c := NewClock(start)
+	r := NewRecorder(ed.WithClock(c))
+	var attempts atomic.Int32
+	r.OnRetry("tick", func(e ed.Event) error {
+		attempts.Add(1)
+		return errors.New("failed")
+	}, &ed.RetryPolicy{MaxAttempts: 2, Backoff: time.Minute})
+	r.Dispatch(ed.NewParamsEvent("tick"))
+	assert.Equal(int32(1), attempts.Load(), "The retry should wait for the backoff!")
+	assert.Equal(start, c.Now(), "The backoff should be scheduled instead of slept!")
+
+	c.Advance(time.Minute)
+	r.Drain()
+	assert.Equal(int32(2), attempts.Load(), "The retry should run once the backoff elapses!")
+}
+
+func TestClockAck(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Now()
+	c := NewClock(start)
+	r := NewRecorder(ed.WithClock(c))
+	var deliveries atomic.Int32
+	r.OnAck("tick", func(e ed.Event) ed.Ack {
+		deliveries.Add(1)
+		return ed.Nacked
+	}, &ed.AckPolicy{MaxAttempts: 2, Timeout: time.Minute})
+	r.Dispatch(ed.NewParamsEvent("tick"))
+	assert.Equal(int32(1), deliveries.Load(), "The redelivery should wait for the timeout!")
+	assert.Equal(start, c.Now(), "The redelivery should be scheduled instead of slept!")
+
+	c.Advance(time.Minute)
+	r.Drain()
+	assert.Equal(int32(2), deliveries.Load(), "The event should be redelivered once the timeout elapses!")
+	assert.Equal(1, len(r.DeadLetters()), "The unacked event should be dead lettered!")
+}
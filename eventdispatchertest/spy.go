@@ -0,0 +1,69 @@
+package eventdispatchertest
+
+import (
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+	"testing"
+)
+
+// Spy records the calls of its Listener method
+type Spy struct {
+	mu     sync.Mutex
+	events []ed.Event
+}
+
+// Listener records the call with the event e
+func (s *Spy) Listener(e ed.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, e)
+}
+
+// Calls returns the events the listener has been called with
+func (s *Spy) Calls() []ed.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]ed.Event(nil), s.events...)
+}
+
+// AssertListenerCalled asserts that the spy listener has been called at
+// least once
+func AssertListenerCalled(t testing.TB, s *Spy) bool {
+	t.Helper()
+	if len(s.Calls()) == 0 {
+		t.Errorf("Expected the listener to be called")
+		return false
+	}
+
+	return true
+}
+
+// AssertListenerNotCalled asserts that the spy listener has not been called
+func AssertListenerNotCalled(t testing.TB, s *Spy) bool {
+	t.Helper()
+	if c := len(s.Calls()); c != 0 {
+		t.Errorf("Expected the listener not to be called, called %d time(s) with: %v", c, names(s.Calls()))
+		return false
+	}
+
+	return true
+}
+
+// AssertListenerCalledTimes asserts that the spy listener has been called
+// exactly c times
+func AssertListenerCalledTimes(t testing.TB, s *Spy, c int) bool {
+	t.Helper()
+	if sc := len(s.Calls()); sc != c {
+		t.Errorf("Expected the listener to be called %d time(s), called %d time(s)", c, sc)
+		return false
+	}
+
+	return true
+}
+
+// NewSpy creates the listener spy
+func NewSpy() *Spy {
+	return &Spy{}
+}
@@ -0,0 +1,60 @@
+// Command eventgen generates the typed event APIs from the event definition
+// file. The package name defaults to $GOPACKAGE set by `go generate`.
+//
+// Usage:
+//
+//	eventgen [-o events_gen.go] [-package name] events.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/gacek85/eventdispatcher/eventgen"
+	"os"
+)
+
+func main() {
+	out := flag.String("o", "", "output file, the standard output if empty")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "name of the generated package, overrides the definition")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: eventgen [-o events_gen.go] [-package name] events.yaml")
+		os.Exit(2)
+	}
+
+	def, err := parseFile(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	if def.Package == "" || *pkg != "" {
+		def.Package = *pkg
+	}
+
+	var buf bytes.Buffer
+	if err := eventgen.Generate(&buf, def); err != nil {
+		fail(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		fail(err)
+	}
+}
+
+func parseFile(path string) (*eventgen.Definition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return eventgen.Parse(f)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
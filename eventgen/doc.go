@@ -0,0 +1,25 @@
+// Package eventgen generates the typed event APIs from the event definition
+// file, removing the boilerplate of the payload events in the consumers.
+//
+// The definition file is YAML or JSON:
+//
+//	package: users
+//	imports:
+//	  - time
+//	events:
+//	  - name: user.created
+//	    doc: is dispatched when the user signs up
+//	    fields:
+//	      - name: ID
+//	        type: string
+//	        json: id
+//	      - name: CreatedAt
+//	        type: time.Time
+//
+// For each event the payload struct (UserCreated), the name constant
+// (UserCreatedEventName), the constructor (NewUserCreated) and the typed
+// OnUserCreated and DispatchUserCreated helpers are generated. Add the
+// directive to any file of the package and run `go generate`:
+//
+//	//go:generate go run github.com/gacek85/eventdispatcher/eventgen/cmd/eventgen -o events_gen.go events.yaml
+package eventgen
@@ -0,0 +1,5 @@
+// Package example contains the typed event APIs generated by eventgen from
+// the events.yaml definition
+package example
+
+//go:generate go run github.com/gacek85/eventdispatcher/eventgen/cmd/eventgen -o events_gen.go events.yaml
@@ -0,0 +1,69 @@
+// Code generated by eventgen. DO NOT EDIT.
+
+package example
+
+import (
+	ed "github.com/gacek85/eventdispatcher"
+	"time"
+)
+
+// UserCreatedEventName is the name of the user.created event
+const UserCreatedEventName = "user.created"
+
+// UserCreated is the payload of the user.created event, which is dispatched when the user signs up
+type UserCreated struct {
+	// ID is the identifier of the user
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewUserCreated creates the user.created event carrying the payload with given fields
+func NewUserCreated(id string, email string, createdAt time.Time) *ed.PayloadEvent[UserCreated] {
+	return ed.NewPayloadEvent(UserCreatedEventName, UserCreated{
+		ID:        id,
+		Email:     email,
+		CreatedAt: createdAt,
+	})
+}
+
+// OnUserCreated registers the listener l receiving the payloads of the user.created events
+func OnUserCreated(d *ed.EventDispatcher, l func(UserCreated)) {
+	ed.OnPayload(d, UserCreatedEventName, l)
+}
+
+// DispatchUserCreated dispatches the user.created event with the payload p.
+// Returns the dispatched event.
+func DispatchUserCreated(d *ed.EventDispatcher, p UserCreated) *ed.PayloadEvent[UserCreated] {
+	return ed.DispatchPayload(d, UserCreatedEventName, p)
+}
+
+// OrderPlacedEventName is the name of the order.placed event
+const OrderPlacedEventName = "order.placed"
+
+// OrderPlaced is the payload of the order.placed event
+type OrderPlaced struct {
+	OrderID string
+	Items   []string
+	Total   float64
+}
+
+// NewOrderPlaced creates the order.placed event carrying the payload with given fields
+func NewOrderPlaced(orderID string, items []string, total float64) *ed.PayloadEvent[OrderPlaced] {
+	return ed.NewPayloadEvent(OrderPlacedEventName, OrderPlaced{
+		OrderID: orderID,
+		Items:   items,
+		Total:   total,
+	})
+}
+
+// OnOrderPlaced registers the listener l receiving the payloads of the order.placed events
+func OnOrderPlaced(d *ed.EventDispatcher, l func(OrderPlaced)) {
+	ed.OnPayload(d, OrderPlacedEventName, l)
+}
+
+// DispatchOrderPlaced dispatches the order.placed event with the payload p.
+// Returns the dispatched event.
+func DispatchOrderPlaced(d *ed.EventDispatcher, p OrderPlaced) *ed.PayloadEvent[OrderPlaced] {
+	return ed.DispatchPayload(d, OrderPlacedEventName, p)
+}
@@ -0,0 +1,34 @@
+package example
+
+import (
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGeneratedEvents(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	var created []UserCreated
+	OnUserCreated(d, func(p UserCreated) {
+		created = append(created, p)
+	})
+
+	now := time.Now()
+	d.Dispatch(NewUserCreated("1", "user@example.com", now))
+	e := DispatchUserCreated(d, UserCreated{ID: "2"})
+	assert.Equal(UserCreatedEventName, e.Name(), "Invalid event name!")
+	assert.Equal(2, len(created), "The listener should receive both payloads!")
+	assert.Equal("user@example.com", created[0].Email)
+	assert.Equal(now, created[0].CreatedAt)
+	assert.Equal("2", created[1].ID)
+
+	placed := 0
+	OnOrderPlaced(d, func(p OrderPlaced) {
+		placed++
+		assert.Equal(2, len(p.Items))
+	})
+	DispatchOrderPlaced(d, OrderPlaced{OrderID: "o1", Items: []string{"a", "b"}})
+	assert.Equal(1, placed, "The order listener should be called!")
+}
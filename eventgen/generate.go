@@ -0,0 +1,222 @@
+package eventgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// ErrInvalidDefinition is returned when the event definition is invalid
+var ErrInvalidDefinition = errors.New("eventgen: invalid definition")
+
+// Field is the field of the event payload
+type Field struct {
+
+	// Name is the exported name of the payload struct field
+	Name string `yaml:"name" json:"name"`
+
+	// Type is the Go type of the field, e.g. string or time.Time
+	Type string `yaml:"type" json:"type"`
+
+	// JSON is the name of the field in the JSON encoding, the field is
+	// encoded under its Go name if empty
+	JSON string `yaml:"json" json:"json"`
+
+	// Doc is the documentation of the field
+	Doc string `yaml:"doc" json:"doc"`
+}
+
+// Event is the definition of the event
+type Event struct {
+
+	// Name is the name the event is dispatched with
+	Name string `yaml:"name" json:"name"`
+
+	// Type is the name of the payload struct, derived from the event name
+	// if empty, e.g. UserCreated for user.created
+	Type string `yaml:"type" json:"type"`
+
+	// Doc is the documentation of the event
+	Doc string `yaml:"doc" json:"doc"`
+
+	// Fields are the fields of the payload
+	Fields []Field `yaml:"fields" json:"fields"`
+}
+
+// Definition is the content of the event definition file
+type Definition struct {
+
+	// Package is the name of the generated package
+	Package string `yaml:"package" json:"package"`
+
+	// Imports are the import paths of the packages of the field types
+	Imports []string `yaml:"imports" json:"imports"`
+
+	// Events are the defined events
+	Events []Event `yaml:"events" json:"events"`
+}
+
+// Parse reads the YAML or JSON event definition from r
+func Parse(r io.Reader) (*Definition, error) {
+	var def Definition
+	if err := yaml.NewDecoder(r).Decode(&def); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDefinition, err)
+	}
+
+	return &def, nil
+}
+
+// Generate writes the formatted source of the typed event APIs of the
+// definition def to w
+func Generate(w io.Writer, def *Definition) error {
+	if err := validate(def); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := source.Execute(&buf, def); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDefinition, err)
+	}
+	_, err = w.Write(src)
+
+	return err
+}
+
+// TypeName derives the name of the payload struct from the event name n,
+// e.g. UserCreated for user.created
+func TypeName(n string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(n, isSeparator) {
+		r := []rune(part)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+
+	return b.String()
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsLetter(r) == false && unicode.IsDigit(r) == false
+}
+
+// validate checks the definition and fills the derived payload type names
+func validate(def *Definition) error {
+	if token.IsIdentifier(def.Package) == false {
+		return fmt.Errorf("%w: invalid package name %q", ErrInvalidDefinition, def.Package)
+	}
+	types := make(map[string]bool)
+	for i := range def.Events {
+		e := &def.Events[i]
+		if e.Name == "" {
+			return fmt.Errorf("%w: event %d has no name", ErrInvalidDefinition, i)
+		}
+		if e.Type == "" {
+			e.Type = TypeName(e.Name)
+		}
+		if token.IsExported(e.Type) == false || token.IsIdentifier(e.Type) == false {
+			return fmt.Errorf("%w: invalid type %q of event %s", ErrInvalidDefinition, e.Type, e.Name)
+		}
+		if types[e.Type] {
+			return fmt.Errorf("%w: duplicated type %s", ErrInvalidDefinition, e.Type)
+		}
+		types[e.Type] = true
+
+		fields := make(map[string]bool)
+		for _, f := range e.Fields {
+			if token.IsExported(f.Name) == false || token.IsIdentifier(f.Name) == false {
+				return fmt.Errorf("%w: invalid field %q of event %s", ErrInvalidDefinition, f.Name, e.Name)
+			}
+			if f.Type == "" {
+				return fmt.Errorf("%w: field %s of event %s has no type", ErrInvalidDefinition, f.Name, e.Name)
+			}
+			if fields[f.Name] {
+				return fmt.Errorf("%w: duplicated field %s of event %s", ErrInvalidDefinition, f.Name, e.Name)
+			}
+			fields[f.Name] = true
+		}
+	}
+
+	return nil
+}
+
+// argName returns the constructor argument name of the field with given
+// name, e.g. createdAt for CreatedAt and id for ID
+func argName(n string) string {
+	r := []rune(n)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		i++
+	}
+	// The last capital of the acronym followed by the lower case starts the
+	// next word, e.g. URLPath
+	if i > 1 && i < len(r) {
+		i--
+	}
+	for j := 0; j < i; j++ {
+		r[j] = unicode.ToLower(r[j])
+	}
+	arg := string(r)
+	// The name of the imported dispatcher package must not be shadowed
+	if token.IsKeyword(arg) || arg == "ed" {
+		arg += "_"
+	}
+
+	return arg
+}
+
+var source = template.Must(template.New("source").Funcs(template.FuncMap{"arg": argName}).Parse(`// Code generated by eventgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	ed "github.com/gacek85/eventdispatcher"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{range .Events}}
+// {{.Type}}EventName is the name of the {{.Name}} event
+const {{.Type}}EventName = {{printf "%q" .Name}}
+
+// {{.Type}} is the payload of the {{.Name}} event{{if .Doc}}, which {{.Doc}}{{end}}
+type {{.Type}} struct {
+{{- range $i, $f := .Fields}}
+{{- if $f.Doc}}
+{{- if $i}}
+{{end}}
+	// {{.Name}} {{.Doc}}
+{{- end}}
+	{{.Name}} {{.Type}}{{if .JSON}} ` + "`json:\"{{.JSON}}\"`" + `{{end}}
+{{- end}}
+}
+
+// New{{.Type}} creates the {{.Name}} event carrying the payload with given fields
+func New{{.Type}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}{{arg $f.Name}} {{$f.Type}}{{end}}) *ed.PayloadEvent[{{.Type}}] {
+	return ed.NewPayloadEvent({{.Type}}EventName, {{.Type}}{
+{{- range .Fields}}
+		{{.Name}}: {{arg .Name}},
+{{- end}}
+	})
+}
+
+// On{{.Type}} registers the listener l receiving the payloads of the {{.Name}} events
+func On{{.Type}}(d *ed.EventDispatcher, l func({{.Type}})) {
+	ed.OnPayload(d, {{.Type}}EventName, l)
+}
+
+// Dispatch{{.Type}} dispatches the {{.Name}} event with the payload p.
+// Returns the dispatched event.
+func Dispatch{{.Type}}(d *ed.EventDispatcher, p {{.Type}}) *ed.PayloadEvent[{{.Type}}] {
+	return ed.DispatchPayload(d, {{.Type}}EventName, p)
+}
+{{end}}`))
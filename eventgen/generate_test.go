@@ -0,0 +1,62 @@
+package eventgen
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerateMatchesExample(t *testing.T) {
+	assert := assert.New(t)
+	f, err := os.Open("example/events.yaml")
+	assert.Nil(err)
+	defer f.Close()
+	def, err := Parse(f)
+	assert.Nil(err)
+
+	var buf bytes.Buffer
+	assert.Nil(Generate(&buf, def))
+	expected, err := os.ReadFile("example/events_gen.go")
+	assert.Nil(err)
+	assert.Equal(string(expected), buf.String(), "The example should be regenerated with go generate!")
+}
+
+func TestParseJSON(t *testing.T) {
+	assert := assert.New(t)
+	def, err := Parse(strings.NewReader(`{"package": "users", "events": [{"name": "user.deleted", "fields": [{"name": "Type", "type": "string"}]}]}`))
+	assert.Nil(err)
+
+	var buf bytes.Buffer
+	assert.Nil(Generate(&buf, def))
+	src := buf.String()
+	assert.Contains(src, "const UserDeletedEventName = \"user.deleted\"", "The type should be derived from the event name!")
+	assert.Contains(src, "func NewUserDeleted(type_ string)", "Keywords should not be used as arguments!")
+}
+
+func TestInvalidDefinition(t *testing.T) {
+	assert := assert.New(t)
+	for _, def := range []*Definition{
+		{Package: ""},
+		{Package: "p", Events: []Event{{Name: ""}}},
+		{Package: "p", Events: []Event{{Name: "a"}, {Name: "A"}}},
+		{Package: "p", Events: []Event{{Name: "a", Fields: []Field{{Name: "id", Type: "string"}}}}},
+		{Package: "p", Events: []Event{{Name: "a", Fields: []Field{{Name: "ID"}}}}},
+		{Package: "p", Events: []Event{{Name: "a", Fields: []Field{{Name: "ID", Type: "[string"}}}}},
+	} {
+		err := Generate(&bytes.Buffer{}, def)
+		assert.True(errors.Is(err, ErrInvalidDefinition), "The definition should be rejected!")
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("UserCreated", TypeName("user.created"))
+	assert.Equal("OrderItemAdded", TypeName("order_item-added"))
+	assert.Equal("id", argName("ID"))
+	assert.Equal("urlPath", argName("URLPath"))
+	assert.Equal("createdAt", argName("CreatedAt"))
+	assert.Equal("ed_", argName("Ed"))
+}
@@ -0,0 +1,72 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+)
+
+// ParamError is the well-known param holding the error the listeners
+// failed the event with. Its value is surfaced by TryDispatch when it is
+// an error.
+const ParamError = "error"
+
+// Failable is the event the listeners may fail, so TryDispatch returns the
+// error after the dispatch. It is the migration path to the listeners
+// returning the errors.
+type Failable interface {
+	Event
+
+	// Fail records the error of the listener handling the event
+	Fail(err error)
+
+	// Err returns the errors recorded, joined, or nil
+	Err() error
+}
+
+// Fail records the error of the listener handling the event in the
+// ParamError param, joined with the errors recorded before. Does not stop
+// the propagation, call StopPropagation to do so.
+func (event *ParamsEvent) Fail(err error) {
+	if err == nil {
+		return
+	}
+	if prev := event.Err(); prev != nil {
+		err = errors.Join(prev, err)
+	}
+	event.SetParam(ParamError, err)
+}
+
+// Err returns the error recorded in the ParamError param, or nil if there
+// is none or the param is not an error
+func (event *ParamsEvent) Err() error {
+	err, _ := event.params[ParamError].(error)
+
+	return err
+}
+
+// Fail records the error of the listener handling the event
+func (event *ConcurrentParamsEvent) Fail(err error) {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.Fail(err)
+}
+
+// Err returns the errors recorded, joined, or nil
+func (event *ConcurrentParamsEvent) Err() error {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.Err()
+}
+
+// failure returns the error the listeners failed the event with, if it is
+// Failable
+func failure(e Event) error {
+	if fe, ok := e.(Failable); ok {
+		return fe.Err()
+	}
+
+	return nil
+}
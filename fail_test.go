@@ -0,0 +1,39 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFail(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	errA, errB := errors.New("a"), errors.New("b")
+	d.On(TestEventName, func(e Event) {
+		e.(Failable).Fail(errA)
+	})
+	d.On(TestEventName, func(e Event) {
+		e.(Failable).Fail(errB)
+	})
+	_, err := d.TryDispatch(NewParamsEvent(TestEventName))
+	assert.ErrorIs(err, errA, "The errors of all listeners should be returned!")
+	assert.ErrorIs(err, errB, "The errors of all listeners should be returned!")
+
+	_, err = d.TryDispatch(NewConcurrentParamsEvent(TestEventName))
+	assert.ErrorIs(err, errA, "The concurrent event should be failable too!")
+}
+
+func TestErrParam(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.On(TestEventName, func(e Event) {
+		e.(*ParamsEvent).SetParam(ParamError, errors.New("failed"))
+	})
+	_, err := d.TryDispatch(NewParamsEvent(TestEventName))
+	assert.EqualError(err, "failed", "The error param should be returned!")
+	_, err = d.TryDispatch(NewParamsEvent("other").SetParam(ParamError, "not an error"))
+	assert.Nil(err, "The error param not being an error should be ignored!")
+}
@@ -0,0 +1,78 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// Filter is a pipeline stage applied to the event before it reaches the
+// listeners. Returns the event to be dispatched further (may be the same
+// instance, a modified or a completely new one) and false if the event
+// should be dropped.
+type Filter func(Event) (Event, bool)
+
+// AddFilter registers a filter applied to all dispatched events. Global
+// filters are applied in the order of registration, before the filters
+// registered for the event name.
+func (d *EventDispatcher) AddFilter(f Filter) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	d.filters = append(d.filters, f)
+}
+
+// AddFilterOn registers a filter applied to the events with given name n
+// (may contain many space separated names).
+func (d *EventDispatcher) AddFilterOn(n string, f Filter) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	for _, name := range getNames(n) {
+		name = normalize(d, name)
+		d.namedFilters[name] = append(d.namedFilters[name], f)
+	}
+}
+
+// filter passes the event dispatched under name n through the global
+// filters and then through the filters registered for the name. Returns the
+// resulting event, the name it should be dispatched under and false if any
+// of the filters dropped it. If a filter changes the event name, the event
+// is dispatched under the new one and passed through the filters registered
+// for it instead of the remaining filters of the old name. The filters of
+// each name are applied at most once, so the renaming filters cannot loop.
+func filter(d *EventDispatcher, n string, e Event) (Event, string, bool) {
+	d.RWMutex.RLock()
+	global := d.filters
+	d.RWMutex.RUnlock()
+
+	for _, f := range global {
+		fe, ok := f(e)
+		if ok == false {
+			return e, n, false
+		}
+		if fe.Name() != e.Name() {
+			n = normalize(d, fe.Name())
+		}
+		e = fe
+	}
+
+	applied := make(map[string]bool)
+	for applied[n] == false {
+		applied[n] = true
+		d.RWMutex.RLock()
+		named := d.namedFilters[n]
+		d.RWMutex.RUnlock()
+
+		for _, f := range named {
+			fe, ok := f(e)
+			if ok == false {
+				return e, n, false
+			}
+			renamed := fe.Name() != e.Name()
+			e = fe
+			if renamed {
+				n = normalize(d, fe.Name())
+				break
+			}
+		}
+	}
+
+	return e, n, true
+}
@@ -0,0 +1,84 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFilterTransform(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.AddFilter(func(e Event) (Event, bool) {
+		e.(*ParamsEvent).SetParam("tenant", "acme")
+		return e, true
+	})
+	var tenant interface{}
+	d.On(TestEventName, func(e Event) {
+		tenant, _ = e.(*ParamsEvent).GetParam("tenant")
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal("acme", tenant, "The global filter should enrich the event!")
+}
+
+func TestFilterDrop(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.On("event_1 event_2", func(e Event) {
+		c++
+	})
+	d.AddFilterOn("event_1", func(e Event) (Event, bool) {
+		return e, false
+	})
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent("event_2"))
+	assert.Equal(1, c, "Only the event not dropped by the filter should reach the listener!")
+}
+
+func TestFilterReplace(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.AddFilterOn("legacy_event", func(e Event) (Event, bool) {
+		return NewParamsEvent(TestEventName), true
+	})
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	re := d.Dispatch(NewParamsEvent("legacy_event"))
+	assert.Equal(1, c, "The replaced event should be dispatched to its listeners!")
+	assert.Equal(TestEventName, re.Name(), "Dispatch should return the filtered event!")
+}
+
+func TestFilterRenamedEventFilters(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var applied []string
+	d.AddFilterOn("legacy_event", func(e Event) (Event, bool) {
+		applied = append(applied, "legacy")
+		return NewParamsEvent(TestEventName), true
+	})
+	d.AddFilterOn("legacy_event", func(e Event) (Event, bool) {
+		applied = append(applied, "legacy_skipped")
+		return e, true
+	})
+	d.AddFilterOn(TestEventName, func(e Event) (Event, bool) {
+		applied = append(applied, "renamed")
+		return NewParamsEvent("legacy_event"), e.(*ParamsEvent).HasParam("drop") == false
+	})
+	d.On(TestEventName, func(e Event) {
+		applied = append(applied, "listener")
+	})
+	d.On("legacy_event", func(e Event) {
+		applied = append(applied, "legacy_listener")
+	})
+
+	d.Dispatch(NewParamsEvent("legacy_event"))
+	assert.Equal([]string{"legacy", "renamed", "legacy_listener"}, applied, "The filters of the new name should be applied once!")
+
+	applied = nil
+	d.Dispatch(NewParamsEvent(TestEventName).SetParam("drop", true))
+	assert.Equal([]string{"renamed"}, applied, "The filter of the current name should drop the event!")
+}
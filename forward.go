@@ -0,0 +1,167 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"reflect"
+	"slices"
+)
+
+// forwardRule is the rule forwarding the events between the dispatchers
+type forwardRule struct {
+	patterns  []string
+	predicate func(Event) bool
+	transform Transform
+}
+
+// ForwardOption configures the forwarding rule
+type ForwardOption func(*forwardRule)
+
+// ForwardNames forwards the events with the names matching any of the
+// patterns only, e.g. "order.*" or "billing.**". The wildcards are matched
+// even if the source dispatcher does not match them.
+func ForwardNames(patterns ...string) ForwardOption {
+	return func(r *forwardRule) {
+		r.patterns = append(r.patterns, patterns...)
+	}
+}
+
+// ForwardIf forwards the events the predicate returns true for only
+func ForwardIf(predicate func(Event) bool) ForwardOption {
+	return func(r *forwardRule) {
+		r.predicate = predicate
+	}
+}
+
+// ForwardTransform forwards the events created with the transform t
+// instead of the original ones. Returning nil forwards no event. The
+// created event inherits from the original one like the derived events
+// do, see WithInheritance of the source dispatcher.
+func ForwardTransform(t Transform) ForwardOption {
+	return func(r *forwardRule) {
+		r.transform = t
+	}
+}
+
+// Forward dispatches the events dispatched by src, after its listeners are
+// done, also with dst, composing the dispatchers into the routed topology.
+// Unlike Attach, the forwarded events may be filtered and transformed, and
+// the cycles of the rules are allowed: the event is never forwarded to the
+// dispatcher it has already passed through. The cycles are tracked for the
+// EventDispatcher and NamespaceView destinations only. Returns the
+// subscription removing the rule.
+func Forward(src *EventDispatcher, dst Dispatcher, opts ...ForwardOption) *Subscription {
+	r := &forwardRule{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	s := src.OnAny(func(e Event) {
+		if r.matches(e) {
+			forwardTo(src, dst, r, e)
+		}
+	})
+	patterns := r.patterns
+	if len(patterns) == 0 {
+		patterns = []string{TopologyAny}
+	}
+	var removes []func()
+	for _, p := range patterns {
+		removes = append(removes, src.AddTopologyEdge(TopologyEdge{p, DispatcherNode(dst), TopologyForward}))
+	}
+	stop := s.stop
+	s.stop = func() bool {
+		for _, remove := range removes {
+			remove()
+		}
+		return stop()
+	}
+
+	return s
+}
+
+// matches informs whether the event e should be forwarded
+func (r *forwardRule) matches(e Event) bool {
+	if len(r.patterns) != 0 && slices.IndexFunc(r.patterns, func(p string) bool {
+		return p == e.Name() || matchPattern(p, e.Name())
+	}) == -1 {
+		return false
+	}
+
+	return r.predicate == nil || r.predicate(e)
+}
+
+// forwardTo dispatches the event e dispatched by src with dst, unless it
+// has already passed through dst
+func forwardTo(src *EventDispatcher, dst Dispatcher, r *forwardRule, e Event) {
+	var path []Dispatcher
+	if key := forwardKey(e); key != nil {
+		if p, ok := src.forwarded.Load(key); ok {
+			path = p.([]Dispatcher)
+		}
+	}
+	passed := func(d Dispatcher) bool {
+		return sameDispatcher(d, dst)
+	}
+	if slices.ContainsFunc(path, passed) || passed(src) {
+		return
+	}
+
+	fe := e
+	if r.transform != nil {
+		if fe = r.transform(e); fe == nil {
+			return
+		}
+		if fe != e {
+			src.inheritance.Apply(fe, e)
+		}
+	}
+	path = slices.Clip(path)
+	if slices.ContainsFunc(path, func(d Dispatcher) bool {
+		return sameDispatcher(d, src)
+	}) == false {
+		path = append(path, src)
+	}
+	path = append(path, dst)
+
+	// The path is kept by the dispatcher receiving the event, so it finds
+	// the path once it forwards the event further
+	var rd *EventDispatcher
+	dispatch := func() {
+		dst.Dispatch(fe)
+	}
+	switch d := dst.(type) {
+	case *EventDispatcher:
+		rd = d
+	case *NamespaceView:
+		rd, fe = d.d, d.namespaced(fe)
+		dispatch = func() {
+			tryDispatch(rd, fe.Name(), fe, nil)
+		}
+	}
+	key := forwardKey(fe)
+	if rd == nil || key == nil {
+		dispatch()
+		return
+	}
+	prev, ok := rd.forwarded.Swap(key, path)
+	defer func() {
+		if ok {
+			rd.forwarded.Store(key, prev)
+			return
+		}
+		rd.forwarded.Delete(key)
+	}()
+	dispatch()
+}
+
+// forwardKey returns the key of the event e in the forwarded map, or nil
+// if the event may not be the key, e.g. it is not a pointer to the event
+// holding the params map
+func forwardKey(e Event) interface{} {
+	if reflect.TypeOf(e).Comparable() == false {
+		return nil
+	}
+
+	return e
+}
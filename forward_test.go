@@ -0,0 +1,100 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestForward(t *testing.T) {
+	assert := assert.New(t)
+	src, dst := NewDispatcher(), NewDispatcher()
+	var got []string
+	dst.On("order.paid order.canceled user.created", func(e Event) {
+		got = append(got, e.Name())
+	})
+	s := Forward(src, dst, ForwardNames("order.*"), ForwardIf(func(e Event) bool {
+		return e.Name() != "order.canceled"
+	}))
+
+	src.Dispatch(NewParamsEvent("order.paid"))
+	src.Dispatch(NewParamsEvent("order.canceled"))
+	src.Dispatch(NewParamsEvent("user.created"))
+	s.Unsubscribe()
+	src.Dispatch(NewParamsEvent("order.paid"))
+	assert.Equal([]string{"order.paid"}, got, "Only the matching events should be forwarded!")
+}
+
+func TestForwardTransform(t *testing.T) {
+	assert := assert.New(t)
+	src, dst := NewDispatcher(), NewDispatcher()
+	var got *ParamsEvent
+	dst.On("public.order.paid", func(e Event) {
+		got = e.(*ParamsEvent)
+	})
+	Forward(src, dst, ForwardTransform(func(e Event) Event {
+		if e.Name() == "internal" {
+			return nil
+		}
+		return NewParamsEvent("public." + e.Name())
+	}))
+
+	src.Dispatch(NewParamsEvent("internal"))
+	assert.Nil(got, "The event should not be forwarded if the transform returns nil!")
+	e := NewParamsEvent("order.paid").SetPriority(PriorityHigh)
+	src.Dispatch(e)
+	assert.NotNil(got, "The transformed event should be forwarded!")
+	assert.Equal(PriorityHigh, got.Priority(), "The transformed event should inherit the priority!")
+	assert.Equal(e.ID(), got.CausationID(), "The transformed event should be caused by the original!")
+}
+
+func TestForwardCycle(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := NewDispatcher(), NewDispatcher(), NewDispatcher()
+	calls := make(map[string]int)
+	for name, d := range map[string]*EventDispatcher{"a": a, "b": b, "c": c} {
+		name := name
+		d.On(TestEventName, func(e Event) {
+			calls[name]++
+		})
+	}
+	Forward(a, b)
+	Forward(b, a)
+	Forward(b, c)
+	Forward(c, a)
+
+	a.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(map[string]int{"a": 1, "b": 1, "c": 1}, calls, "The event should pass through each dispatcher once!")
+}
+
+func TestForwardUncomparable(t *testing.T) {
+	assert := assert.New(t)
+	a := NewDispatcher()
+	b := mapDispatcher{NewDispatcher(), map[string]int{}}
+	c := mapDispatcher{NewDispatcher(), map[string]int{}}
+	var calls int
+	c.On(TestEventName, func(e Event) {
+		calls++
+	})
+	Forward(a, b)
+	Forward(b.EventDispatcher, c)
+
+	assert.NotPanics(func() {
+		a.Dispatch(NewParamsEvent(TestEventName))
+	}, "The uncomparable destinations should not panic!")
+	assert.Equal(1, calls, "The event should be forwarded through the uncomparable destinations!")
+}
+
+func TestForwardNamespaceCycle(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	d.OnAny(func(e Event) {
+		got = append(got, e.Name())
+	})
+	Forward(d, d.Namespace("audit"))
+
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{TestEventName, "audit." + TestEventName}, got, "The event should pass through the namespace once!")
+}
@@ -0,0 +1,129 @@
+// Package fswatch watches the filesystem paths with fsnotify and dispatches
+// the changes as events, turning the dispatcher into a file watch hub.
+package fswatch
+
+import (
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+)
+
+const (
+	// CreatedEventName is the name of the event dispatched when the file
+	// or directory is created
+	CreatedEventName = "fs.created"
+
+	// ModifiedEventName is the name of the event dispatched when the file
+	// is written to or its attributes change
+	ModifiedEventName = "fs.modified"
+
+	// RemovedEventName is the name of the event dispatched when the file
+	// or directory is removed or renamed
+	RemovedEventName = "fs.removed"
+
+	// ErrorEventName is the name of the source event of the watcher
+	// errors reported with ReportError
+	ErrorEventName = "fs.error"
+
+	// ParamPath is the param of the events holding the changed path
+	ParamPath = "path"
+
+	// ParamOp is the param of the events holding the fsnotify operation
+	ParamOp = "op"
+)
+
+// Watcher dispatches the changes of the watched paths
+type Watcher struct {
+	d    *ed.EventDispatcher
+	w    *fsnotify.Watcher
+	done chan struct{}
+	once sync.Once
+}
+
+// Add starts watching the path. Directories are watched non-recursively.
+func (w *Watcher) Add(path string) error {
+	if err := w.w.Add(path); err != nil {
+		return fmt.Errorf("fswatch: %w", err)
+	}
+
+	return nil
+}
+
+// Remove stops watching the path
+func (w *Watcher) Remove(path string) error {
+	if err := w.w.Remove(path); err != nil {
+		return fmt.Errorf("fswatch: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops watching all paths
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		err = w.w.Close()
+		<-w.done
+	})
+
+	return err
+}
+
+// run dispatches the fsnotify events until the watcher is closed
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case fe, ok := <-w.w.Events:
+			if ok == false {
+				return
+			}
+			if e := NewEvent(fe); e != nil {
+				w.d.Dispatch(e)
+			}
+		case err, ok := <-w.w.Errors:
+			if ok == false {
+				return
+			}
+			w.d.ReportError(ed.NewParamsEvent(ErrorEventName), fmt.Errorf("fswatch: %w", err))
+		}
+	}
+}
+
+// NewEvent converts the fsnotify event to the dispatched one. Returns nil if
+// the operation is not relayed.
+func NewEvent(fe fsnotify.Event) *ed.ParamsEvent {
+	var n string
+	switch {
+	case fe.Has(fsnotify.Create):
+		n = CreatedEventName
+	case fe.Has(fsnotify.Remove), fe.Has(fsnotify.Rename):
+		n = RemovedEventName
+	case fe.Has(fsnotify.Write), fe.Has(fsnotify.Chmod):
+		n = ModifiedEventName
+	default:
+		return nil
+	}
+
+	return ed.NewParamsEvent(n).SetParam(ParamPath, fe.Name).SetParam(ParamOp, fe.Op)
+}
+
+// New creates the watcher dispatching the changes of given paths with the
+// dispatcher d
+func New(d *ed.EventDispatcher, paths ...string) (*Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("fswatch: %w", err)
+	}
+	w := &Watcher{d: d, w: fw, done: make(chan struct{})}
+	go w.run()
+	for _, path := range paths {
+		if err := w.Add(path); err != nil {
+			w.Close()
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
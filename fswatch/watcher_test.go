@@ -0,0 +1,59 @@
+package fswatch
+
+import (
+	"github.com/fsnotify/fsnotify"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	d := ed.NewDispatcher()
+	events := make(chan ed.Event, 16)
+	d.On(CreatedEventName+" "+ModifiedEventName+" "+RemovedEventName, func(e ed.Event) {
+		events <- e
+	})
+	w, err := New(d, dir)
+	assert.Nil(err)
+	defer w.Close()
+
+	path := filepath.Join(dir, "file.txt")
+	assert.Nil(os.WriteFile(path, []byte("a"), 0o600))
+	waitFor(t, events, CreatedEventName, path)
+	assert.Nil(os.Remove(path))
+	waitFor(t, events, RemovedEventName, path)
+}
+
+func waitFor(t *testing.T, events chan ed.Event, n string, path string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-events:
+			p, _ := e.(*ed.ParamsEvent).GetParam(ParamPath)
+			if e.Name() == n && p == path {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("The %s event for %s should be dispatched!", n, path)
+		}
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	assert := assert.New(t)
+	e := NewEvent(fsnotify.Event{Name: "a", Op: fsnotify.Write})
+	assert.Equal(ModifiedEventName, e.Name())
+	p, _ := e.GetParam(ParamPath)
+	assert.Equal("a", p)
+	assert.Equal(RemovedEventName, NewEvent(fsnotify.Event{Name: "a", Op: fsnotify.Rename}).Name())
+}
+
+func TestNewMissingPath(t *testing.T) {
+	_, err := New(ed.NewDispatcher(), filepath.Join(t.TempDir(), "missing"))
+	assert.NotNil(t, err, "Watching the missing path should fail!")
+}
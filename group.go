@@ -0,0 +1,188 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ListenerGroup registers related listeners together, so they may be
+// disabled, enabled or removed at once
+type ListenerGroup struct {
+	sync.Mutex
+	d         *EventDispatcher
+	name      string
+	disabled  int32
+	listeners []groupListener
+}
+
+// groupListener binds the listener registered within the group with the
+// identifier of its wrapper registered in the dispatcher
+type groupListener struct {
+	n  string
+	l  Listener
+	id uint64
+}
+
+// Group returns the listener group with given name, creating it if it does
+// not exist yet
+func (d *EventDispatcher) Group(name string) *ListenerGroup {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	g, ok := d.groups[name]
+	if ok == false {
+		g = &ListenerGroup{d: d, name: name}
+		d.groups[name] = g
+	}
+
+	return g
+}
+
+// Name returns the name of the group
+func (g *ListenerGroup) Name() string {
+	return g.name
+}
+
+// On registers a listener within the group for given event name.
+func (g *ListenerGroup) On(n string, l Listener) {
+	for _, name := range getNames(n) {
+		w := func(e Event) {
+			if g.IsEnabled() {
+				l(e)
+			}
+		}
+		addToGroup(g, name, nextID(g.d), l, w)
+	}
+}
+
+// Once registers a listener within the group to be executed only once.
+// Events dispatched while the group is disabled do not consume the call.
+func (g *ListenerGroup) Once(n string, l Listener) {
+	for _, name := range getNames(n) {
+		name := name
+		id := nextID(g.d)
+		var fired int32
+		w := func(e Event) {
+			if g.IsEnabled() && atomic.CompareAndSwapInt32(&fired, 0, 1) {
+				removeFromGroup(g, name, id)
+				l(e)
+			}
+		}
+		addToGroup(g, name, id, l, w)
+	}
+}
+
+// Off removes all registrations of the listener within the group for given
+// event name. Returns the number of listeners removed.
+func (g *ListenerGroup) Off(n string, l Listener) int {
+	if rejectSealed(g.d, n) {
+		return 0
+	}
+	p := reflect.ValueOf(l).Pointer()
+	var removed int
+	for _, gl := range listenersOf(g) {
+		if gl.n == n && reflect.ValueOf(gl.l).Pointer() == p {
+			removeFromGroup(g, gl.n, gl.id)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// OffAll removes all listeners registered within the group for given name.
+func (g *ListenerGroup) OffAll(n string) {
+	if rejectSealed(g.d, n) {
+		return
+	}
+	for _, gl := range listenersOf(g) {
+		if gl.n == n {
+			removeFromGroup(g, gl.n, gl.id)
+		}
+	}
+}
+
+// HasListeners returns true if any listener for given event name has been
+// registered within the group and false otherwise
+func (g *ListenerGroup) HasListeners(n string) bool {
+	for _, gl := range listenersOf(g) {
+		if gl.n == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Dispatch dispatches the event with the dispatcher of the group, so the
+// group may be passed around as the Dispatcher scoped to its listeners
+func (g *ListenerGroup) Dispatch(e Event) Event {
+	return g.d.Dispatch(e)
+}
+
+// RemoveAll removes all listeners registered within the group.
+func (g *ListenerGroup) RemoveAll() {
+	listeners := listenersOf(g)
+	if len(listeners) != 0 && rejectSealed(g.d, listeners[0].n) {
+		return
+	}
+	for _, gl := range listeners {
+		removeFromGroup(g, gl.n, gl.id)
+	}
+}
+
+// Disable makes the listeners of the group ignore dispatched events until
+// the group is enabled again
+func (g *ListenerGroup) Disable() {
+	atomic.StoreInt32(&g.disabled, 1)
+}
+
+// Enable makes the listeners of the group handle dispatched events again
+func (g *ListenerGroup) Enable() {
+	atomic.StoreInt32(&g.disabled, 0)
+}
+
+// IsEnabled informs whether the listeners of the group handle the events
+func (g *ListenerGroup) IsEnabled() bool {
+	return atomic.LoadInt32(&g.disabled) == 0
+}
+
+// addToGroup registers the wrapper w of listener l under given identifier
+// in the dispatcher and the group
+func addToGroup(g *ListenerGroup, n string, id uint64, l Listener, w Listener) {
+	if rejectSealed(g.d, n) {
+		return
+	}
+	g.Lock()
+	g.listeners = append(g.listeners, groupListener{n, l, id})
+	g.Unlock()
+
+	onID(g.d, n, id, w)
+}
+
+// removeFromGroup removes the wrapper registered under given identifier
+// from the dispatcher and the group
+func removeFromGroup(g *ListenerGroup, n string, id uint64) {
+	offID(g.d, n, id)
+
+	g.Lock()
+	defer g.Unlock()
+	var listeners []groupListener
+	for _, gl := range g.listeners {
+		if gl.id != id {
+			listeners = append(listeners, gl)
+		}
+	}
+	g.listeners = listeners
+}
+
+// listenersOf returns a copy of the listeners registered within the group
+func listenersOf(g *ListenerGroup) []groupListener {
+	g.Lock()
+	defer g.Unlock()
+
+	return append([]groupListener(nil), g.listeners...)
+}
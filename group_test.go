@@ -0,0 +1,93 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGroupEnableDisable(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	g := d.Group("debug")
+	assert.Equal(g, d.Group("debug"), "The same group instance should be returned for the name!")
+	var c, oc int
+	g.On("event_1 event_2", func(e Event) {
+		c++
+	})
+	g.Once(TestEventName, func(e Event) {
+		oc++
+	})
+
+	g.Disable()
+	assert.False(g.IsEnabled(), "The group should be disabled!")
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(0, c, "Disabled listeners should not be called!")
+	assert.True(d.HasListeners(TestEventName), "Disabled once listener should not be consumed!")
+
+	g.Enable()
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent("event_2"))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(2, c, "Enabled listeners should be called!")
+	assert.Equal(1, oc, "The once listener should be called only once!")
+	assert.False(d.HasListeners(TestEventName), "The once listener should unbind itself!")
+}
+
+func TestGroupRemoveAll(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	l := func(e Event) {
+		c++
+	}
+	d.On(TestEventName, l)
+	g := d.Group("feature")
+	g.On(TestEventName, l)
+	g.On("other_event", l)
+
+	g.Off("other_event", l)
+	assert.False(d.HasListeners("other_event"), "The group listener should be removed!")
+
+	g.RemoveAll()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "Only the listener registered outside the group should remain!")
+}
+
+func TestGroupsSharingEventName(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var a, b int
+	d.Group("a").On(TestEventName, func(e Event) {
+		a++
+	})
+	d.Group("b").On(TestEventName, func(e Event) {
+		b++
+	})
+	d.Group("a").RemoveAll()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(0, a, "Listeners of the removed group should not be called!")
+	assert.Equal(1, b, "Removing a group should not affect other groups!")
+}
+
+func TestGroupAsDispatcher(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var gd Dispatcher = d.Group("plugin")
+	var c int
+	gd.On("a b", func(e Event) {
+		c++
+	})
+	d.On("a", func(e Event) {
+		c++
+	})
+	gd.Dispatch(NewParamsEvent("a"))
+	assert.Equal(2, c, "The group should dispatch with its dispatcher!")
+	gd.OffAll("a")
+	assert.False(gd.HasListeners("a"), "The group listeners should be removed!")
+	assert.True(gd.HasListeners("b"), "Listeners of other names should be kept!")
+	assert.True(d.HasListeners("a"), "Listeners outside the group should be kept!")
+}
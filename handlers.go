@@ -0,0 +1,124 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+const (
+	// HandlerMethodPrefix is the prefix of the method names registered as
+	// listeners by RegisterHandlers
+	HandlerMethodPrefix = "On"
+
+	// HandlerTag is the struct tag holding the event names the listener
+	// field is registered for by RegisterHandlers
+	HandlerTag = "event"
+)
+
+// ErrInvalidHandlers is returned by RegisterHandlers when the handlers
+// object is nil or is not a struct
+var ErrInvalidHandlers = errors.New("eventdispatcher: invalid handlers")
+
+var eventType = reflect.TypeOf((*Event)(nil)).Elem()
+
+// RegisterHandlers registers the handlers found on obj as listeners and
+// returns a function removing all of them. Handlers are:
+//
+//   - methods named OnXxx taking a single Event param and returning
+//     nothing, registered for the event name made of Xxx converted to
+//     snake case (OnUserCreated listens on "user_created"),
+//   - exported struct fields of Listener or func(Event) type tagged with
+//     `event:"name"`, registered for the (space separated) names of the tag.
+//
+// Returns ErrInvalidHandlers if obj is nil or is neither a struct nor
+// a pointer to a struct, nothing is registered then.
+func (d *EventDispatcher) RegisterHandlers(obj interface{}) (func(), error) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Pointer && v.IsNil() || reflect.Indirect(v).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T", ErrInvalidHandlers, obj)
+	}
+
+	var registrations []registration
+	register := func(n string, l Listener) {
+		for _, name := range getNames(n) {
+			registrations = append(registrations, registration{name, on(d, name, l)})
+		}
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if isHandlerMethod(m) {
+			register(handlerEventName(m.Name), v.Method(i).Interface().(func(Event)))
+		}
+	}
+
+	sv := reflect.Indirect(v)
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		n, ok := f.Tag.Lookup(HandlerTag)
+		if ok == false || f.PkgPath != "" || isNilHandler(sv.Field(i)) {
+			continue
+		}
+		switch l := sv.Field(i).Interface().(type) {
+		case Listener:
+			register(n, l)
+		case func(Event):
+			register(n, l)
+		}
+	}
+
+	return func() {
+		offRegistrations(d, registrations)
+	}, nil
+}
+
+// isNilHandler informs whether the handler field value v cannot be
+// registered, because it is nil or cannot hold a function at all
+func isNilHandler(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Func, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return true
+}
+
+// isHandlerMethod informs whether the method m should be registered as
+// a listener
+func isHandlerMethod(m reflect.Method) bool {
+	if len(m.Name) <= len(HandlerMethodPrefix) || strings.HasPrefix(m.Name, HandlerMethodPrefix) == false {
+		return false
+	}
+	if unicode.IsUpper(rune(m.Name[len(HandlerMethodPrefix)])) == false {
+		return false
+	}
+
+	// The receiver is the first input param of the method
+	mt := m.Type
+	return mt.NumIn() == 2 && mt.In(1) == eventType && mt.NumOut() == 0
+}
+
+// handlerEventName converts the handler method name to the snake case
+// event name, e.g. OnHTTPRequestDone becomes "http_request_done"
+func handlerEventName(m string) string {
+	r := []rune(strings.TrimPrefix(m, HandlerMethodPrefix))
+	var b strings.Builder
+	for i, c := range r {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(r[i-1]) || (i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(r[i-1]))) {
+				b.WriteRune('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,71 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testHandlers struct {
+	names   []string
+	Created Listener    `event:"user.created user.restored"`
+	Deleted func(Event) `event:"user.deleted"`
+	Ignored Listener
+	Count   int `event:"user.counted"`
+}
+
+func (h *testHandlers) OnUserUpdated(e Event) {
+	h.names = append(h.names, e.Name())
+}
+
+func (h *testHandlers) OnHTTPRequest(e Event) {
+	h.names = append(h.names, e.Name())
+}
+
+func (h *testHandlers) OnInvalid(e Event) bool {
+	return true
+}
+
+func (h *testHandlers) Online(e Event) {
+	h.names = append(h.names, "online")
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	h := &testHandlers{}
+	h.Created = func(e Event) {
+		h.names = append(h.names, e.Name())
+	}
+	h.Deleted = h.Created
+	unregister, err := d.RegisterHandlers(h)
+	assert.Nil(err, "The handlers should be registered!")
+
+	for _, n := range []string{"user_updated", "http_request", "user.created", "user.restored", "user.deleted", "invalid", "line"} {
+		d.Dispatch(NewParamsEvent(n))
+	}
+	assert.Equal([]string{"user_updated", "http_request", "user.created", "user.restored", "user.deleted"}, h.names, "Invalid handlers called!")
+
+	unregister()
+	for _, n := range []string{"user_updated", "http_request", "user.created", "user.restored", "user.deleted"} {
+		assert.False(d.HasListeners(n), "All handlers should be unregistered!")
+	}
+}
+
+func TestRegisterInvalidHandlers(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var h *testHandlers
+	for _, obj := range []interface{}{nil, h, 42, func(e Event) {}} {
+		_, err := d.RegisterHandlers(obj)
+		assert.ErrorIs(err, ErrInvalidHandlers, "Only the structs should be accepted as handlers!")
+	}
+}
+
+func TestHandlerEventName(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("user_created", handlerEventName("OnUserCreated"))
+	assert.Equal("http_request_done", handlerEventName("OnHTTPRequestDone"))
+	assert.Equal("user_id", handlerEventName("OnUserID"))
+}
@@ -0,0 +1,180 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DispatchRecord describes a single dispatch remembered by the dispatcher
+// created WithHistory
+type DispatchRecord struct {
+
+	// Name is the name the event has been dispatched under
+	Name string
+
+	// Time is the moment the dispatch started
+	Time time.Time
+
+	// Listeners is the number of listeners called
+	Listeners int
+
+	// Duration is the time all listeners took
+	Duration time.Duration
+
+	// Errors are the errors reported by the listeners
+	Errors []error
+
+	// Params is the summary of the event params after the dispatch
+	Params string
+
+	e Event
+}
+
+// DispatchObserver is notified with the record of each finished dispatch
+type DispatchObserver func(DispatchRecord)
+
+// history is the ring buffer of the last dispatch records. It also tracks
+// the records of the dispatches in flight and notifies the observers.
+type history struct {
+	sync.Mutex
+	records   []DispatchRecord
+	next      int
+	full      bool
+	inFlight  []*DispatchRecord
+	observers []DispatchObserver
+}
+
+// WithHistory makes the dispatcher remember the last size dispatches,
+// accessible with History
+func WithHistory(size int) Option {
+	return func(d *EventDispatcher) {
+		if size > 0 {
+			d.history.records = make([]DispatchRecord, size)
+		}
+	}
+}
+
+// WithDispatchObserver registers the function notified with the record of
+// each finished dispatch
+func WithDispatchObserver(o DispatchObserver) Option {
+	return func(d *EventDispatcher) {
+		d.history.observers = append(d.history.observers, o)
+	}
+}
+
+// History returns the remembered dispatch records, oldest first. Returns
+// nil unless the dispatcher was created WithHistory.
+func (d *EventDispatcher) History() []DispatchRecord {
+	h := d.history
+	if len(h.records) == 0 {
+		return nil
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	if h.full == false {
+		return append([]DispatchRecord(nil), h.records[:h.next]...)
+	}
+	records := append([]DispatchRecord(nil), h.records[h.next:]...)
+
+	return append(records, h.records[:h.next]...)
+}
+
+// DumpHistory writes the remembered dispatch records to w, one per line
+func (d *EventDispatcher) DumpHistory(w io.Writer) error {
+	for _, r := range d.History() {
+		_, err := fmt.Fprintf(w, "%s %s listeners=%d duration=%s errors=%d%s\n",
+			r.Time.Format(time.RFC3339Nano), r.Name, r.Listeners, r.Duration, len(r.Errors), formatErrors(r.Errors))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatErrors formats the errors of the dispatch record
+func formatErrors(errs []error) string {
+	var s string
+	for _, err := range errs {
+		s += fmt.Sprintf(" %q", err.Error())
+	}
+
+	return s
+}
+
+// enabled informs whether the dispatches should be recorded at all
+func (h *history) enabled() bool {
+	return len(h.records) != 0 || len(h.observers) != 0
+}
+
+// startRecord creates the in flight record of the dispatch of event e
+// under name n. Returns nil if neither history nor observers are enabled.
+func startRecord(d *EventDispatcher, n string, e Event) *DispatchRecord {
+	h := d.history
+	if h.enabled() == false {
+		return nil
+	}
+
+	r := &DispatchRecord{Name: n, Time: d.clock.Now(), e: e}
+	h.Lock()
+	defer h.Unlock()
+	h.inFlight = append(h.inFlight, r)
+
+	return r
+}
+
+// finishRecord stores the in flight record r in the ring buffer
+func finishRecord(d *EventDispatcher, r *DispatchRecord, listeners int) {
+	if r == nil {
+		return
+	}
+
+	h := d.history
+	h.Lock()
+	r.Listeners = listeners
+	r.Duration = d.clock.Now().Sub(r.Time)
+	r.Params = summarizeParams(d, r.e)
+	r.e = nil
+	for i, ir := range h.inFlight {
+		if ir == r {
+			h.inFlight = append(h.inFlight[:i:i], h.inFlight[i+1:]...)
+			break
+		}
+	}
+	if len(h.records) != 0 {
+		h.records[h.next] = *r
+		h.next = (h.next + 1) % len(h.records)
+		if h.next == 0 {
+			h.full = true
+		}
+	}
+	observers := h.observers
+	h.Unlock()
+
+	for _, o := range observers {
+		o(*r)
+	}
+}
+
+// recordError attaches the error reported for event e to its in flight
+// dispatch records
+func recordError(d *EventDispatcher, e Event, err error) {
+	h := d.history
+	if h.enabled() == false || reflect.TypeOf(e).Comparable() == false {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	for _, r := range h.inFlight {
+		if r.e == e {
+			r.Errors = append(r.Errors, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithHistory(2))
+	err := errors.New("failed")
+	d.On("event_2", func(e Event) {
+		d.ReportError(e, err)
+	})
+	d.On("event_2 event_3", func(e Event) {})
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent("event_2"))
+	d.Dispatch(NewParamsEvent("event_3"))
+
+	h := d.History()
+	assert.Equal(2, len(h), "Only the last dispatches should be remembered!")
+	assert.Equal("event_2", h[0].Name, "The records should be ordered from the oldest!")
+	assert.Equal(2, h[0].Listeners, "Invalid listeners count!")
+	assert.Equal([]error{err}, h[0].Errors, "The reported error should be recorded!")
+	assert.Equal("event_3", h[1].Name)
+	assert.Equal(0, len(h[1].Errors))
+
+	var b strings.Builder
+	assert.Nil(d.DumpHistory(&b))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(2, len(lines), "Each record should be dumped in its own line!")
+	assert.Contains(lines[0], "event_2 listeners=2")
+	assert.Contains(lines[0], `"failed"`)
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	d := NewDispatcher()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Nil(t, d.History(), "No history should be remembered by default!")
+}
+
+func TestDispatchObserver(t *testing.T) {
+	assert := assert.New(t)
+	var records []DispatchRecord
+	d := NewDispatcher(WithDispatchObserver(func(r DispatchRecord) {
+		records = append(records, r)
+	}))
+	d.On(TestEventName, func(e Event) {})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, len(records), "The observer should be notified about the dispatch!")
+	assert.Equal(TestEventName, records[0].Name)
+	assert.Equal(1, records[0].Listeners)
+	assert.Nil(d.History(), "The observer should not enable the history!")
+}
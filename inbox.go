@@ -0,0 +1,157 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNoEventID is reported when the event passed to the exactly-once
+// listener has no ID, so its processing cannot be tracked
+var ErrNoEventID = errors.New("eventdispatcher: event has no ID")
+
+// Inbox remembers the IDs of the events processed by the consumers, so the
+// listeners registered with OnExactlyOnce skip the events delivered again,
+// e.g. replayed from the history or redelivered by the bridge.
+//
+// The dispatcher delivers the events at most once by default, or at least
+// once with OnRetry and OnAck. Exactly-once processing combines the event
+// IDs assigned at the source (CausedBy, WithCorrelation or the bridge),
+// the dedupe cache of WithDeduplication dropping the recent duplicates
+// cheaply before they are dispatched, and the persistent inbox catching
+// the duplicates arriving later or after the restart. The event is marked
+// processed only after the listener succeeds, so a crash in between makes
+// the event processed again: listeners needing the strict guarantee must
+// mark it in the same transaction as their own changes.
+type Inbox interface {
+
+	// Processed informs whether the event with given id has been processed
+	// by the consumer
+	Processed(consumer string, id string) (bool, error)
+
+	// MarkProcessed remembers the event with given id has been processed
+	// by the consumer
+	MarkProcessed(consumer string, id string) error
+}
+
+// MemoryInbox is the Inbox keeping the processed IDs in memory, e.g. for
+// tests. It forgets them on restart.
+type MemoryInbox struct {
+	sync.RWMutex
+	processed map[string]map[string]bool
+}
+
+// Processed informs whether the event has been processed by the consumer
+func (in *MemoryInbox) Processed(consumer string, id string) (bool, error) {
+	in.RLock()
+	defer in.RUnlock()
+
+	return in.processed[consumer][id], nil
+}
+
+// MarkProcessed remembers the event has been processed by the consumer
+func (in *MemoryInbox) MarkProcessed(consumer string, id string) error {
+	in.Lock()
+	defer in.Unlock()
+
+	if in.processed[consumer] == nil {
+		in.processed[consumer] = make(map[string]bool)
+	}
+	in.processed[consumer][id] = true
+	return nil
+}
+
+// FileInbox is the Inbox keeping an empty marker file per processed event
+// in the directory of each consumer. The consumers and the event IDs are
+// base64url encoded in the file names.
+type FileInbox struct {
+	dir string
+}
+
+// Processed informs whether the marker file of the event exists
+func (in *FileInbox) Processed(consumer string, id string) (bool, error) {
+	_, err := os.Stat(in.path(consumer, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// MarkProcessed creates the marker file of the event
+func (in *FileInbox) MarkProcessed(consumer string, id string) error {
+	path := in.path(consumer, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, nil, 0o600)
+}
+
+// path returns the path of the marker file of the event processed by the
+// consumer
+func (in *FileInbox) path(consumer string, id string) string {
+	return filepath.Join(in.dir, fileName(consumer), fileName(id))
+}
+
+// fileName encodes s as the name of the file, which neither contains the
+// separators nor is a special name like "..", whatever s is. The prefix
+// keeps the empty s from naming the parent directory.
+func fileName(s string) string {
+	return "_" + base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// OnExactlyOnce registers the fallible listener for given event name
+// processing each event ID once per consumer, as remembered by the inbox.
+// The events without the ID are not processed and ErrNoEventID is
+// reported, the same as the errors of the listener and the inbox. Failed
+// events are not marked processed, so they are processed again when
+// redelivered. Deliveries to the listener are serialized.
+func (d *EventDispatcher) OnExactlyOnce(consumer string, n string, l FallibleListener, inbox Inbox) {
+	var mu sync.Mutex
+	d.On(n, func(e Event) {
+		ce, ok := e.(Correlated)
+		if ok == false || ce.ID() == "" {
+			d.ReportError(e, fmt.Errorf("%w: %s", ErrNoEventID, e.Name()))
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		processed, err := inbox.Processed(consumer, ce.ID())
+		if err != nil {
+			d.ReportError(e, err)
+			return
+		}
+		if processed {
+			return
+		}
+		if err := l(e); err != nil {
+			d.ReportError(e, err)
+			return
+		}
+		if err := inbox.MarkProcessed(consumer, ce.ID()); err != nil {
+			d.ReportError(e, err)
+		}
+	})
+}
+
+// NewMemoryInbox creates an empty in memory inbox
+func NewMemoryInbox() *MemoryInbox {
+	return &MemoryInbox{processed: make(map[string]map[string]bool)}
+}
+
+// NewFileInbox creates the inbox keeping the marker files in the directory
+// dir, creating it if needed
+func NewFileInbox(dir string) (*FileInbox, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileInbox{dir}, nil
+}
@@ -0,0 +1,75 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOnExactlyOnce(t *testing.T) {
+	fi, err := NewFileInbox(t.TempDir())
+	assert.Nil(t, err, "The file inbox should be created!")
+	for name, inbox := range map[string]Inbox{"memory": NewMemoryInbox(), "file": fi} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			d := NewDispatcher()
+			var got []string
+			fail := true
+			d.OnExactlyOnce("mailer", TestEventName, func(e Event) error {
+				if fail {
+					fail = false
+					return errors.New("failed")
+				}
+				got = append(got, e.(*ParamsEvent).ID())
+				return nil
+			}, inbox)
+			a, b := NewParamsEvent(TestEventName), NewParamsEvent(TestEventName)
+			a.SetCorrelation("a", "a", "")
+			b.SetCorrelation("b/1", "b/1", "")
+			for _, e := range []*ParamsEvent{a, a, a, b, b} {
+				d.Dispatch(e)
+			}
+			assert.Equal([]string{"a", "b/1"}, got, "Each event should be processed once, after the failed attempt!")
+			processed, _ := inbox.Processed("other", "a")
+			assert.False(processed, "The events should be tracked per consumer!")
+		})
+	}
+}
+
+func TestOnExactlyOnceWithoutID(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	var c int
+	d.OnExactlyOnce("mailer", TestEventName, func(e Event) error {
+		c++
+		return nil
+	}, NewMemoryInbox())
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(0, c, "The event without ID should not be processed!")
+	assert.Len(errs, 1, "The missing ID should be reported!")
+	assert.ErrorIs(errs[0], ErrNoEventID)
+}
+
+func TestFileInboxSpecialNames(t *testing.T) {
+	assert := assert.New(t)
+	in, err := NewFileInbox(t.TempDir())
+	assert.Nil(err)
+	assert.Nil(in.MarkProcessed("mailer", "a"))
+	for _, name := range []string{".", "..", "", "a/.."} {
+		processed, err := in.Processed("mailer", name)
+		assert.Nil(err)
+		assert.False(processed, "The id %q should not name an existing file!", name)
+		processed, err = in.Processed(name, "a")
+		assert.Nil(err)
+		assert.False(processed, "The consumer %q should not name an existing directory!", name)
+	}
+	assert.Nil(in.MarkProcessed("..", ".."))
+	processed, _ := in.Processed("..", "..")
+	assert.True(processed, "The special names should be tracked too!")
+}
@@ -0,0 +1,136 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// Metadata is the metadata the event re-dispatched by a bridge or a
+// derivation inherits from its source event
+type Metadata struct {
+
+	// Priority is the priority of the re-dispatched event
+	Priority Priority
+
+	// CorrelationID is the identifier shared by all events of the cascade
+	CorrelationID string
+
+	// CausationID is the identifier of the source event
+	CausationID string
+
+	// Params are the params copied from the source event
+	Params map[string]interface{}
+}
+
+// MetadataMapper maps the metadata inherited from the source event before
+// it is applied, e.g. lowering the priority of the replicated events
+type MetadataMapper func(md *Metadata, source Event)
+
+// Inheritance defines how the events re-dispatched by the bridges and the
+// derivations inherit from their source events. The zero value carries
+// over the priority and the correlation.
+type Inheritance struct {
+
+	// Params lists the params copied from the source event unless the
+	// re-dispatched event sets them, e.g. the tenant or the trace id
+	Params []string
+
+	// Map maps the inherited metadata, unless nil
+	Map MetadataMapper
+}
+
+// WithInheritance makes the dispatcher apply the inheritance in to the
+// events derived with Derive
+func WithInheritance(in Inheritance) Option {
+	return func(d *EventDispatcher) {
+		d.inheritance = in
+	}
+}
+
+// Apply makes the event e inherit the metadata of the source event, like
+// CausedBy does for the correlation. The priority is inherited unless e has
+// its own, other than PriorityNormal. The metadata e can not hold, e.g. the
+// priority of the event not being a ParamsEvent, is skipped. Returns e.
+func (in Inheritance) Apply(e Event, source Event) Event {
+	md := Metadata{Priority: priorityOf(e)}
+	if md.Priority == PriorityNormal {
+		md.Priority = priorityOf(source)
+	}
+	if cs, ok := source.(Correlated); ok {
+		assignID(cs)
+		md.CorrelationID, md.CausationID = cs.CorrelationID(), cs.ID()
+	}
+	if len(in.Params) != 0 {
+		md.Params = inheritedParams(e, source, in.Params)
+	}
+	if in.Map != nil {
+		in.Map(&md, source)
+	}
+
+	applyMetadata(e, md)
+
+	return e
+}
+
+// inheritedParams returns the params of the source event with given names
+// the event e does not set
+func inheritedParams(e Event, source Event, names []string) map[string]interface{} {
+	ps, ok := source.(interface {
+		GetParam(k string) (interface{}, bool)
+	})
+	if ok == false {
+		return nil
+	}
+	pe, _ := e.(interface {
+		HasParam(k string) bool
+	})
+
+	params := make(map[string]interface{})
+	for _, k := range names {
+		if pe != nil && pe.HasParam(k) {
+			continue
+		}
+		if v, ok := ps.GetParam(k); ok {
+			params[k] = v
+		}
+	}
+
+	return params
+}
+
+// applyMetadata sets the metadata md on the event e
+func applyMetadata(e Event, md Metadata) {
+	switch ev := e.(type) {
+	case *ParamsEvent:
+		ev.SetPriority(md.Priority)
+		for k, v := range md.Params {
+			ev.SetParam(k, v)
+		}
+	case *ConcurrentParamsEvent:
+		ev.SetPriority(md.Priority)
+		for k, v := range md.Params {
+			ev.SetParam(k, v)
+		}
+	}
+
+	ce, ok := e.(Correlated)
+	if ok == false || (md.CorrelationID == "" && md.CausationID == "") {
+		return
+	}
+	id := ce.ID()
+	if id == "" {
+		id = NewEventID()
+	}
+	ce.SetCorrelation(id, md.CorrelationID, md.CausationID)
+}
+
+// MapMetadata maps the metadata of the event e in place with the mapper m,
+// e.g. so the bridges may override the priority of the relayed events. The
+// params set by the mapper are set on e. Returns e.
+func MapMetadata(e Event, m MetadataMapper) Event {
+	md := Metadata{Priority: priorityOf(e)}
+	if ce, ok := e.(Correlated); ok {
+		md.CorrelationID, md.CausationID = ce.CorrelationID(), ce.CausationID()
+	}
+	m(&md, e)
+	applyMetadata(e, md)
+
+	return e
+}
@@ -0,0 +1,84 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInheritanceApply(t *testing.T) {
+	assert := assert.New(t)
+	source := NewParamsEvent("order.paid").
+		SetParam("tenant", "acme").
+		SetParam("trace", "t1").
+		SetPriority(PriorityHigh)
+
+	in := Inheritance{Params: []string{"tenant", "trace", "missing"}}
+	e := in.Apply(NewParamsEvent("email.send").SetParam("trace", "t2"), source).(*ParamsEvent)
+	assert.Equal(PriorityHigh, e.Priority(), "The priority should be inherited!")
+	assert.NotEqual("", source.ID(), "The source should be assigned the id!")
+	assert.Equal(source.ID(), e.CausationID(), "The event should be caused by the source!")
+	assert.Equal(source.CorrelationID(), e.CorrelationID(), "The correlation should be inherited!")
+	tenant, _ := e.GetParam("tenant")
+	trace, _ := e.GetParam("trace")
+	assert.Equal("acme", tenant, "The listed params should be inherited!")
+	assert.Equal("t2", trace, "The params set on the event should be kept!")
+	assert.False(e.HasParam("missing"), "The params missing on the source should be skipped!")
+
+	e = in.Apply(NewParamsEvent("email.send").SetPriority(PriorityLow), source).(*ParamsEvent)
+	assert.Equal(PriorityLow, e.Priority(), "The own priority should be kept!")
+
+	in.Map = func(md *Metadata, source Event) {
+		md.Priority = PriorityLow
+		delete(md.Params, "tenant")
+	}
+	e = in.Apply(NewParamsEvent("email.send"), source).(*ParamsEvent)
+	assert.Equal(PriorityLow, e.Priority(), "The mapper should override the priority!")
+	assert.False(e.HasParam("tenant"), "The mapper should override the params!")
+}
+
+func TestDeriveInheritance(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithInheritance(Inheritance{Params: []string{"tenant"}}))
+	var derived *ParamsEvent
+	d.On("email.send", func(e Event) {
+		derived = e.(*ParamsEvent)
+	})
+	_, err := d.Derive("order.paid", "email.send", func(e Event) Event {
+		return NewParamsEvent("email.send")
+	})
+	assert.Nil(err)
+
+	source := NewParamsEvent("order.paid").SetParam("tenant", "acme").SetPriority(PriorityHigh)
+	d.Dispatch(source)
+	assert.Equal(PriorityHigh, derived.Priority(), "The derived event should inherit the priority!")
+	assert.Equal(source.ID(), derived.CausationID(), "The derived event should be caused by the source!")
+	tenant, _ := derived.GetParam("tenant")
+	assert.Equal("acme", tenant, "The derived event should inherit the params!")
+}
+
+func TestPriorityJSON(t *testing.T) {
+	assert := assert.New(t)
+	data, err := json.Marshal(NewParamsEvent(TestEventName).SetPriority(PriorityHigh))
+	assert.Nil(err)
+	e := &ParamsEvent{}
+	assert.Nil(json.Unmarshal(data, e))
+	assert.Equal(PriorityHigh, e.Priority(), "The priority should survive the encoding!")
+}
+
+func TestMapMetadata(t *testing.T) {
+	assert := assert.New(t)
+	e := NewParamsEvent(TestEventName)
+	e.SetCorrelation("id", "correlation", "cause")
+	MapMetadata(e, func(md *Metadata, source Event) {
+		assert.Equal("correlation", md.CorrelationID, "The metadata of the event should be mapped!")
+		md.Priority = PriorityHigh
+		md.Params = map[string]interface{}{"origin": "bridge"}
+	})
+	origin, _ := e.GetParam("origin")
+	assert.Equal(PriorityHigh, e.Priority(), "The mapped priority should be set!")
+	assert.Equal("bridge", origin, "The mapped params should be set!")
+	assert.Equal("id", e.ID(), "The id should be kept!")
+}
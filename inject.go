@@ -0,0 +1,80 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"reflect"
+)
+
+// DispatcherSetter is implemented by the types accepting the dispatcher
+// injected with InjectDispatcher
+type DispatcherSetter interface {
+
+	// SetDispatcher sets the event dispatcher instance
+	SetDispatcher(Dispatcher)
+}
+
+// InjectDispatcher sets the dispatcher d on the target if it implements
+// DispatcherSetter and walks through the exported fields of the target,
+// also nested in structs, pointers, interfaces, slices, arrays and maps,
+// injecting the dispatcher into each of them implementing the interface.
+// Each instance gets the dispatcher once even if referenced many times.
+// Returns the number of injected instances.
+func InjectDispatcher(target interface{}, d Dispatcher) int {
+	visited := make(map[visitedValue]bool)
+	return inject(reflect.ValueOf(target), d, visited)
+}
+
+// visitedValue identifies the value already walked through. The type is
+// needed as a struct shares its address with its first field.
+type visitedValue struct {
+	p uintptr
+	t reflect.Type
+}
+
+// inject injects the dispatcher into the value v and its nested values
+func inject(v reflect.Value, d Dispatcher, visited map[visitedValue]bool) int {
+	if v.IsValid() == false {
+		return 0
+	}
+
+	var c int
+	switch v.Kind() {
+	case reflect.Ptr:
+		vv := visitedValue{v.Pointer(), v.Type()}
+		if v.IsNil() || visited[vv] {
+			return 0
+		}
+		visited[vv] = true
+		if s, ok := v.Interface().(DispatcherSetter); ok {
+			s.SetDispatcher(d)
+			c++
+		}
+		return c + inject(v.Elem(), d, visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return inject(v.Elem(), d, visited)
+	case reflect.Struct:
+		if v.CanAddr() && visited[visitedValue{v.Addr().Pointer(), v.Addr().Type()}] == false {
+			return inject(v.Addr(), d, visited)
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				c += inject(v.Field(i), d, visited)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c += inject(v.Index(i), d, visited)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			c += inject(iter.Value(), d, visited)
+		}
+	}
+
+	return c
+}
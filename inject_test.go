@@ -0,0 +1,56 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testService struct {
+	d Dispatcher
+}
+
+func (s *testService) SetDispatcher(d Dispatcher) {
+	s.d = d
+}
+
+func (s *testService) Dispatcher() Dispatcher {
+	return s.d
+}
+
+type testApp struct {
+	testService
+	Users    *testService
+	Mailer   DispatcherAware
+	Plugins  []*testService
+	Modules  map[string]*testService
+	Embedded struct {
+		Billing testService
+	}
+	private *testService
+}
+
+func TestInjectDispatcher(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	shared := &testService{}
+	app := &testApp{
+		Users:   shared,
+		Mailer:  &testService{},
+		Plugins: []*testService{{}, shared},
+		Modules: map[string]*testService{"audit": {}},
+		private: &testService{},
+	}
+	app.Embedded.Billing = testService{}
+
+	c := InjectDispatcher(app, d)
+	assert.Equal(6, c, "Invalid number of injected instances!")
+	assert.Equal(Dispatcher(d), app.Dispatcher(), "The dispatcher should be injected into the target!")
+	assert.Equal(Dispatcher(d), app.Users.d, "The dispatcher should be injected into pointer fields!")
+	assert.Equal(Dispatcher(d), app.Mailer.Dispatcher(), "The dispatcher should be injected into interface fields!")
+	assert.Equal(Dispatcher(d), app.Plugins[0].d, "The dispatcher should be injected into slice items!")
+	assert.Equal(Dispatcher(d), app.Modules["audit"].d, "The dispatcher should be injected into map values!")
+	assert.Equal(Dispatcher(d), app.Embedded.Billing.d, "The dispatcher should be injected into nested structs!")
+	assert.Nil(app.private.d, "Unexported fields should be skipped!")
+}
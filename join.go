@@ -0,0 +1,152 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// JoinTimeoutEventName is the name of the event dispatched when the
+	// join times out before all its events arrive
+	JoinTimeoutEventName = "dispatcher.join.timeout"
+
+	// ParamJoinKey is the param of the join timeout event holding the value
+	// of the param the events are joined by
+	ParamJoinKey = "join_key"
+
+	// ParamJoinMissing is the param of the join timeout event holding the
+	// names of the events that have not arrived
+	ParamJoinMissing = "missing"
+)
+
+// JoinHandler handles the events joined by the key, in the order of their
+// names given to Join
+type JoinHandler func(key string, events []Event)
+
+// join is the state of the events joined by the key, waiting for the rest
+type join struct {
+	events []Event
+	timer  Timer
+}
+
+// joiner collects the events joined by the param value
+type joiner struct {
+	sync.Mutex
+	d       *EventDispatcher
+	names   []string
+	param   string
+	timeout time.Duration
+	h       JoinHandler
+	joins   map[string]*join
+}
+
+// Join invokes the handler h once all events with given names carrying the
+// same value of the param arrive, e.g. "email.verified" and
+// "profile.filled" of the same "user_id". The latest event of each name is
+// passed to the handler. Unless the timeout is zero, the join not complete
+// within the timeout from its first event is discarded and the
+// JoinTimeoutEventName event is dispatched. The events without the param
+// are ignored. The returned subscription removes the join along with the
+// pending events.
+func (d *EventDispatcher) Join(names []string, param string, timeout time.Duration, h JoinHandler) *Subscription {
+	j := &joiner{d: d, names: names, param: param, timeout: timeout, h: h, joins: make(map[string]*join)}
+	s := &Subscription{d: d, stop: j.stop}
+	for i, name := range names {
+		i := i
+		s.registrations = append(s.registrations, registration{name, on(d, name, func(e Event) {
+			j.add(i, e)
+		})})
+	}
+
+	return s
+}
+
+// add adds the i-th event of the join, invoking the handler if it has
+// been the last one missing
+func (j *joiner) add(i int, e Event) {
+	key, ok := joinKey(e, j.param)
+	if ok == false {
+		return
+	}
+
+	j.Lock()
+	jn, ok := j.joins[key]
+	if ok == false {
+		jn = &join{events: make([]Event, len(j.names))}
+		j.joins[key] = jn
+		if j.timeout > 0 {
+			jn.timer = j.d.clock.AfterFunc(j.timeout, func() {
+				j.expire(key, jn)
+			})
+		}
+	}
+	jn.events[i] = e
+	for _, je := range jn.events {
+		if je == nil {
+			j.Unlock()
+			return
+		}
+	}
+	delete(j.joins, key)
+	j.Unlock()
+
+	if jn.timer != nil {
+		jn.timer.Stop()
+	}
+	j.h(key, jn.events)
+}
+
+// expire discards the join timed out and dispatches the timeout event
+func (j *joiner) expire(key string, jn *join) {
+	j.Lock()
+	if j.joins[key] != jn {
+		j.Unlock()
+		return
+	}
+	delete(j.joins, key)
+	var missing []string
+	for i, e := range jn.events {
+		if e == nil {
+			missing = append(missing, j.names[i])
+		}
+	}
+	j.Unlock()
+
+	e := NewParamsEvent(JoinTimeoutEventName).SetParam(ParamJoinKey, key).SetParam(ParamJoinMissing, missing)
+	j.d.Dispatch(e)
+}
+
+// stop discards all pending joins
+func (j *joiner) stop() bool {
+	j.Lock()
+	defer j.Unlock()
+
+	for key, jn := range j.joins {
+		if jn.timer != nil {
+			jn.timer.Stop()
+		}
+		delete(j.joins, key)
+	}
+
+	return true
+}
+
+// joinKey returns the value of the param of the event formatted as the
+// join key and false if the event has no such param
+func joinKey(e Event, param string) (string, bool) {
+	pe, ok := e.(interface {
+		GetParam(k string) (interface{}, bool)
+	})
+	if ok == false {
+		return "", false
+	}
+	v, ok := pe.GetParam(param)
+	if ok == false {
+		return "", false
+	}
+
+	return fmt.Sprint(v), true
+}
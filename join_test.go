@@ -0,0 +1,59 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestJoin(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var keys []string
+	var joined []Event
+	d.Join([]string{"email.verified", "profile.filled"}, "user_id", 0, func(key string, events []Event) {
+		keys = append(keys, key)
+		joined = events
+	})
+	filled := NewParamsEvent("profile.filled").SetParam("user_id", 1)
+	d.Dispatch(filled)
+	d.Dispatch(NewParamsEvent("email.verified").SetParam("user_id", 2))
+	d.Dispatch(NewParamsEvent("email.verified"))
+	assert.Empty(keys, "The handler should wait for all events!")
+	verified := NewParamsEvent("email.verified").SetParam("user_id", 1)
+	d.Dispatch(verified)
+	assert.Equal([]string{"1"}, keys, "The handler should be invoked once all events of the key arrive!")
+	assert.Equal([]Event{verified, filled}, joined, "The events should be passed in the order of names!")
+	d.Dispatch(NewParamsEvent("profile.filled").SetParam("user_id", 1))
+	assert.Len(keys, 1, "The join should start over after completing!")
+}
+
+func TestJoinTimeout(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	timeouts := make(chan *ParamsEvent, 1)
+	d.On(JoinTimeoutEventName, func(e Event) {
+		timeouts <- e.(*ParamsEvent)
+	})
+	var c int
+	s := d.Join([]string{"a", "b"}, "id", 10*time.Millisecond, func(key string, events []Event) {
+		c++
+	})
+	d.Dispatch(NewParamsEvent("a").SetParam("id", "x"))
+	select {
+	case e := <-timeouts:
+		key, _ := e.GetParam(ParamJoinKey)
+		missing, _ := e.GetParam(ParamJoinMissing)
+		assert.Equal("x", key, "Invalid key of the timed out join!")
+		assert.Equal([]string{"b"}, missing, "Invalid missing events of the timed out join!")
+	case <-time.After(time.Second):
+		assert.Fail("The join should time out!")
+	}
+	d.Dispatch(NewParamsEvent("b").SetParam("id", "x"))
+	assert.Equal(0, c, "The timed out join should be discarded!")
+
+	s.Unsubscribe()
+	assert.False(d.HasListeners("a"), "The join should be removed!")
+}
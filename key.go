@@ -0,0 +1,73 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+)
+
+// EventKey is the event name bound to the type T of the event payload, so
+// the payload type of the listeners and dispatched events is checked at
+// compile time
+type EventKey[T any] struct {
+	name string
+}
+
+// Name returns the event name
+func (k EventKey[T]) Name() string {
+	return k.name
+}
+
+// KeyEvent is the event dispatched with DispatchKey carrying the payload of
+// type T
+type KeyEvent[T any] struct {
+	*ParamsEvent
+
+	// Payload is the payload of the event
+	Payload T
+}
+
+// OnKey registers the listener l receiving the payloads of the events with
+// given key. Events dispatched under the key name without the payload of
+// type T, e.g. with the string API, are reported with ReportError.
+func OnKey[T any](d *EventDispatcher, k EventKey[T], l func(T)) {
+	d.On(k.name, keyListener(d, l))
+}
+
+// OnceKey registers the listener l like OnKey, executed only once
+func OnceKey[T any](d *EventDispatcher, k EventKey[T], l func(T)) {
+	d.Once(k.name, keyListener(d, l))
+}
+
+// DispatchKey dispatches the event with given key and payload p. Returns
+// the dispatched event.
+func DispatchKey[T any](d *EventDispatcher, k EventKey[T], p T) *KeyEvent[T] {
+	e := NewKeyEvent(k, p)
+	d.Dispatch(e)
+
+	return e
+}
+
+// keyListener adapts the payload listener l to the Listener
+func keyListener[T any](d *EventDispatcher, l func(T)) Listener {
+	return func(e Event) {
+		ke, ok := e.(*KeyEvent[T])
+		if ok == false {
+			var p T
+			d.ReportError(e, fmt.Errorf("eventdispatcher: event %s does not carry the %T payload", e.Name(), p))
+			return
+		}
+		l(ke.Payload)
+	}
+}
+
+// NewEventKey creates the key of the events with name n and the payload of
+// type T
+func NewEventKey[T any](n string) EventKey[T] {
+	return EventKey[T]{n}
+}
+
+// NewKeyEvent creates the event with given key and payload p
+func NewKeyEvent[T any](k EventKey[T], p T) *KeyEvent[T] {
+	return &KeyEvent[T]{NewParamsEvent(k.name), p}
+}
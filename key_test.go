@@ -0,0 +1,72 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type userCreated struct {
+	ID   int
+	Name string
+}
+
+var userCreatedKey = NewEventKey[userCreated]("user.created")
+
+func TestEventKey(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []userCreated
+	OnKey(d, userCreatedKey, func(p userCreated) {
+		got = append(got, p)
+	})
+	var names []string
+	d.On(userCreatedKey.Name(), func(e Event) {
+		names = append(names, e.Name())
+	})
+	e := DispatchKey(d, userCreatedKey, userCreated{1, "john"})
+	assert.Equal([]userCreated{{1, "john"}}, got, "The payload should be passed to the listener!")
+	assert.Equal([]string{"user.created"}, names, "The string listeners should receive the event too!")
+	assert.Equal(userCreated{1, "john"}, e.Payload)
+}
+
+func TestEventKeyPayloadMismatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	OnKey(d, userCreatedKey, func(p userCreated) {
+		c++
+	})
+	var ee *ErrorEvent
+	d.On(ErrorEventName, func(e Event) {
+		ee = e.(*ErrorEvent)
+	})
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal(0, c, "The listener should not be called without the payload!")
+	assert.NotNil(ee, "The payload mismatch should be reported!")
+}
+
+func TestOnceKey(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	OnceKey(d, userCreatedKey, func(p userCreated) {
+		c++
+	})
+	DispatchKey(d, userCreatedKey, userCreated{})
+	DispatchKey(d, userCreatedKey, userCreated{})
+	assert.Equal(1, c, "The listener should be called once!")
+}
+
+func TestOnKeyInvalidName(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	OnKey(d, NewEventKey[userCreated](""), func(p userCreated) {})
+	assert.Len(errs, 1, "The invalid registration should be reported!")
+	assert.ErrorIs(errs[0], ErrEmptyName)
+}
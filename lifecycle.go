@@ -0,0 +1,87 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+const (
+	// ListenerAddedEventName is the name of the lifecycle event dispatched
+	// when a listener is registered
+	ListenerAddedEventName = "dispatcher.listener.added"
+
+	// ListenerRemovedEventName is the name of the lifecycle event dispatched
+	// when a listener is removed
+	ListenerRemovedEventName = "dispatcher.listener.removed"
+
+	// DispatchErrorEventName is the name of the lifecycle ErrorEvent
+	// dispatched when a listener reports an error
+	DispatchErrorEventName = "dispatcher.dispatch.error"
+
+	// ParamListenerEvent is the param of the listener lifecycle events
+	// holding the name of the event the listener is registered for
+	ParamListenerEvent = "event"
+
+	// ParamListenerID is the param of the listener lifecycle events holding
+	// the identifier of the registration
+	ParamListenerID = "listener_id"
+)
+
+// WithLifecycle makes the dispatcher dispatch the lifecycle events to the
+// dispatcher meta whenever a listener is added or removed and whenever a
+// listener reports an error, so the tooling may react to the changes of
+// the topology. The meta dispatcher must not be the configured one, its
+// listeners would be notified about their own changes.
+func WithLifecycle(meta Dispatcher) Option {
+	return func(d *EventDispatcher) {
+		d.lifecycle = meta
+	}
+}
+
+// listenerAdded dispatches the lifecycle event of the listener registered
+// under given identifier for event name n
+func listenerAdded(d *EventDispatcher, n string, id uint64) {
+	if d.lifecycle == nil {
+		return
+	}
+	d.lifecycle.Dispatch(newListenerEvent(ListenerAddedEventName, n, id))
+}
+
+// listenersRemoved calls the unsubscribe hooks and dispatches the lifecycle
+// events of the listeners removed from event name n
+func listenersRemoved(d *EventDispatcher, n string, ids []uint64) {
+	unsubscribed(d, ids)
+	if d.lifecycle == nil {
+		return
+	}
+	for _, id := range ids {
+		d.lifecycle.Dispatch(newListenerEvent(ListenerRemovedEventName, n, id))
+	}
+}
+
+// dispatchFailed dispatches the lifecycle event of the error err reported
+// by the listener handling the event e
+func dispatchFailed(d *EventDispatcher, e Event, err error) {
+	if d.lifecycle == nil {
+		return
+	}
+	d.lifecycle.Dispatch(&ErrorEvent{NewParamsEvent(DispatchErrorEventName), err, e})
+}
+
+// newListenerEvent creates the listener lifecycle event with given name
+func newListenerEvent(name string, n string, id uint64) *ParamsEvent {
+	return NewParamsEvent(name).
+		SetParam(ParamListenerEvent, n).
+		SetParam(ParamListenerID, id)
+}
+
+// modulesChanged dispatches the lifecycle events of the module listeners
+// removed and added
+func modulesChanged(d *EventDispatcher, removed []registration, added []registration) {
+	if d.lifecycle == nil {
+		return
+	}
+	for _, r := range removed {
+		d.lifecycle.Dispatch(newListenerEvent(ListenerRemovedEventName, r.n, r.id))
+	}
+	for _, r := range added {
+		listenerAdded(d, r.n, r.id)
+	}
+}
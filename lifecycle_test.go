@@ -0,0 +1,62 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	meta := NewDispatcher()
+	var got []string
+	record := func(e Event) {
+		pe := e.(*ParamsEvent)
+		n, _ := pe.GetParam(ParamListenerEvent)
+		id, _ := pe.GetParam(ParamListenerID)
+		got = append(got, fmt.Sprintf("%s %v %v", e.Name(), n, id))
+	}
+	meta.On(ListenerAddedEventName, record)
+	meta.On(ListenerRemovedEventName, record)
+
+	d := NewDispatcher(WithLifecycle(meta))
+	l := func(e Event) {}
+	d.On(TestEventName, l)
+	d.Once(TestEventName, l)
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Off(TestEventName, l)
+	assert.Equal([]string{
+		"dispatcher.listener.added test_event 1",
+		"dispatcher.listener.added test_event 2",
+		"dispatcher.listener.removed test_event 2",
+		"dispatcher.listener.removed test_event 1",
+	}, got, "The listener changes should be dispatched to the meta dispatcher!")
+
+	got = nil
+	d.ReplaceModule("m", Module{"a": {l}})
+	d.RemoveModule("m")
+	assert.Equal([]string{
+		"dispatcher.listener.added a 3",
+		"dispatcher.listener.removed a 3",
+	}, got, "The module changes should be dispatched to the meta dispatcher!")
+}
+
+func TestWithLifecycleError(t *testing.T) {
+	assert := assert.New(t)
+	meta := NewDispatcher()
+	var got *ErrorEvent
+	meta.On(DispatchErrorEventName, func(e Event) {
+		got = e.(*ErrorEvent)
+	})
+
+	d := NewDispatcher(WithLifecycle(meta))
+	err := errors.New("failed")
+	e := NewParamsEvent(TestEventName)
+	d.ReportError(e, err)
+	assert.NotNil(got, "The error should be dispatched to the meta dispatcher!")
+	assert.Equal(err, got.Err, "The error event should hold the error!")
+	assert.Equal(e, got.Source, "The error event should hold the source event!")
+}
@@ -2,5 +2,26 @@
 // reliable event dispatcher
 package eventdispatcher
 
+import (
+	"reflect"
+	"runtime"
+)
+
 // Listener type for defining functions as listeners
 type Listener func(Event)
+
+// ListenerName returns the name of the listener function, e.g.
+// "main.sendReceipt" or "main.(*Mailer).Send-fm" for the method value.
+// The listeners wrapped by the dispatcher, e.g. with Once, are named after
+// the wrapping closure.
+func ListenerName(l Listener) string {
+	if l == nil {
+		return ""
+	}
+	f := runtime.FuncForPC(reflect.ValueOf(l).Pointer())
+	if f == nil {
+		return ""
+	}
+
+	return f.Name()
+}
@@ -0,0 +1,136 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// MaxLoggedParamLength is the max length of the param value in the params
+// summary logged by the dispatcher created WithLogger
+const MaxLoggedParamLength = 64
+
+// Logger is the adapter interface of the logging libraries used by the
+// dispatcher created WithLogger. Use SlogLogger for log/slog, other
+// libraries (zap, logrus) need a tiny adapter mapping the levels and attrs.
+type Logger interface {
+
+	// Log logs the message with given level and attributes
+	Log(level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// LogLevels defines the levels of the messages logged by the dispatcher
+type LogLevels struct {
+
+	// Dispatch is the level of the message logged after each dispatch
+	Dispatch slog.Level
+
+	// Listener is the level of the message logged after each listener call
+	Listener slog.Level
+
+	// Error is the level of the dispatch message if any listener reported
+	// an error
+	Error slog.Level
+}
+
+// DefaultLogLevels logs dispatches and listener calls at debug level and
+// failed dispatches at error level
+var DefaultLogLevels = LogLevels{
+	Dispatch: slog.LevelDebug,
+	Listener: slog.LevelDebug,
+	Error:    slog.LevelError,
+}
+
+// slogLogger is the Logger using log/slog
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// Log logs the message with given level and attributes
+func (sl slogLogger) Log(level slog.Level, msg string, attrs ...slog.Attr) {
+	sl.l.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// SlogLogger adapts the slog logger l to the Logger interface
+func SlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+// WithLogger makes the dispatcher log every dispatch and listener call
+// with the logger l at given levels
+func WithLogger(l Logger, levels LogLevels) Option {
+	return func(d *EventDispatcher) {
+		WithMiddleware(logListener(d, l, levels.Listener))(d)
+		WithDispatchObserver(logDispatch(l, levels))(d)
+	}
+}
+
+// logListener returns the middleware logging the listener calls
+func logListener(d *EventDispatcher, l Logger, level slog.Level) Middleware {
+	return func(c ListenerCall, e Event, next Listener) {
+		start := d.clock.Now()
+		next(e)
+		l.Log(level, "event listener called",
+			slog.String("event", c.Name),
+			slog.Uint64("listener_id", c.ListenerID),
+			slog.Duration("duration", d.clock.Now().Sub(start)))
+	}
+}
+
+// logDispatch returns the observer logging the dispatches
+func logDispatch(l Logger, levels LogLevels) DispatchObserver {
+	return func(r DispatchRecord) {
+		attrs := []slog.Attr{
+			slog.String("event", r.Name),
+			slog.Int("listeners", r.Listeners),
+			slog.Duration("duration", r.Duration),
+			slog.Time("time", r.Time),
+		}
+		if r.Params != "" {
+			attrs = append(attrs, slog.String("params", r.Params))
+		}
+		level := levels.Dispatch
+		if len(r.Errors) != 0 {
+			level = levels.Error
+			errs := make([]string, len(r.Errors))
+			for i, err := range r.Errors {
+				errs[i] = err.Error()
+			}
+			attrs = append(attrs, slog.Any("errors", errs))
+		}
+		l.Log(level, "event dispatched", attrs...)
+	}
+}
+
+// summarizeParams formats the params of the event as a short string, e.g.
+// "id=12 user=john", masking the ones sensitive for the dispatcher d.
+// Returns an empty string if the event has no params.
+func summarizeParams(d *EventDispatcher, e Event) string {
+	pe, ok := e.(interface {
+		Params() map[string]interface{}
+	})
+	if ok == false {
+		return ""
+	}
+
+	params := d.RedactParams(pe.Params())
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		v := fmt.Sprintf("%v", params[k])
+		if len(v) > MaxLoggedParamLength {
+			v = v[:MaxLoggedParamLength] + "..."
+		}
+		parts[i] = k + "=" + v
+	}
+
+	return strings.Join(parts, " ")
+}
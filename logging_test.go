@@ -0,0 +1,42 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	assert := assert.New(t)
+	var b bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	d := NewDispatcher(WithLogger(SlogLogger(l), DefaultLogLevels))
+	d.On(TestEventName, func(e Event) {
+		d.ReportError(e, errors.New("failed"))
+	})
+	e := NewParamsEvent(TestEventName)
+	e.SetParam("user", "john").SetParam("id", 12)
+	d.Dispatch(e)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(3, len(lines), "The error event dispatch, the listener call and the dispatch should be logged!")
+	assert.Contains(lines[0], "level=DEBUG msg=\"event dispatched\" event=dispatcher.error listeners=0")
+	assert.Contains(lines[1], "level=DEBUG msg=\"event listener called\" event=test_event listener_id=1")
+	assert.Contains(lines[2], "level=ERROR msg=\"event dispatched\" event=test_event listeners=1")
+	assert.Contains(lines[2], "params=\"id=12 user=john\"")
+	assert.Contains(lines[2], "errors=[failed]")
+}
+
+func TestSummarizeParams(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	e := NewParamsEvent(TestEventName)
+	assert.Equal("", summarizeParams(d, e))
+	e.SetParam("long", strings.Repeat("x", MaxLoggedParamLength+1))
+	assert.Equal("long="+strings.Repeat("x", MaxLoggedParamLength)+"...", summarizeParams(d, e), "Long values should be truncated!")
+}
@@ -0,0 +1,25 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"time"
+)
+
+// Metrics receives the measurements of the dispatcher, e.g. to export them
+// to the monitoring system
+type Metrics interface {
+
+	// ObserveDispatch is called after each dispatch with the event name,
+	// the number of listeners called, the time they took and the number of
+	// errors they reported
+	ObserveDispatch(n string, listeners int, duration time.Duration, errors int)
+}
+
+// WithMetrics makes the dispatcher report the measurements of each
+// dispatch to m
+func WithMetrics(m Metrics) Option {
+	return WithDispatchObserver(func(r DispatchRecord) {
+		m.ObserveDispatch(r.Name, r.Listeners, r.Duration, len(r.Errors))
+	})
+}
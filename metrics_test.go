@@ -0,0 +1,35 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	names     []string
+	listeners int
+	errors    int
+}
+
+func (m *testMetrics) ObserveDispatch(n string, listeners int, duration time.Duration, errors int) {
+	m.names = append(m.names, n)
+	m.listeners += listeners
+	m.errors += errors
+}
+
+func TestWithMetrics(t *testing.T) {
+	assert := assert.New(t)
+	m := &testMetrics{}
+	d := NewDispatcher(WithMetrics(m))
+	d.On(TestEventName, func(e Event) {
+		d.ReportError(e, errors.New("failed"))
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{ErrorEventName, TestEventName}, m.names, "Every dispatch should be observed!")
+	assert.Equal(1, m.listeners, "The called listeners should be counted!")
+	assert.Equal(1, m.errors, "The reported errors should be counted!")
+}
@@ -0,0 +1,52 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// ListenerCall describes the listener invocation passed through the
+// middlewares
+type ListenerCall struct {
+
+	// Name is the name the event has been dispatched under
+	Name string
+
+	// ListenerID identifies the registration of the called listener
+	ListenerID uint64
+}
+
+// Middleware wraps each listener invocation. It must call next with the
+// event to proceed with the invocation, or skip it to prevent the listener
+// from being called.
+type Middleware func(c ListenerCall, e Event, next Listener)
+
+// WithMiddleware registers the middlewares wrapping the listener calls, in
+// the given order, the first one being the outermost
+func WithMiddleware(m ...Middleware) Option {
+	return func(d *EventDispatcher) {
+		d.middlewares = append(d.middlewares, m...)
+	}
+}
+
+// Use registers the middleware wrapping the listener calls, inside the
+// already registered ones
+func (d *EventDispatcher) Use(m Middleware) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	d.middlewares = append(d.middlewares[:len(d.middlewares):len(d.middlewares)], m)
+}
+
+// invoke passes the listener call through the middlewares mws and finally
+// calls the listener l
+func invoke(d *EventDispatcher, mws []Middleware, c ListenerCall, l Listener, e Event) {
+	if len(mws) == 0 && d.labels {
+		callLabeled(d, c, l, e)
+		return
+	}
+	if len(mws) == 0 {
+		call(d, l, e)
+		return
+	}
+	mws[0](c, e, func(e Event) {
+		invoke(d, mws[1:], c, l, e)
+	})
+}
@@ -0,0 +1,35 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	var calls []string
+	outer := func(c ListenerCall, e Event, next Listener) {
+		calls = append(calls, "outer:"+c.Name)
+		next(e)
+	}
+	d := NewDispatcher(WithMiddleware(outer))
+	d.Use(func(c ListenerCall, e Event, next Listener) {
+		calls = append(calls, "inner")
+		if e.(*ParamsEvent).HasParam("skip") == false {
+			next(e)
+		}
+	})
+	d.On(TestEventName, func(e Event) {
+		calls = append(calls, "listener")
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"outer:" + TestEventName, "inner", "listener"}, calls, "Invalid middlewares order!")
+
+	calls = nil
+	e := NewParamsEvent(TestEventName)
+	e.SetParam("skip", true)
+	d.Dispatch(e)
+	assert.Equal([]string{"outer:" + TestEventName, "inner"}, calls, "The middleware should be able to skip the listener!")
+}
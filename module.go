@@ -0,0 +1,119 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sort"
+)
+
+// Module is the bundle of listeners by event name, registered and replaced
+// together
+type Module map[string][]Listener
+
+// ReplaceModule registers the listeners of the module m under given name,
+// replacing the ones registered under that name before. The swap is atomic,
+// each dispatch sees either the old or the new set of listeners. The
+// dispatches in flight complete against the old set.
+func (d *EventDispatcher) ReplaceModule(name string, m Module) {
+	if rejectSealed(d, name) {
+		return
+	}
+	var entries []registration
+	added := make(map[string]listenersCollection)
+	for n, listeners := range m {
+		for _, name := range getNames(n) {
+			name = normalize(d, name)
+			if ok, _ := checkName(d, name, nil); ok == false {
+				continue
+			}
+			for _, l := range listeners {
+				id := nextID(d)
+				added[name] = append(added[name], listenerEntry{id: id, l: l})
+				entries = append(entries, registration{name, id})
+			}
+		}
+	}
+
+	var removed []registration
+	defer func() {
+		modulesChanged(d, removed, entries)
+	}()
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+	lockShards(d)
+	defer unlockShards(d)
+
+	removed = removeModule(d, name)
+	for n, listeners := range added {
+		s := shardOf(d, n)
+		for _, le := range listeners {
+			s.listeners[n] = insertEntry(s.listeners[n], le)
+		}
+		s.listeners[n] = orderDependencies(d, n, s.listeners[n])
+	}
+	if len(entries) != 0 {
+		d.modules[name] = entries
+	}
+}
+
+// RemoveModule removes all listeners of the module with given name
+func (d *EventDispatcher) RemoveModule(name string) {
+	if rejectSealed(d, name) {
+		return
+	}
+	var removed []registration
+	defer func() {
+		modulesChanged(d, removed, nil)
+	}()
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+	lockShards(d)
+	defer unlockShards(d)
+
+	removed = removeModule(d, name)
+}
+
+// Modules returns the names of the registered modules, sorted
+func (d *EventDispatcher) Modules() []string {
+	d.RWMutex.RLock()
+	defer d.RWMutex.RUnlock()
+
+	names := make([]string, 0, len(d.modules))
+	for name := range d.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// removeModule removes the listeners of the module with given name. Must be
+// called under the lock of the dispatcher and all shards. Returns the
+// registrations removed.
+func removeModule(d *EventDispatcher, name string) []registration {
+	var removed []registration
+	ids := make(map[uint64]bool)
+	names := make(map[string]bool)
+	for _, r := range d.modules[name] {
+		ids[r.id] = true
+		names[r.n] = true
+	}
+	delete(d.modules, name)
+
+	for n := range names {
+		// The listeners slice may be in use by a running dispatch, so it is
+		// never modified in place
+		s := shardOf(d, n)
+		var listeners listenersCollection
+		for _, le := range s.listeners[n] {
+			if ids[le.id] == false {
+				listeners = append(listeners, le)
+				continue
+			}
+			removed = append(removed, registration{n, le.id})
+		}
+		s.listeners[n] = listeners
+	}
+
+	return removed
+}
@@ -0,0 +1,57 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReplaceModule(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var calls []string
+	listener := func(s string) Listener {
+		return func(e Event) {
+			calls = append(calls, s)
+		}
+	}
+	d.On("a", listener("other"))
+	d.ReplaceModule("billing", Module{"a b": {listener("v1")}})
+	d.Dispatch(NewParamsEvent("a"))
+	d.Dispatch(NewParamsEvent("b"))
+	assert.Equal([]string{"other", "v1", "v1"}, calls)
+
+	calls = nil
+	d.ReplaceModule("billing", Module{"a": {listener("v2")}})
+	d.Dispatch(NewParamsEvent("a"))
+	d.Dispatch(NewParamsEvent("b"))
+	assert.Equal([]string{"other", "v2"}, calls, "The old listeners should be replaced!")
+	assert.Equal([]string{"billing"}, d.Modules())
+
+	d.RemoveModule("billing")
+	assert.Empty(d.Modules())
+	assert.True(d.HasListeners("a"), "Listeners outside the module should be kept!")
+	assert.False(d.HasListeners("b"))
+}
+
+func TestReplaceModuleInFlight(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var calls []string
+	d.ReplaceModule("m", Module{TestEventName: {
+		func(e Event) {
+			calls = append(calls, "v1 first")
+			d.ReplaceModule("m", Module{TestEventName: {func(e Event) {
+				calls = append(calls, "v2")
+			}}})
+		},
+		func(e Event) {
+			calls = append(calls, "v1 second")
+		},
+	}})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"v1 first", "v1 second"}, calls, "The dispatch in flight should complete against the old set!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal("v2", calls[2])
+}
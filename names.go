@@ -0,0 +1,153 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StrictMode defines what happens when the event with the name not
+// registered with RegisterEventName is dispatched or subscribed to
+type StrictMode int
+
+const (
+	// StrictOff accepts all event names
+	StrictOff StrictMode = iota
+
+	// StrictWarn accepts the unregistered names but reports the
+	// ErrUnknownEventName with ReportError
+	StrictWarn
+
+	// StrictReject makes TryDispatch drop the event and return the
+	// ErrUnknownEventName, the listeners are not registered and the error
+	// is reported with ReportError
+	StrictReject
+)
+
+// ErrUnknownEventName is returned or reported when the event name has not
+// been registered with RegisterEventName
+var ErrUnknownEventName = errors.New("eventdispatcher: unknown event name")
+
+// eventNames is the registry of the known event names and their
+// descriptions
+var eventNames = struct {
+	sync.RWMutex
+	names map[string]string
+}{names: map[string]string{
+	ErrorEventName: "Dispatched when a listener fails",
+}}
+
+// RegisterEventName registers the event name n along with its description,
+// so it is accepted by the dispatchers in strict mode
+func RegisterEventName(n string, description string) {
+	eventNames.Lock()
+	defer eventNames.Unlock()
+
+	eventNames.names[n] = description
+}
+
+// EventNames returns all registered event names with their descriptions
+func EventNames() map[string]string {
+	eventNames.RLock()
+	defer eventNames.RUnlock()
+
+	names := make(map[string]string, len(eventNames.names))
+	for n, desc := range eventNames.names {
+		names[n] = desc
+	}
+
+	return names
+}
+
+// WithStrictNames makes the dispatcher check the names of the dispatched
+// and subscribed events against the ones registered with RegisterEventName
+func WithStrictNames(mode StrictMode) Option {
+	return func(d *EventDispatcher) {
+		d.strict = mode
+	}
+}
+
+// checkName checks whether the name n of the dispatched event e, or the
+// subscribed one if e is nil, is registered. Returns false if it must be
+// rejected. The error is reported with ReportError on subscription, as
+// there is no other way to surface it, or when the dispatcher only warns.
+func checkName(d *EventDispatcher, n string, e Event) (bool, error) {
+	if d.strict == StrictOff {
+		return true, nil
+	}
+
+	if isRegisteredName(d, n) {
+		return true, nil
+	}
+
+	err := unknownNameError(n)
+	if e == nil {
+		d.ReportError(NewParamsEvent(n), err)
+	} else if d.strict == StrictWarn {
+		d.ReportError(e, err)
+	}
+
+	return d.strict != StrictReject, err
+}
+
+// isRegisteredName informs whether the normalized name n is registered,
+// comparing it with the registered names normalized the same way
+func isRegisteredName(d *EventDispatcher, n string) bool {
+	eventNames.RLock()
+	defer eventNames.RUnlock()
+
+	if _, ok := eventNames.names[n]; ok || d.normalizer == nil {
+		return ok
+	}
+	for name := range eventNames.names {
+		if normalize(d, name) == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unknownNameError returns the ErrUnknownEventName suggesting the closest
+// registered name, if any is similar enough
+func unknownNameError(n string) error {
+	eventNames.RLock()
+	defer eventNames.RUnlock()
+
+	var suggestion string
+	best := len(n)/3 + 1
+	for name := range eventNames.names {
+		if dist := editDistance(n, name); dist < best || (dist == best && name < suggestion) {
+			best, suggestion = dist, name
+		}
+	}
+	if suggestion == "" {
+		return fmt.Errorf("%w: %q", ErrUnknownEventName, n)
+	}
+
+	return fmt.Errorf("%w: %q, did you mean %q?", ErrUnknownEventName, n, suggestion)
+}
+
+// editDistance returns the Levenshtein distance of the strings a and b
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
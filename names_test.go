@@ -0,0 +1,59 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStrictReject(t *testing.T) {
+	assert := assert.New(t)
+	RegisterEventName("user.created", "Dispatched when the user signs up")
+	d := NewDispatcher(WithStrictNames(StrictReject))
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	d.On("user.creatd", func(e Event) {})
+	assert.False(d.HasListeners("user.creatd"), "The listener of unknown name should not be registered!")
+	assert.Equal(1, len(errs), "The subscription error should be reported!")
+
+	_, err := d.TryDispatch(NewParamsEvent("user.creatd"))
+	assert.True(errors.Is(err, ErrUnknownEventName), "Unknown names should be rejected!")
+	assert.Contains(err.Error(), `did you mean "user.created"`, "The closest name should be suggested!")
+
+	var c int
+	d.On("user.created", func(e Event) {
+		c++
+	})
+	_, err = d.TryDispatch(NewParamsEvent("user.created"))
+	assert.Nil(err)
+	assert.Equal(1, c, "Registered names should be dispatched!")
+	assert.Equal("Dispatched when the user signs up", EventNames()["user.created"])
+}
+
+func TestStrictWarn(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithStrictNames(StrictWarn))
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	var c int
+	d.On("unknown.event", func(e Event) {
+		c++
+	})
+	_, err := d.TryDispatch(NewParamsEvent("unknown.event"))
+	assert.Nil(err, "Unknown names should only be reported!")
+	assert.Equal(1, c, "The event should be dispatched!")
+	assert.Equal(2, len(errs), "Both the subscription and the dispatch should be reported!")
+}
+
+func TestEditDistance(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(0, editDistance("abc", "abc"))
+	assert.Equal(1, editDistance("user.creatd", "user.created"))
+	assert.Equal(3, editDistance("kitten", "sitting"))
+}
@@ -0,0 +1,137 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"strings"
+)
+
+// NamespaceSeparator separates the namespace from the event name
+const NamespaceSeparator = "."
+
+// NamespaceView is a Dispatcher view prefixing all event names with its
+// namespace, so "invoice.paid" registered or dispatched within the
+// "billing" namespace becomes "billing.invoice.paid" in the underlying
+// dispatcher. The copy of the dispatched event is renamed to its prefixed
+// name, so the listeners, forwarders and attached dispatchers see the name
+// it has been dispatched under, while the event of the caller is left
+// unchanged.
+type NamespaceView struct {
+	d      *EventDispatcher
+	prefix string
+}
+
+// Namespace returns the view of the dispatcher for given namespace ns
+func (d *EventDispatcher) Namespace(ns string) *NamespaceView {
+	return &NamespaceView{d, ns + NamespaceSeparator}
+}
+
+// Namespace returns the view for the namespace nested in this one
+func (v *NamespaceView) Namespace(ns string) *NamespaceView {
+	return &NamespaceView{v.d, v.prefix + ns + NamespaceSeparator}
+}
+
+// Dispatch dispatches the copy of the event renamed to its prefixed name and
+// returns it after all listeners do their jobs. The events of the types
+// defined outside of this package are wrapped, so their name can change.
+// The wrapper implements only the Event interface, so the listeners of such
+// events can not use their optional interfaces, e.g. Correlated, Failable
+// or Cancelable, and the dispatcher neither deduplicates nor fails them.
+func (v *NamespaceView) Dispatch(e Event) Event {
+	e = v.namespaced(e)
+	e, _ = tryDispatch(v.d, e.Name(), e, nil)
+
+	return e
+}
+
+// namespaced returns the copy of the event e renamed to its prefixed name
+func (v *NamespaceView) namespaced(e Event) Event {
+	n := v.prefix + e.Name()
+	c := copyEvent(e)
+	if r, ok := c.(renamer); ok {
+		r.rename(n)
+		return c
+	}
+
+	return namespacedEvent{c, n}
+}
+
+// On registers a listener for given event name within the namespace.
+func (v *NamespaceView) On(n string, l Listener) {
+	v.d.On(prefixNames(v.prefix, n), l)
+}
+
+// Once registers a listener to be executed only once for given event name
+// within the namespace.
+func (v *NamespaceView) Once(n string, l Listener) {
+	v.d.Once(prefixNames(v.prefix, n), l)
+}
+
+// Off removes all registrations of the event listener for given event name
+// within the namespace. Returns the number of listeners removed.
+func (v *NamespaceView) Off(n string, l Listener) int {
+	return v.d.Off(v.prefix+n, l)
+}
+
+// OffAll removes all listeners for given name within the namespace.
+func (v *NamespaceView) OffAll(n string) {
+	v.d.OffAll(v.prefix + n)
+}
+
+// HasListeners returns true if any listener for given event name within
+// the namespace has been assigned and false otherwise.
+func (v *NamespaceView) HasListeners(n string) bool {
+	return v.d.HasListeners(v.prefix + n)
+}
+
+// ListenerCount returns the number of listeners for given event name within
+// the namespace
+func (v *NamespaceView) ListenerCount(n string) int {
+	return v.d.ListenerCount(v.prefix + n)
+}
+
+// prefixNames prefixes each of the space separated names in n
+func prefixNames(prefix string, n string) string {
+	names := getNames(n)
+	for i, name := range names {
+		names[i] = prefix + name
+	}
+
+	return strings.Join(names, " ")
+}
+
+// renamer is implemented by the events of this package, which the
+// NamespaceView renames once copied
+type renamer interface {
+	rename(n string)
+}
+
+// namespacedEvent is the event of an unknown type under its prefixed name
+type namespacedEvent struct {
+	Event
+	name string
+}
+
+// Name returns the prefixed name of the event
+func (e namespacedEvent) Name() string {
+	return e.name
+}
+
+func (event *ParamsEvent) rename(n string) {
+	event.name = n
+}
+
+func (event *ConcurrentParamsEvent) rename(n string) {
+	event.Lock()
+	defer event.Unlock()
+
+	event.event.rename(n)
+}
+
+func (event *PayloadEvent[T]) rename(n string) {
+	event.name = n
+}
+
+func (event *ImmutableEvent) rename(n string) {
+	event.name = n
+}
@@ -0,0 +1,86 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNamespace(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var ns Dispatcher = d.Namespace("billing")
+	var names []string
+	ns.On("invoice.paid invoice.sent", func(e Event) {
+		names = append(names, e.Name())
+	})
+	assert.True(d.HasListeners("billing.invoice.paid"), "The listener should be registered under the prefixed name!")
+	assert.True(d.HasListeners("billing.invoice.sent"), "The listener should be registered under the prefixed name!")
+	assert.False(d.HasListeners("invoice.paid"), "The listener should not be registered under the raw name!")
+
+	ns.Dispatch(NewParamsEvent("invoice.paid"))
+	d.Dispatch(NewParamsEvent("billing.invoice.sent"))
+	d.Dispatch(NewParamsEvent("invoice.paid"))
+	assert.Equal([]string{"billing.invoice.paid", "billing.invoice.sent"}, names, "Invalid events received within the namespace!")
+
+	ns.OffAll("invoice.paid")
+	assert.False(ns.HasListeners("invoice.paid"), "All listeners should be removed within the namespace!")
+}
+
+func TestNestedNamespace(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.On("billing.invoice.paid", func(e Event) {
+		c++
+	})
+	d.Namespace("billing").Namespace("invoice").Dispatch(NewParamsEvent("paid"))
+	assert.Equal(1, c, "Nested namespaces should join the prefixes!")
+}
+
+// customEvent is the event of the type the namespace cannot rename
+type customEvent struct {
+	Event
+}
+
+func TestNamespaceKeepsPrefixedName(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	child := NewDispatcher()
+	d.Attach(child)
+	var all, children []string
+	d.OnAny(func(e Event) {
+		all = append(all, e.Name())
+	})
+	child.On("billing.invoice.paid", func(e Event) {
+		children = append(children, e.Name())
+	})
+
+	e := d.Namespace("billing").Dispatch(NewPayloadEvent("invoice.paid", 10))
+	assert.Equal("billing.invoice.paid", e.Name(), "The dispatched event should carry the prefixed name!")
+	assert.Equal([]string{"billing.invoice.paid"}, all, "The catch-all listener should see the prefixed name!")
+	assert.Equal([]string{"billing.invoice.paid"}, children, "The attached dispatcher should receive the prefixed name!")
+
+	d.Namespace("billing").Dispatch(customEvent{NewParamsEvent("invoice.sent")})
+	assert.Equal("billing.invoice.sent", all[1], "The events of the unknown types should carry the prefixed name!")
+}
+
+func TestNamespaceKeepsCallerEvent(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var names []string
+	d.OnAny(func(e Event) {
+		names = append(names, e.Name())
+	})
+	ns := d.Namespace("billing")
+	e := NewParamsEvent("paid")
+	ns.Dispatch(e)
+	ns.Dispatch(e)
+	assert.Equal([]string{"billing.paid", "billing.paid"}, names, "The event should be prefixed once per dispatch!")
+	assert.Equal("paid", e.Name(), "The event of the caller should not be renamed!")
+
+	ie := NewEvent("sent").MustBuild()
+	assert.Equal("billing.sent", ns.Dispatch(ie).Name(), "The copy should carry the prefixed name!")
+	assert.Equal("sent", ie.Name(), "The immutable event should not be renamed!")
+}
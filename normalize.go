@@ -0,0 +1,87 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"strings"
+)
+
+// NameNormalizer maps the event name to its canonical form. It must be
+// idempotent, as the names may be normalized more than once.
+type NameNormalizer func(string) string
+
+// WithNameNormalizer makes the dispatcher normalize the event names with
+// the normalizers, in the given order, when registering and removing the
+// listeners, dispatching the events and defining the aliases, so e.g.
+// "User.Created" and "user.created" are the same event. The names of the
+// per-name configuration, like the filters, schemas, rate limits, request
+// handlers and derivations, are normalized as well.
+func WithNameNormalizer(normalizers ...NameNormalizer) Option {
+	return func(d *EventDispatcher) {
+		prev := d.normalizer
+		d.normalizer = func(n string) string {
+			if prev != nil {
+				n = prev(n)
+			}
+			for _, f := range normalizers {
+				n = f(n)
+			}
+			return n
+		}
+	}
+}
+
+// LowercaseName is the NameNormalizer lowercasing the name
+func LowercaseName(n string) string {
+	return strings.ToLower(n)
+}
+
+// TrimName is the NameNormalizer removing the leading and trailing
+// separators and white space
+func TrimName(n string) string {
+	return strings.Trim(strings.TrimSpace(n), NamespaceSeparator)
+}
+
+// SeparatorName returns the NameNormalizer replacing the separators, e.g.
+// "/" or ":", with the NamespaceSeparator
+func SeparatorName(separators ...string) NameNormalizer {
+	pairs := make([]string, 0, 2*len(separators))
+	for _, sep := range separators {
+		pairs = append(pairs, sep, NamespaceSeparator)
+	}
+	r := strings.NewReplacer(pairs...)
+
+	return r.Replace
+}
+
+// normalizeOptions normalizes the event names the options configured the
+// dispatcher for, as the normalizer may be set by the later option
+func normalizeOptions(d *EventDispatcher) {
+	if d.normalizer == nil {
+		return
+	}
+	d.rateLimiters = normalizeKeys(d, d.rateLimiters)
+	d.sampling = normalizeKeys(d, d.sampling)
+	d.retryPolicies = normalizeKeys(d, d.retryPolicies)
+}
+
+// normalizeKeys returns the copy of the map m keyed by the normalized event
+// names
+func normalizeKeys[T any](d *EventDispatcher, m map[string]T) map[string]T {
+	normalized := make(map[string]T, len(m))
+	for n, v := range m {
+		normalized[normalize(d, n)] = v
+	}
+
+	return normalized
+}
+
+// normalize returns the event name n normalized with the normalizer of the
+// dispatcher, if any
+func normalize(d *EventDispatcher, n string) string {
+	if d.normalizer == nil {
+		return n
+	}
+
+	return d.normalizer(n)
+}
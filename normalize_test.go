@@ -0,0 +1,131 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithNameNormalizer(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithNameNormalizer(SeparatorName("/", ":"), TrimName, LowercaseName))
+	var c int
+	l := func(e Event) {
+		c++
+	}
+	d.On("User.Created", l)
+	d.Dispatch(NewParamsEvent("user.created"))
+	d.Dispatch(NewParamsEvent("user/created"))
+	d.Dispatch(NewParamsEvent(" USER:CREATED. "))
+	assert.Equal(3, c, "All spellings should reach the same listeners!")
+	assert.True(d.HasListeners("user:created"), "The listeners should be found by any spelling!")
+	assert.Equal(1, d.Off("USER.CREATED", l), "The listener should be removed by any spelling!")
+	assert.False(d.HasListeners("user.created"), "The listener should be removed!")
+}
+
+func TestNormalizedFilters(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithNameNormalizer(LowercaseName))
+	var c int
+	d.On("user.created user.renamed", func(e Event) {
+		c++
+	})
+	d.AddFilterOn("User.Created", func(e Event) (Event, bool) {
+		return e.(*ParamsEvent).WithName("USER.RENAMED"), true
+	})
+	d.AddFilterOn("User.Renamed", func(e Event) (Event, bool) {
+		return e, false
+	})
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal(0, c, "The filters should be applied by any spelling of the name!")
+}
+
+func TestNormalizedSchema(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithNameNormalizer(LowercaseName))
+	d.SetSchema("User.Created", NewSchema().Require("id", nil), ValidationReject)
+	_, err := d.TryDispatch(NewParamsEvent("user.created"))
+	var ve *ValidationError
+	assert.True(errors.As(err, &ve), "The schema should be applied by any spelling of the name!")
+}
+
+func TestNormalizedOptions(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(
+		WithRateLimit("User.Created", 1, 1, RateLimitError),
+		WithSampling("User.Deleted", 0),
+		WithSyncMode(),
+		WithRetryPolicy("User.Updated", RetryPolicy{MaxAttempts: 2}),
+		WithNameNormalizer(LowercaseName))
+	var deleted, attempts int
+	d.On("user.deleted", func(e Event) {
+		deleted++
+	})
+	d.OnRetry("user.updated", func(e Event) error {
+		attempts++
+		return errors.New("failed")
+	}, nil)
+
+	d.Dispatch(NewParamsEvent("user.created"))
+	_, err := d.TryDispatch(NewParamsEvent("user.created"))
+	assert.ErrorIs(err, ErrRateLimited, "The rate limit should be applied by any spelling of the name!")
+	d.Dispatch(NewParamsEvent("user.deleted"))
+	assert.Equal(0, deleted, "The sampling should be applied by any spelling of the name!")
+	d.Dispatch(NewParamsEvent("user.updated"))
+	assert.Equal(2, attempts, "The retry policy should be found by any spelling of the name!")
+}
+
+func TestNormalizedRequest(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithNameNormalizer(LowercaseName))
+	err := d.Handle("User.Get", func(e Event) (Response, error) {
+		return "user", nil
+	})
+	assert.Nil(err, "The handler should be registered!")
+	assert.ErrorIs(d.Handle("user.get", nil), ErrHandlerExists, "The handler should be found by any spelling of the name!")
+	assert.True(d.HasHandler("USER.GET"), "The handler should be found by any spelling of the name!")
+	resp, err := d.Request(NewParamsEvent("user.get"))
+	assert.Nil(err, "The request should be handled!")
+	assert.Equal("user", resp, "Invalid response!")
+	d.RemoveHandler("user.GET")
+	assert.False(d.HasHandler("user.get"), "The handler should be removed by any spelling of the name!")
+}
+
+func TestNormalizedDerive(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithNameNormalizer(LowercaseName))
+	var names []string
+	d.On("email.send", func(e Event) {
+		names = append(names, e.Name())
+	})
+	_, err := d.Derive("Order.Paid", "Email.Send", nil)
+	assert.Nil(err, "The derivation should be registered!")
+	_, err = d.Derive("email.send", "ORDER.PAID", nil)
+	assert.ErrorIs(err, ErrDerivationCycle, "The cycle should be detected by any spelling of the names!")
+	d.Dispatch(NewParamsEvent("order.paid"))
+	assert.Equal([]string{"email.send"}, names, "The event should be derived by any spelling of the names!")
+}
+
+func TestNormalizedStrictNames(t *testing.T) {
+	assert := assert.New(t)
+	RegisterEventName("Normalized.Registered", "Registered with capitals")
+	d := NewDispatcher(WithStrictNames(StrictReject), WithNameNormalizer(LowercaseName))
+	_, err := d.TryDispatch(NewParamsEvent("normalized.registered"))
+	assert.Nil(err, "The registered name should be found by any spelling!")
+}
+
+func TestNormalizedAuthorizer(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithNameNormalizer(LowercaseName), WithAuthorizer(NamespacePolicy{"billing": {"billing"}}))
+	d.As("billing").On("Billing.Invoice.Paid", func(e Event) {})
+	assert.True(d.HasListeners("billing.invoice.paid"), "The authorizer should check the normalized name!")
+}
+
+func TestNameNormalizers(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("a.b", TrimName(" .a.b. "))
+	assert.Equal("a.b.c", SeparatorName("/", "::")("a/b::c"))
+	assert.Equal("a.b", LowercaseName("A.B"))
+}
@@ -0,0 +1,106 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// ReadOnlyEvent is the view of the event passed to the observers. It gives
+// access to the params but neither to their setters nor to the event
+// itself, so the observer cannot change the event by accident.
+type ReadOnlyEvent interface {
+
+	// Name returns the event name
+	Name() string
+
+	// IsPropagationStopped informs weather the event should
+	// be further propagated or not
+	IsPropagationStopped() bool
+
+	// HasParam defines if a param with given key exists
+	HasParam(k string) bool
+
+	// GetParam returns a parameter value for given key and whether it
+	// existed
+	GetParam(k string) (interface{}, bool)
+
+	// Params returns a copy of all params of the event
+	Params() map[string]interface{}
+}
+
+// Observer is the listener receiving the read-only view of the event
+type Observer func(e ReadOnlyEvent)
+
+// paramsReader is implemented by the events carrying the params, e.g. the
+// ParamsEvent, ConcurrentParamsEvent and ImmutableEvent
+type paramsReader interface {
+	HasParam(k string) bool
+	GetParam(k string) (interface{}, bool)
+	Params() map[string]interface{}
+}
+
+// eventView is the ReadOnlyEvent wrapping the event
+type eventView struct {
+	e Event
+}
+
+// Name returns the name of the event
+func (v eventView) Name() string {
+	return v.e.Name()
+}
+
+// IsPropagationStopped informs weather the event should
+// be further propagated or not
+func (v eventView) IsPropagationStopped() bool {
+	return v.e.IsPropagationStopped()
+}
+
+// HasParam defines if a param with given key exists, always false for the
+// events without params
+func (v eventView) HasParam(k string) bool {
+	if p, ok := v.e.(paramsReader); ok {
+		return p.HasParam(k)
+	}
+
+	return false
+}
+
+// GetParam returns a parameter value for given key and whether it existed
+func (v eventView) GetParam(k string) (interface{}, bool) {
+	if p, ok := v.e.(paramsReader); ok {
+		return p.GetParam(k)
+	}
+
+	return nil, false
+}
+
+// Params returns a copy of all params of the event, empty for the events
+// without params
+func (v eventView) Params() map[string]interface{} {
+	if p, ok := v.e.(paramsReader); ok {
+		return p.Params()
+	}
+
+	return make(map[string]interface{})
+}
+
+// ReadOnly returns the read-only view of the event e
+func ReadOnly(e Event) ReadOnlyEvent {
+	return eventView{e}
+}
+
+// Observe registers the observer o for given event name (may contain many
+// space separated names). Observers are called in the PhasePost, after the
+// listeners registered with On had a chance to change the event, so they
+// see its final state, even if the propagation has been stopped. Returns
+// the subscription removing the observer.
+func (d *EventDispatcher) Observe(n string, o Observer) *Subscription {
+	l := func(e Event) {
+		o(ReadOnly(e))
+	}
+	s := &Subscription{d: d}
+	for _, name := range getNames(n) {
+		id := nextID(d)
+		addEntry(d, name, listenerEntry{id: id, l: l, phase: PhasePost})
+		s.registrations = append(s.registrations, registration{name, id})
+	}
+
+	return s
+}
@@ -0,0 +1,45 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestObserve(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var seen []interface{}
+	s := d.Observe(TestEventName, func(e ReadOnlyEvent) {
+		_, isEvent := e.(Event)
+		assert.False(isEvent, "The observer should not get the full event!")
+		v, _ := e.GetParam("status")
+		seen = append(seen, v)
+	})
+	d.On(TestEventName, func(e Event) {
+		e.(*ParamsEvent).SetParam("status", "enriched")
+		e.StopPropagation()
+	})
+
+	d.Dispatch(NewParamsEvent(TestEventName).SetParam("status", "new"))
+	assert.Equal([]interface{}{"enriched"}, seen, "The observer should see the event changed by the listeners!")
+
+	s.Unsubscribe()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, len(seen), "The unsubscribed observer should not be called!")
+}
+
+func TestReadOnly(t *testing.T) {
+	assert := assert.New(t)
+	v := ReadOnly(NewEvent(TestEventName).Param("a", 1).MustBuild())
+	assert.True(v.HasParam("a"))
+	assert.Equal(map[string]interface{}{"a": 1}, v.Params())
+
+	v = ReadOnly(NewPayloadEvent(TestEventName, 1))
+	assert.Equal(TestEventName, v.Name())
+	assert.False(v.HasParam("a"), "The event without params should have no params!")
+	_, ok := v.GetParam("a")
+	assert.False(ok)
+	assert.Equal(0, len(v.Params()))
+}
@@ -0,0 +1,120 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ListenerID identifies the registration of the listener, as passed to the
+// middlewares in the ListenerCall
+type ListenerID = uint64
+
+// ErrUnknownListener is returned by SetListenerOrder when the listener is
+// not registered for the event name
+var ErrUnknownListener = errors.New("eventdispatcher: unknown listener")
+
+// Order returns the identifiers of the listeners registered for the event
+// name n in the order they are called, matching the order of ListenerNames.
+// The listeners of the matching patterns and the aliases are not included.
+func (d *EventDispatcher) Order(n string) []ListenerID {
+	n = normalize(d, n)
+	s := shardOf(d, n)
+	s.RLock()
+	defer s.RUnlock()
+
+	ids := make([]ListenerID, len(s.listeners[n]))
+	for i, le := range s.listeners[n] {
+		ids[i] = le.id
+	}
+
+	return ids
+}
+
+// SetListenerOrder makes the listeners with given identifiers, registered
+// for the event name n, be called in the given order, e.g. to resolve the
+// conflicts of the plugins. The listeners not in the order follow the
+// ordered ones, keeping their relative order. Phases still take precedence,
+// the listeners are only reordered within their phase, and so do the
+// dependencies set with RunAfter, the listener still following the ones it
+// runs after. Returns ErrUnknownListener, reordering none, if any identifier is not registered
+// for the name.
+func (d *EventDispatcher) SetListenerOrder(n string, order []ListenerID) error {
+	n = normalize(d, n)
+	s := shardOf(d, n)
+	s.Lock()
+	defer s.Unlock()
+
+	position := make(map[ListenerID]int, len(s.listeners[n]))
+	for _, le := range s.listeners[n] {
+		position[le.id] = len(order)
+	}
+	for i, id := range order {
+		if _, ok := position[id]; ok == false {
+			return fmt.Errorf("%w: %d for %s", ErrUnknownListener, id, n)
+		}
+		position[id] = i
+	}
+
+	// The listeners slice may be in use by a running dispatch, so it is
+	// never modified in place
+	listeners := append(listenersCollection(nil), s.listeners[n]...)
+	sort.SliceStable(listeners, func(i, j int) bool {
+		if listeners[i].phase != listeners[j].phase {
+			return listeners[i].phase < listeners[j].phase
+		}
+		return position[listeners[i].id] < position[listeners[j].id]
+	})
+	s.listeners[n] = orderDependencies(d, n, listeners)
+
+	return nil
+}
+
+// mergeListeners merges the listeners of many names, each sorted by phase
+// and then in the order of registration or the one set with
+// SetListenerOrder. The merged listeners are sorted by phase and keep the
+// order of each source, the listeners of different sources following the
+// order of registration. The listener found in many sources is taken once.
+func mergeListeners(sources []listenersCollection) listenersCollection {
+	var size int
+	for _, listeners := range sources {
+		size += len(listeners)
+	}
+
+	merged := make(listenersCollection, 0, size)
+	seen := make(map[ListenerID]bool, size)
+	heads := make([]int, len(sources))
+	for {
+		next := -1
+		for i, listeners := range sources {
+			for heads[i] < len(listeners) && seen[listeners[heads[i]].id] {
+				heads[i]++
+			}
+			if heads[i] == len(listeners) {
+				continue
+			}
+			if next == -1 || precedes(listeners[heads[i]], sources[next][heads[next]]) {
+				next = i
+			}
+		}
+		if next == -1 {
+			return merged
+		}
+		le := sources[next][heads[next]]
+		seen[le.id] = true
+		merged = append(merged, le)
+		heads[next]++
+	}
+}
+
+// precedes informs whether the listener entry a is called before b when
+// they come from different sources
+func precedes(a listenerEntry, b listenerEntry) bool {
+	if a.phase != b.phase {
+		return a.phase < b.phase
+	}
+
+	return a.id < b.id
+}
@@ -0,0 +1,95 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetListenerOrder(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		d.On(TestEventName, func(e Event) {
+			got = append(got, name)
+		})
+	}
+	d.OnPhase(TestEventName, PhasePre, func(e Event) {
+		got = append(got, "pre")
+	})
+	ids := d.Order(TestEventName)
+	assert.Len(ids, 5, "All listeners should be listed!")
+
+	assert.Nil(d.SetListenerOrder(TestEventName, []ListenerID{ids[4], ids[2], ids[0]}), "The listeners should be reordered!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"pre", "d", "b", "a", "c"}, got, "The listeners should be called in the set order within their phase!")
+
+	err := d.SetListenerOrder(TestEventName, []ListenerID{ids[1], 12345})
+	assert.ErrorIs(err, ErrUnknownListener, "The unknown listener should be rejected!")
+	assert.Equal([]ListenerID{ids[0], ids[4], ids[2], ids[1], ids[3]}, d.Order(TestEventName), "The order should not change on error!")
+}
+
+func TestSetListenerOrderWildcard(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	var got []string
+	for _, name := range []string{"a", "b"} {
+		name := name
+		d.On("user.created", func(e Event) {
+			got = append(got, name)
+		})
+	}
+	d.On("user.*", func(e Event) {
+		got = append(got, "wildcard")
+	})
+	ids := d.Order("user.created")
+	assert.Nil(d.SetListenerOrder("user.created", []ListenerID{ids[1], ids[0]}), "The listeners should be reordered!")
+
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal([]string{"b", "a", "wildcard"}, got, "The set order should be kept along with the wildcard listeners!")
+}
+
+func TestSetListenerOrderRouter(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRouter(func(e Event) []string {
+		return []string{"user.created", "audit"}
+	}))
+	var got []string
+	for _, name := range []string{"a", "b"} {
+		name := name
+		d.On("user.created", func(e Event) {
+			got = append(got, name)
+		})
+	}
+	d.On("audit", func(e Event) {
+		got = append(got, "audit")
+	})
+	ids := d.Order("user.created")
+	assert.Nil(d.SetListenerOrder("user.created", []ListenerID{ids[1], ids[0]}), "The listeners should be reordered!")
+
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal([]string{"b", "a", "audit"}, got, "The set order should be kept by the routed dispatch!")
+}
+
+func TestSetListenerOrderRunAfter(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	named := func(name string) Listener {
+		return func(e Event) {
+			got = append(got, name)
+		}
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		d.OnNamed(TestEventName, name, named(name))
+	}
+	assert.Nil(d.RunAfter(TestEventName, "b", "a"))
+	ids := d.Order(TestEventName)
+
+	assert.Nil(d.SetListenerOrder(TestEventName, []ListenerID{ids[1], ids[2], ids[0]}))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"c", "a", "b"}, got, "The listener should still run after its dependency!")
+}
@@ -0,0 +1,114 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// Sequenced is the event stamped with the sequence number by
+// DispatchOrdered
+type Sequenced interface {
+
+	// Sequence returns the sequence number of the event within its name
+	Sequence() uint64
+
+	// SetSequence sets the sequence number of the event
+	SetSequence(seq uint64)
+}
+
+// orderedLanes dispatches the events in the background keeping their
+// order per event name, using at most workers dispatches at once
+type orderedLanes struct {
+	sync.Mutex
+	workers chan struct{}
+	seqs    map[string]uint64
+	lanes   map[string]*lane
+}
+
+// lane is the queue of the events with the same name
+type lane struct {
+	queue   []Event
+	running bool
+}
+
+// WithOrderedWorkers sets the max number of events dispatched at once by
+// DispatchOrdered. Defaults to GOMAXPROCS.
+func WithOrderedWorkers(n int) Option {
+	return func(d *EventDispatcher) {
+		d.ordered = newOrderedLanes(n)
+	}
+}
+
+// DispatchOrdered dispatches the event in the background. Events with the
+// same name are stamped with the increasing sequence numbers and the
+// listeners receive them in that order, while the events with different
+// names are dispatched concurrently. Returns the sequence number. Use Drain
+// to wait until the events are processed.
+func (d *EventDispatcher) DispatchOrdered(e Event) uint64 {
+	o := d.ordered
+	n := e.Name()
+
+	o.Lock()
+	o.seqs[n]++
+	seq := o.seqs[n]
+	if se, ok := e.(Sequenced); ok {
+		se.SetSequence(seq)
+	}
+	l, ok := o.lanes[n]
+	if ok == false {
+		l = &lane{}
+		o.lanes[n] = l
+	}
+	l.queue = append(l.queue, e)
+	start := l.running == false
+	l.running = true
+	o.Unlock()
+
+	if start {
+		async(d, func() {
+			runLane(d, o, n, l)
+		})
+	}
+
+	return seq
+}
+
+// runLane dispatches the events of the lane with name n one by one until
+// the lane is empty
+func runLane(d *EventDispatcher, o *orderedLanes, n string, l *lane) {
+	for {
+		o.Lock()
+		if len(l.queue) == 0 {
+			l.running = false
+			delete(o.lanes, n)
+			o.Unlock()
+			return
+		}
+		e := l.queue[0]
+		l.queue = l.queue[1:]
+		o.Unlock()
+
+		// The sync mode runs the lanes inline, so there is nothing to limit
+		// and the lane of the other name started by a listener would wait
+		// for the slot held by this one forever
+		if d.syncMode {
+			d.Dispatch(e)
+			continue
+		}
+		o.workers <- struct{}{}
+		d.Dispatch(e)
+		<-o.workers
+	}
+}
+
+func newOrderedLanes(workers int) *orderedLanes {
+	if workers < 1 {
+		workers = 1
+	}
+	return &orderedLanes{
+		workers: make(chan struct{}, workers),
+		seqs:    make(map[string]uint64),
+		lanes:   make(map[string]*lane),
+	}
+}
@@ -0,0 +1,66 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatchOrdered(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithOrderedWorkers(4))
+	var mu sync.Mutex
+	seqs := make(map[string][]uint64)
+	listener := func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		seqs[e.Name()] = append(seqs[e.Name()], e.(Sequenced).Sequence())
+	}
+	d.On("a b", listener)
+	for i := 0; i < 50; i++ {
+		d.DispatchOrdered(NewParamsEvent("a"))
+		d.DispatchOrdered(NewParamsEvent("b"))
+	}
+	d.Drain()
+	for _, n := range []string{"a", "b"} {
+		assert.Equal(50, len(seqs[n]), "All events should be dispatched!")
+		for i, seq := range seqs[n] {
+			assert.Equal(uint64(i+1), seq, "The events should be received in the sequence order!")
+		}
+	}
+}
+
+func TestDispatchOrderedReturnsSequence(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode())
+	assert.Equal(uint64(1), d.DispatchOrdered(NewParamsEvent("a")))
+	assert.Equal(uint64(2), d.DispatchOrdered(NewParamsEvent("a")))
+	assert.Equal(uint64(1), d.DispatchOrdered(NewParamsEvent("b")), "The sequences should be kept per event name!")
+}
+
+func TestDispatchOrderedSyncModeNested(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode(), WithOrderedWorkers(1))
+	var got []string
+	d.On("a", func(e Event) {
+		got = append(got, "a")
+		d.DispatchOrdered(NewParamsEvent("b"))
+	})
+	d.On("b", func(e Event) {
+		got = append(got, "b")
+	})
+	done := make(chan struct{})
+	go func() {
+		d.DispatchOrdered(NewParamsEvent("a"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The nested ordered dispatch should not deadlock!")
+	}
+	assert.Equal([]string{"a", "b"}, got, "The nested event should be dispatched inline!")
+}
@@ -0,0 +1,254 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PathSeparator separates the keys of the param path, e.g. user.address.city
+const PathSeparator = "."
+
+// ErrInvalidPath is returned when the value cannot be set under the param path
+var ErrInvalidPath = errors.New("eventdispatcher: invalid path")
+
+// GetPath returns the value nested in the params under given path, e.g.
+// user.address.city. The path keys are the keys of the maps, the names or
+// JSON names of the exported struct fields and the indexes of the slices.
+// If the value does not exist, returns an empty string. Second value
+// returned says if the value existed.
+func (event *ParamsEvent) GetPath(path string) (value interface{}, ok bool) {
+	keys := strings.Split(path, PathSeparator)
+	v, ok := event.params[keys[0]]
+	if ok == false {
+		return "", false
+	}
+	rv := reflect.ValueOf(v)
+	for _, k := range keys[1:] {
+		if rv, ok = pathChild(rv, k); ok == false {
+			return "", false
+		}
+	}
+	if rv.IsValid() == false {
+		return nil, true
+	}
+
+	return rv.Interface(), true
+}
+
+// SetPath sets the value v nested in the params under given path, creating
+// the missing maps on the way. Structs held by value are copied, the ones
+// held by pointer are modified in place. Returns ErrInvalidPath if the
+// value cannot be set, e.g. the path goes through a string.
+func (event *ParamsEvent) SetPath(path string, v interface{}) error {
+	keys := strings.Split(path, PathSeparator)
+	if len(keys) == 1 {
+		event.SetParam(path, v)
+		return nil
+	}
+	// The nested maps and the structs held by pointer are changed in place,
+	// so the tracked value is copied before
+	old, ok := event.params[keys[0]]
+	if event.changes.pending(keys[0]) {
+		old = deepCopy(old)
+	}
+	rv, err := setPath(reflect.ValueOf(event.params[keys[0]]), keys[1:], v)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidPath, path, err)
+	}
+	event.changes.remember(keys[0], old, ok)
+	event.params[keys[0]] = rv.Interface()
+
+	return nil
+}
+
+// GetPath returns the value nested in the params under given path
+func (event *ConcurrentParamsEvent) GetPath(path string) (value interface{}, ok bool) {
+	event.RLock()
+	defer event.RUnlock()
+
+	return event.event.GetPath(path)
+}
+
+// SetPath sets the value v nested in the params under given path
+func (event *ConcurrentParamsEvent) SetPath(path string, v interface{}) error {
+	event.Lock()
+	defer event.Unlock()
+
+	return event.event.SetPath(path, v)
+}
+
+// pathChild returns the value under the key k of the map, struct or slice v
+func pathChild(v reflect.Value, k string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		c := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+		return c, c.IsValid()
+	case reflect.Struct:
+		i, ok := fieldIndex(v.Type(), k)
+		if ok == false {
+			return reflect.Value{}, false
+		}
+		// Fails on the nil pointer to the embedded struct
+		f, err := v.FieldByIndexErr(i)
+		return f, err == nil
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, false
+		}
+		return v.Index(i), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// setPath sets the value x under the keys nested in v. Returns the value
+// to be stored in place of v, which differs from v if v was created or
+// copied.
+func setPath(v reflect.Value, keys []string, x interface{}) (reflect.Value, error) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return setPath(reflect.Value{}, keys, x)
+		}
+		return setPath(v.Elem(), keys, x)
+	}
+	if v.IsValid() == false {
+		v = reflect.ValueOf(make(map[string]interface{}))
+	}
+	k := keys[0]
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		c, err := setPath(v.Elem(), keys, x)
+		if err != nil {
+			return v, err
+		}
+		return v, assign(v.Elem(), c)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v, fmt.Errorf("map key of %s is not a string", v.Type())
+		}
+		if v.IsNil() {
+			v = reflect.MakeMap(v.Type())
+		}
+		key := reflect.ValueOf(k).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		c, err := setChild(v.MapIndex(key), elem.Type(), keys, x)
+		if err != nil {
+			return v, err
+		}
+		if err := assign(elem, c); err != nil {
+			return v, err
+		}
+		v.SetMapIndex(key, elem)
+		return v, nil
+	case reflect.Struct, reflect.Array:
+		// Values held by interfaces and maps are not addressable
+		if v.CanAddr() == false {
+			a := reflect.New(v.Type()).Elem()
+			a.Set(v)
+			v = a
+		}
+		f, ok := addressableChild(v, k)
+		if ok == false {
+			return v, fmt.Errorf("no %s in %s", k, v.Type())
+		}
+		c, err := setChild(f, f.Type(), keys, x)
+		if err != nil {
+			return v, err
+		}
+		return v, assign(f, c)
+	case reflect.Slice:
+		f, ok := addressableChild(v, k)
+		if ok == false {
+			return v, fmt.Errorf("index %s out of range of %s", k, v.Type())
+		}
+		c, err := setChild(f, f.Type(), keys, x)
+		if err != nil {
+			return v, err
+		}
+		return v, assign(f, c)
+	}
+
+	return v, fmt.Errorf("cannot set %s in %s", k, v.Type())
+}
+
+// setChild returns the value replacing the child c of type t of the
+// container being set, the value x itself for the last key
+func setChild(c reflect.Value, t reflect.Type, keys []string, x interface{}) (reflect.Value, error) {
+	if len(keys) == 1 {
+		return reflect.ValueOf(x), nil
+	}
+	// The missing child of the concrete type is created from its zero value,
+	// so it matches the type of the container
+	if c.IsValid() == false && t.Kind() != reflect.Interface {
+		c = reflect.Zero(t)
+	}
+
+	return setPath(c, keys[1:], x)
+}
+
+// addressableChild returns the settable struct field, array or slice
+// element under the key k of v
+func addressableChild(v reflect.Value, k string) (reflect.Value, bool) {
+	if v.Kind() == reflect.Struct {
+		i, ok := fieldIndex(v.Type(), k)
+		if ok == false {
+			return reflect.Value{}, false
+		}
+		f, err := v.FieldByIndexErr(i)
+		return f, err == nil
+	}
+	i, err := strconv.Atoi(k)
+	if err != nil || i < 0 || i >= v.Len() {
+		return reflect.Value{}, false
+	}
+
+	return v.Index(i), true
+}
+
+// assign sets the settable dst to v, the zero value if v is invalid
+func assign(dst reflect.Value, v reflect.Value) error {
+	if v.IsValid() == false {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if v.Type().AssignableTo(dst.Type()) == false {
+		return fmt.Errorf("%s is not assignable to %s", v.Type(), dst.Type())
+	}
+	dst.Set(v)
+
+	return nil
+}
+
+// fieldIndex returns the index of the exported field of the struct type t
+// with given name or JSON name
+func fieldIndex(t reflect.Type, k string) ([]int, bool) {
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() == false || f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Name == k || name == k {
+			return f.Index, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,91 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type pathAddress struct {
+	City   string `json:"city"`
+	Street string
+}
+
+type pathUser struct {
+	Name    string
+	Address pathAddress `json:"address"`
+	Tags    []string
+}
+
+func TestGetPath(t *testing.T) {
+	assert := assert.New(t)
+	e := NewParamsEvent(TestEventName)
+	e.SetParam("request", map[string]interface{}{
+		"headers": map[string]string{"host": "example.com"},
+	})
+	e.SetParam("user", &pathUser{Name: "john", Address: pathAddress{City: "Warsaw"}, Tags: []string{"a", "b"}})
+
+	v, ok := e.GetPath("request.headers.host")
+	assert.True(ok, "The nested map value should exist!")
+	assert.Equal("example.com", v)
+	v, ok = e.GetPath("user.address.city")
+	assert.True(ok, "The struct field should be found by its JSON name!")
+	assert.Equal("Warsaw", v)
+	v, _ = e.GetPath("user.Address.City")
+	assert.Equal("Warsaw", v, "The struct field should be found by its name!")
+	v, _ = e.GetPath("user.Tags.1")
+	assert.Equal("b", v, "The slice element should be found by its index!")
+
+	for _, path := range []string{"missing", "request.missing", "user.Tags.2", "user.Name.first", "user.address.city.x"} {
+		v, ok = e.GetPath(path)
+		assert.False(ok, "The value should not exist!")
+		assert.Equal("", v)
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	assert := assert.New(t)
+	e := NewParamsEvent(TestEventName)
+
+	assert.Nil(e.SetPath("user.address.city", "Warsaw"))
+	v, _ := e.GetParam("user")
+	assert.Equal(map[string]interface{}{"address": map[string]interface{}{"city": "Warsaw"}}, v, "The missing maps should be created!")
+
+	u := &pathUser{Tags: []string{"a"}}
+	e.SetParam("pointer", u)
+	assert.Nil(e.SetPath("pointer.address.city", "Cracow"))
+	assert.Nil(e.SetPath("pointer.Tags.0", "b"))
+	assert.Equal("Cracow", u.Address.City, "The struct held by pointer should be modified in place!")
+	assert.Equal("b", u.Tags[0])
+
+	e.SetParam("value", pathUser{Name: "john"})
+	assert.Nil(e.SetPath("value.Address.Street", "Main"))
+	v, _ = e.GetParam("value")
+	assert.Equal(pathUser{Name: "john", Address: pathAddress{Street: "Main"}}, v, "The struct held by value should be replaced by the copy!")
+
+	e.SetParam("typed", map[string]map[string]int{})
+	assert.Nil(e.SetPath("typed.a.b", 1))
+	v, _ = e.GetPath("typed.a.b")
+	assert.Equal(1, v, "The missing map of the concrete type should be created!")
+
+	e.SetParam("name", "john")
+	for _, err := range []error{
+		e.SetPath("name.first", "john"),
+		e.SetPath("value.Missing", 1),
+		e.SetPath("value.Name", 1),
+		e.SetPath("pointer.Tags.5", "c"),
+	} {
+		assert.True(errors.Is(err, ErrInvalidPath), "The value should not be set!")
+	}
+}
+
+func TestConcurrentPath(t *testing.T) {
+	assert := assert.New(t)
+	e := NewConcurrentParamsEvent(TestEventName)
+	assert.Nil(e.SetPath("a.b", 1))
+	v, ok := e.GetPath("a.b")
+	assert.True(ok)
+	assert.Equal(1, v)
+}
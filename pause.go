@@ -0,0 +1,123 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultPauseBufferSize is the max number of events buffered while the
+// dispatcher is paused, unless configured with WithPauseBuffer
+const DefaultPauseBufferSize = 1024
+
+// OverflowPolicy defines what happens to the event dispatched when the
+// buffer of the paused dispatcher is full
+type OverflowPolicy int
+
+const (
+	// OverflowDropNewest drops the event being dispatched
+	OverflowDropNewest OverflowPolicy = iota
+
+	// OverflowDropOldest drops the oldest buffered event to make room for
+	// the one being dispatched
+	OverflowDropOldest
+
+	// OverflowError drops the event being dispatched and makes TryDispatch
+	// return ErrBufferFull
+	OverflowError
+)
+
+// ErrBufferFull is returned by TryDispatch when the event has been dropped
+// because the buffer of the paused dispatcher is full
+var ErrBufferFull = errors.New("eventdispatcher: pause buffer full")
+
+// pausedEvent is the event buffered while the dispatcher is paused along
+// with the name it was dispatched under
+type pausedEvent struct {
+	n string
+	e Event
+}
+
+// WithPauseBuffer sets the max number of events buffered while the
+// dispatcher is paused and the policy applied when the buffer is full.
+// Panics if the size is not positive.
+func WithPauseBuffer(size int, p OverflowPolicy) Option {
+	if size <= 0 {
+		panic(fmt.Sprintf("eventdispatcher: pause buffer size must be positive, got %d", size))
+	}
+	return func(d *EventDispatcher) {
+		d.pauseBufferSize = size
+		d.overflow = p
+	}
+}
+
+// Pause makes the dispatcher buffer the dispatched events instead of
+// passing them to the listeners, until Resume is called
+func (d *EventDispatcher) Pause() {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	d.paused = true
+}
+
+// Resume dispatches all events buffered while the dispatcher was paused, in
+// the order they were dispatched, and makes it dispatch events immediately
+// again. The dispatcher stays paused until the buffer is flushed, so the
+// events dispatched meanwhile, e.g. by the listeners of the buffered ones,
+// are buffered and dispatched after them.
+func (d *EventDispatcher) Resume() {
+	for {
+		d.RWMutex.Lock()
+		buffer := d.pauseBuffer
+		d.pauseBuffer = nil
+		if len(buffer) == 0 {
+			d.paused = false
+			d.RWMutex.Unlock()
+			return
+		}
+		d.RWMutex.Unlock()
+
+		for _, pe := range buffer {
+			process(d, pe.n, pe.e, callListeners(d, nil))
+		}
+	}
+}
+
+// IsPaused informs whether the dispatcher is paused
+func (d *EventDispatcher) IsPaused() bool {
+	d.RWMutex.RLock()
+	defer d.RWMutex.RUnlock()
+
+	return d.paused
+}
+
+// buffer stores the event dispatched under name n if the dispatcher is
+// paused. Returns true if the event has been consumed by the buffer, either
+// stored or dropped, and ErrBufferFull if the overflow policy requires it.
+func buffer(d *EventDispatcher, n string, e Event) (bool, error) {
+	if d.IsPaused() == false {
+		return false, nil
+	}
+
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	if d.paused == false {
+		return false, nil
+	}
+	if len(d.pauseBuffer) >= d.pauseBufferSize {
+		switch d.overflow {
+		case OverflowDropOldest:
+			d.pauseBuffer = append(d.pauseBuffer[1:len(d.pauseBuffer):len(d.pauseBuffer)], pausedEvent{n, e})
+			return true, nil
+		case OverflowError:
+			return true, ErrBufferFull
+		default:
+			return true, nil
+		}
+	}
+	d.pauseBuffer = append(d.pauseBuffer, pausedEvent{n, e})
+
+	return true, nil
+}
@@ -0,0 +1,78 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPauseResume(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var names []string
+	d.On("event_1 event_2", func(e Event) {
+		names = append(names, e.Name())
+	})
+	d.Pause()
+	assert.True(d.IsPaused(), "The dispatcher should be paused!")
+	d.Dispatch(NewParamsEvent("event_2"))
+	d.Dispatch(NewParamsEvent("event_1"))
+	assert.Equal(0, len(names), "No events should be dispatched while paused!")
+
+	d.Resume()
+	assert.False(d.IsPaused(), "The dispatcher should be resumed!")
+	assert.Equal([]string{"event_2", "event_1"}, names, "Buffered events should be dispatched in order on resume!")
+}
+
+func TestPauseOverflow(t *testing.T) {
+	assert := assert.New(t)
+	for _, tc := range []struct {
+		p        OverflowPolicy
+		err      error
+		expected []string
+	}{
+		{OverflowDropNewest, nil, []string{"event_1", "event_2"}},
+		{OverflowDropOldest, nil, []string{"event_2", "event_3"}},
+		{OverflowError, ErrBufferFull, []string{"event_1", "event_2"}},
+	} {
+		d := NewDispatcher(WithPauseBuffer(2, tc.p))
+		var names []string
+		d.On("event_1 event_2 event_3", func(e Event) {
+			names = append(names, e.Name())
+		})
+		d.Pause()
+		d.Dispatch(NewParamsEvent("event_1"))
+		d.Dispatch(NewParamsEvent("event_2"))
+		_, err := d.TryDispatch(NewParamsEvent("event_3"))
+		assert.Equal(tc.err, err, "Invalid error returned for overflowing event!")
+		d.Resume()
+		assert.Equal(tc.expected, names, "Invalid events dispatched after overflow!")
+	}
+}
+
+func TestPauseBufferInvalidSize(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() {
+		WithPauseBuffer(0, OverflowDropOldest)
+	}, "The pause buffer without room should be rejected!")
+}
+
+func TestResumeKeepsOrder(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var names []string
+	d.On("event_1 event_2 event_3", func(e Event) {
+		names = append(names, e.Name())
+		if e.Name() == "event_1" {
+			d.Dispatch(NewParamsEvent("event_3"))
+		}
+	})
+	d.Pause()
+	d.Dispatch(NewParamsEvent("event_1"))
+	d.Dispatch(NewParamsEvent("event_2"))
+
+	d.Resume()
+	assert.False(d.IsPaused(), "The dispatcher should be resumed!")
+	assert.Equal([]string{"event_1", "event_2", "event_3"}, names, "Events dispatched while flushing should follow the buffered ones!")
+}
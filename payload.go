@@ -0,0 +1,66 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+)
+
+// PayloadEvent is the event carrying a single payload of type T, the typed
+// alternative to the params of the ParamsEvent
+type PayloadEvent[T any] struct {
+	name                 string
+	isPropagationStopped bool
+	payload              T
+}
+
+// Name returns the name of the event
+func (event *PayloadEvent[T]) Name() string {
+	return event.name
+}
+
+// IsPropagationStopped informs weather the event should
+// be further propagated or not
+func (event *PayloadEvent[T]) IsPropagationStopped() bool {
+	return event.isPropagationStopped
+}
+
+// StopPropagation sets a flag that make the event no longer
+// propagate.
+func (event *PayloadEvent[T]) StopPropagation() {
+	event.isPropagationStopped = true
+}
+
+// Payload returns the payload of the event
+func (event *PayloadEvent[T]) Payload() T {
+	return event.payload
+}
+
+// OnPayload registers the listener l receiving the payloads of type T of
+// the events with given name n. Events without the payload of type T are
+// reported with ReportError.
+func OnPayload[T any](d *EventDispatcher, n string, l func(T)) {
+	d.On(n, func(e Event) {
+		pe, ok := e.(*PayloadEvent[T])
+		if ok == false {
+			var p T
+			d.ReportError(e, fmt.Errorf("eventdispatcher: event %s does not carry the %T payload", e.Name(), p))
+			return
+		}
+		l(pe.payload)
+	})
+}
+
+// DispatchPayload dispatches the event with given name n and payload p.
+// Returns the dispatched event.
+func DispatchPayload[T any](d *EventDispatcher, n string, p T) *PayloadEvent[T] {
+	e := NewPayloadEvent(n, p)
+	d.Dispatch(e)
+
+	return e
+}
+
+// NewPayloadEvent creates the event with given name n and payload p
+func NewPayloadEvent[T any](n string, p T) *PayloadEvent[T] {
+	return &PayloadEvent[T]{name: n, payload: p}
+}
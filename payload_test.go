@@ -0,0 +1,44 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testOrder struct {
+	ID     int
+	Amount float64
+}
+
+func TestPayloadEvent(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []testOrder
+	OnPayload(d, "order.paid", func(o testOrder) {
+		got = append(got, o)
+	})
+	d.On("order.paid", func(e Event) {
+		e.StopPropagation()
+	})
+	e := DispatchPayload(d, "order.paid", testOrder{1, 9.99})
+	assert.Equal([]testOrder{{1, 9.99}}, got, "The listener should receive the payload!")
+	assert.Equal("order.paid", e.Name())
+	assert.Equal(testOrder{1, 9.99}, e.Payload())
+	assert.True(e.IsPropagationStopped(), "The propagation should be stopped!")
+}
+
+func TestPayloadEventMismatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var errs int
+	d.On(ErrorEventName, func(e Event) {
+		errs++
+	})
+	OnPayload(d, "order.paid", func(o testOrder) {
+		assert.Fail("The listener should not receive other payload!")
+	})
+	DispatchPayload(d, "order.paid", "not an order")
+	assert.Equal(1, errs, "The payload mismatch should be reported!")
+}
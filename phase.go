@@ -0,0 +1,27 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// Phase is the stage of the dispatch the listener is called in. Listeners
+// are called phase by phase, in the order of registration within a phase.
+type Phase int
+
+const (
+	// PhasePre listeners are called first, e.g. to validate the event
+	PhasePre Phase = iota - 1
+
+	// PhaseMain listeners are called after the pre ones. Listeners
+	// registered with On are called in this phase.
+	PhaseMain
+
+	// PhasePost listeners are called last, e.g. to clean up. They are
+	// called even if the propagation of the event has been stopped.
+	PhasePost
+)
+
+// OnPhase registers a listener for given event name called in the phase p
+func (d *EventDispatcher) OnPhase(n string, p Phase, l Listener) {
+	for _, name := range getNames(n) {
+		addEntry(d, name, listenerEntry{id: nextID(d), l: l, phase: p})
+	}
+}
@@ -0,0 +1,46 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOnPhase(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var calls []string
+	d.OnPhase(TestEventName, PhasePost, func(e Event) {
+		calls = append(calls, "post")
+	})
+	d.On(TestEventName, func(e Event) {
+		calls = append(calls, "main 1")
+	})
+	d.OnPhase(TestEventName, PhasePre, func(e Event) {
+		calls = append(calls, "pre")
+	})
+	d.OnPhase(TestEventName, PhaseMain, func(e Event) {
+		calls = append(calls, "main 2")
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"pre", "main 1", "main 2", "post"}, calls, "The listeners should be called phase by phase!")
+}
+
+func TestPhasePostAfterStopPropagation(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var calls []string
+	d.OnPhase(TestEventName, PhasePre, func(e Event) {
+		calls = append(calls, "pre")
+		e.StopPropagation()
+	})
+	d.On(TestEventName, func(e Event) {
+		calls = append(calls, "main")
+	})
+	d.OnPhase(TestEventName, PhasePost, func(e Event) {
+		calls = append(calls, "post")
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"pre", "post"}, calls, "The post listeners should always be called!")
+}
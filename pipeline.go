@@ -0,0 +1,141 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+)
+
+// Stage processes the event in the pipeline and returns the event fed into
+// the next stage. Returning a nil event drops it from the pipeline.
+type Stage func(e Event) (Event, error)
+
+// StageErrorHandler handles the error err of the stage processing the event
+// e. Returns the event fed into the next stage, nil to drop it, or the
+// error aborting the pipeline.
+type StageErrorHandler func(e Event, err error) (Event, error)
+
+// StageError is the error of the pipeline stage aborting the pipeline
+type StageError struct {
+
+	// Stage is the name of the failed stage
+	Stage string
+
+	// Err is the error of the stage
+	Err error
+}
+
+// Error returns the error message
+func (err *StageError) Error() string {
+	return fmt.Sprintf("eventdispatcher: pipeline stage %q failed: %v", err.Stage, err.Err)
+}
+
+// Unwrap returns the error of the stage
+func (err *StageError) Unwrap() error {
+	return err.Err
+}
+
+// SkipOnError is the StageErrorHandler dropping the event the stage failed
+// to process
+func SkipOnError(e Event, err error) (Event, error) {
+	return nil, nil
+}
+
+// ContinueOnError is the StageErrorHandler feeding the event the stage
+// failed to process into the next stage, as if the stage has not been there
+func ContinueOnError(e Event, err error) (Event, error) {
+	return e, nil
+}
+
+// pipelineStage is the stage of the pipeline along with its name and error
+// handler
+type pipelineStage struct {
+	name    string
+	s       Stage
+	onError StageErrorHandler
+}
+
+// Pipeline chains the stages, e.g. the dispatchers, feeding the output
+// event of one stage into the next, for the ETL-style event processing.
+// The stages abort the pipeline on error unless their error handler says
+// otherwise. Build it before running, it is not safe to add the stages
+// concurrently with Run.
+type Pipeline struct {
+	stages []pipelineStage
+}
+
+// Then appends the stage s with given name to the pipeline. Returns this
+// pipeline instance
+func (p *Pipeline) Then(name string, s Stage) *Pipeline {
+	p.stages = append(p.stages, pipelineStage{name: name, s: s})
+	return p
+}
+
+// ThenDispatch appends the stage dispatching the event with the dispatcher
+// d, see DispatchStage. Returns this pipeline instance
+func (p *Pipeline) ThenDispatch(name string, d *EventDispatcher) *Pipeline {
+	return p.Then(name, DispatchStage(d))
+}
+
+// OnError sets the error handler h of the stage appended last. Returns this
+// pipeline instance
+func (p *Pipeline) OnError(h StageErrorHandler) *Pipeline {
+	if len(p.stages) != 0 {
+		p.stages[len(p.stages)-1].onError = h
+	}
+	return p
+}
+
+// Run feeds the event e through all stages of the pipeline. Returns the
+// output event of the last stage, nil if any stage has dropped the event,
+// or the StageError aborting the pipeline.
+func (p *Pipeline) Run(e Event) (Event, error) {
+	for _, st := range p.stages {
+		out, err := st.s(e)
+		if err != nil && st.onError != nil {
+			out, err = st.onError(e, err)
+		}
+		if err != nil {
+			return nil, &StageError{st.name, err}
+		}
+		if out == nil {
+			return nil, nil
+		}
+		e = out
+	}
+
+	return e, nil
+}
+
+// Listener returns the listener running the pipeline for the handled
+// events and reporting the errors with ReportError of the dispatcher d
+func (p *Pipeline) Listener(d *EventDispatcher) Listener {
+	return func(e Event) {
+		if _, err := p.Run(e); err != nil {
+			d.ReportError(e, err)
+		}
+	}
+}
+
+// DispatchStage returns the stage dispatching the event with the dispatcher
+// d, so its listeners may enrich or transform it. Returns the error of
+// TryDispatch, e.g. the error the listeners failed the event with. The
+// event whose propagation has been stopped is dropped.
+func DispatchStage(d *EventDispatcher) Stage {
+	return func(e Event) (Event, error) {
+		out, err := d.TryDispatch(e)
+		if err != nil {
+			return nil, err
+		}
+		if out.IsPropagationStopped() {
+			return nil, nil
+		}
+		return out, nil
+	}
+}
+
+// NewPipeline creates the empty pipeline, passing the events through
+// unchanged
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
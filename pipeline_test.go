@@ -0,0 +1,71 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	assert := assert.New(t)
+	enrich := NewDispatcher()
+	enrich.On(TestEventName, func(e Event) {
+		e.(*ParamsEvent).SetParam("enriched", true)
+	})
+	p := NewPipeline().
+		ThenDispatch("enrich", enrich).
+		Then("rename", func(e Event) (Event, error) {
+			return e.(*ParamsEvent).WithName("loaded"), nil
+		})
+
+	out, err := p.Run(NewParamsEvent(TestEventName))
+	assert.Nil(err, "No error should be returned!")
+	assert.Equal("loaded", out.Name(), "The output event of the last stage should be returned!")
+	v, _ := out.(*ParamsEvent).GetParam("enriched")
+	assert.Equal(true, v, "The output of the stage should be fed into the next one!")
+
+	enrich.On(TestEventName, func(e Event) {
+		e.StopPropagation()
+	})
+	out, err = p.Run(NewParamsEvent(TestEventName))
+	assert.Nil(err, "No error should be returned!")
+	assert.Nil(out, "The stopped event should be dropped!")
+}
+
+func TestPipelineErrors(t *testing.T) {
+	assert := assert.New(t)
+	failure := errors.New("failed")
+	fail := func(e Event) (Event, error) {
+		return nil, failure
+	}
+	var c int
+	count := func(e Event) (Event, error) {
+		c++
+		return e, nil
+	}
+
+	_, err := NewPipeline().Then("fail", fail).Then("count", count).Run(NewParamsEvent(TestEventName))
+	var se *StageError
+	assert.True(errors.As(err, &se), "The stage error should be returned!")
+	assert.Equal("fail", se.Stage, "The failed stage should be named!")
+	assert.ErrorIs(err, failure, "The stage error should wrap the error!")
+	assert.Equal(0, c, "The pipeline should be aborted!")
+
+	out, err := NewPipeline().Then("fail", fail).OnError(SkipOnError).Then("count", count).Run(NewParamsEvent(TestEventName))
+	assert.Nil(err, "No error should be returned!")
+	assert.Nil(out, "The event should be dropped!")
+	assert.Equal(0, c, "The dropped event should not reach the next stage!")
+
+	e := NewParamsEvent(TestEventName)
+	out, err = NewPipeline().Then("fail", fail).OnError(ContinueOnError).Then("count", count).Run(e)
+	assert.Nil(err, "No error should be returned!")
+	assert.Equal(e, out, "The input event should be fed into the next stage!")
+	assert.Equal(1, c, "The pipeline should continue!")
+
+	d := NewDispatcher()
+	d.On(TestEventName, NewPipeline().Then("fail", fail).Listener(d))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.ErrorIs(<-d.Errors(), failure, "The error should be reported!")
+}
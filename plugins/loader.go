@@ -0,0 +1,166 @@
+// Package plugins loads the Go plugins registering their listeners in the
+// event dispatcher at runtime. The plugin is a package built with
+// -buildmode=plugin exporting the function:
+//
+//	func RegisterListeners(d eventdispatcher.Dispatcher)
+//
+// Every plugin registers its listeners within its own listener group, so
+// they are removed when the plugin is unloaded. The Go runtime can not
+// unload the plugin code itself.
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"sync"
+)
+
+const (
+	// RegisterSymbol is the name of the function exported by the plugins
+	RegisterSymbol = "RegisterListeners"
+
+	// LoadedEventName is the name of the event dispatched after the
+	// plugin has been loaded
+	LoadedEventName = "plugin.loaded"
+
+	// UnloadedEventName is the name of the event dispatched after the
+	// plugin has been unloaded
+	UnloadedEventName = "plugin.unloaded"
+
+	// ParamPath is the param of the lifecycle events holding the plugin
+	// path
+	ParamPath = "path"
+
+	// GroupPrefix prefixes the names of the listener groups of the plugins
+	GroupPrefix = "plugin:"
+)
+
+// ErrAlreadyLoaded is returned when the plugin is loaded twice
+var ErrAlreadyLoaded = errors.New("plugins: plugin already loaded")
+
+// ErrNotLoaded is returned when unloading the plugin not loaded
+var ErrNotLoaded = errors.New("plugins: plugin not loaded")
+
+// symbolLookup finds the exported symbol of the opened plugin
+type symbolLookup interface {
+	Lookup(name string) (plugin.Symbol, error)
+}
+
+// opener opens the plugin at given path
+type opener func(path string) (symbolLookup, error)
+
+// Loader loads the plugins into the dispatcher
+type Loader struct {
+	sync.Mutex
+	d      *ed.EventDispatcher
+	open   opener
+	loaded map[string]*ed.ListenerGroup
+}
+
+// Load opens the plugin at given path and calls its RegisterListeners
+// function with the listener group of the plugin. Dispatches the
+// LoadedEventName event once done.
+func (ld *Loader) Load(path string) error {
+	ld.Lock()
+	if _, ok := ld.loaded[path]; ok {
+		ld.Unlock()
+		return fmt.Errorf("%w: %s", ErrAlreadyLoaded, path)
+	}
+	ld.Unlock()
+
+	p, err := ld.open(path)
+	if err != nil {
+		return fmt.Errorf("plugins: %s: %w", path, err)
+	}
+	sym, err := p.Lookup(RegisterSymbol)
+	if err != nil {
+		return fmt.Errorf("plugins: %s: %w", path, err)
+	}
+	register, ok := sym.(func(ed.Dispatcher))
+	if ok == false {
+		return fmt.Errorf("plugins: %s: %s has type %T, want func(eventdispatcher.Dispatcher)", path, RegisterSymbol, sym)
+	}
+
+	ld.Lock()
+	if _, ok := ld.loaded[path]; ok {
+		ld.Unlock()
+		return fmt.Errorf("%w: %s", ErrAlreadyLoaded, path)
+	}
+	g := ld.d.Group(GroupPrefix + path)
+	ld.loaded[path] = g
+	ld.Unlock()
+
+	register(g)
+	ld.d.Dispatch(ed.NewParamsEvent(LoadedEventName).SetParam(ParamPath, path))
+
+	return nil
+}
+
+// LoadDir loads all plugins (*.so files) found in the directory dir, in the
+// order of their names. Returns the errors of all plugins that failed.
+func (ld *Loader) LoadDir(dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("plugins: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("plugins: %w", err)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		if err := ld.Load(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Unload removes all listeners registered by the plugin at given path and
+// dispatches the UnloadedEventName event
+func (ld *Loader) Unload(path string) error {
+	ld.Lock()
+	g, ok := ld.loaded[path]
+	delete(ld.loaded, path)
+	ld.Unlock()
+	if ok == false {
+		return fmt.Errorf("%w: %s", ErrNotLoaded, path)
+	}
+
+	g.RemoveAll()
+	ld.d.Dispatch(ed.NewParamsEvent(UnloadedEventName).SetParam(ParamPath, path))
+
+	return nil
+}
+
+// Loaded returns the paths of the loaded plugins, sorted
+func (ld *Loader) Loaded() []string {
+	ld.Lock()
+	defer ld.Unlock()
+
+	paths := make([]string, 0, len(ld.loaded))
+	for path := range ld.loaded {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// openPlugin opens the Go plugin at given path
+func openPlugin(path string) (symbolLookup, error) {
+	return plugin.Open(path)
+}
+
+// NewLoader creates the loader of the plugins registering their listeners
+// in the dispatcher d
+func NewLoader(d *ed.EventDispatcher) *Loader {
+	return &Loader{d: d, open: openPlugin, loaded: make(map[string]*ed.ListenerGroup)}
+}
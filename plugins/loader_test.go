@@ -0,0 +1,74 @@
+package plugins
+
+import (
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"plugin"
+	"testing"
+)
+
+type fakePlugin map[string]plugin.Symbol
+
+func (p fakePlugin) Lookup(name string) (plugin.Symbol, error) {
+	sym, ok := p[name]
+	if ok == false {
+		return nil, errors.New("symbol not found")
+	}
+	return sym, nil
+}
+
+func newTestLoader(d *ed.EventDispatcher, plugins map[string]fakePlugin) *Loader {
+	ld := NewLoader(d)
+	ld.open = func(path string) (symbolLookup, error) {
+		p, ok := plugins[path]
+		if ok == false {
+			return nil, errors.New("no such file")
+		}
+		return p, nil
+	}
+	return ld
+}
+
+func TestLoadUnload(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	var c int
+	ld := newTestLoader(d, map[string]fakePlugin{
+		"greeter.so": {RegisterSymbol: func(pd ed.Dispatcher) {
+			pd.On("user.created", func(e ed.Event) {
+				c++
+			})
+		}},
+	})
+	var lifecycle []string
+	d.On(LoadedEventName+" "+UnloadedEventName, func(e ed.Event) {
+		path, _ := e.(*ed.ParamsEvent).GetParam(ParamPath)
+		lifecycle = append(lifecycle, e.Name()+" "+path.(string))
+	})
+
+	assert.Nil(ld.Load("greeter.so"))
+	assert.True(errors.Is(ld.Load("greeter.so"), ErrAlreadyLoaded), "The plugin should be loaded once!")
+	assert.Equal([]string{"greeter.so"}, ld.Loaded())
+	d.Dispatch(ed.NewParamsEvent("user.created"))
+	assert.Equal(1, c, "The plugin listeners should be registered!")
+
+	assert.Nil(ld.Unload("greeter.so"))
+	d.Dispatch(ed.NewParamsEvent("user.created"))
+	assert.Equal(1, c, "The plugin listeners should be removed!")
+	assert.True(errors.Is(ld.Unload("greeter.so"), ErrNotLoaded))
+	assert.Equal([]string{"plugin.loaded greeter.so", "plugin.unloaded greeter.so"}, lifecycle,
+		"The lifecycle events should be dispatched!")
+}
+
+func TestLoadInvalid(t *testing.T) {
+	assert := assert.New(t)
+	ld := newTestLoader(ed.NewDispatcher(), map[string]fakePlugin{
+		"empty.so":   {},
+		"invalid.so": {RegisterSymbol: func() {}},
+	})
+	assert.NotNil(ld.Load("missing.so"), "Missing plugins should fail!")
+	assert.NotNil(ld.Load("empty.so"), "Plugins without the symbol should fail!")
+	assert.NotNil(ld.Load("invalid.so"), "Plugins with invalid symbol type should fail!")
+	assert.Empty(ld.Loaded())
+}
@@ -0,0 +1,32 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// Pool of reusable ParamsEvent instances
+var eventsPool = sync.Pool{
+	New: func() interface{} {
+		return NewParamsEvent("")
+	},
+}
+
+// AcquireEvent returns a ParamsEvent with given name n taken from the pool.
+// Call ReleaseEvent once the event is no longer used to avoid allocating a
+// new event on every dispatch.
+func AcquireEvent(n string) *ParamsEvent {
+	e := eventsPool.Get().(*ParamsEvent)
+	e.name = n
+
+	return e
+}
+
+// ReleaseEvent resets the event and puts it back to the pool. The event
+// must not be used after releasing it, neither by the emitter nor by any
+// listener holding a reference to it.
+func ReleaseEvent(e *ParamsEvent) {
+	e.Reset()
+	eventsPool.Put(e)
+}
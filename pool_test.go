@@ -0,0 +1,44 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAcquireReleaseEvent(t *testing.T) {
+	assert := assert.New(t)
+	e := AcquireEvent(TestEventName)
+	assert.Equal(TestEventName, e.Name(), "The acquired event should have the given name!")
+	e.SetParam("foo", "bar")
+	e.StopPropagation()
+	ReleaseEvent(e)
+
+	assert.Equal("", e.Name(), "The released event name should be reset!")
+	assert.False(e.HasParam("foo"), "The released event params should be reset!")
+	assert.False(e.IsPropagationStopped(), "The released event propagation flag should be reset!")
+}
+
+func BenchmarkDispatchNewEvent(b *testing.B) {
+	d := NewDispatcher()
+	d.On(TestEventName, func(e Event) {})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e := NewParamsEvent(TestEventName)
+		e.SetParam("i", i)
+		d.Dispatch(e)
+	}
+}
+
+func BenchmarkDispatchPooledEvent(b *testing.B) {
+	d := NewDispatcher()
+	d.On(TestEventName, func(e Event) {})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e := AcquireEvent(TestEventName)
+		e.SetParam("i", i)
+		d.Dispatch(e)
+		ReleaseEvent(e)
+	}
+}
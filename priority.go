@@ -0,0 +1,107 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// Priority is the urgency of the event dispatched asynchronously. When the
+// async workers are busy, the queued events of the higher priority are
+// dispatched first.
+type Priority int
+
+const (
+	// PriorityLow is the priority of the routine events that may wait
+	PriorityLow Priority = iota - 1
+
+	// PriorityNormal is the default priority
+	PriorityNormal
+
+	// PriorityHigh is the priority of the urgent events, e.g. shutdown or
+	// security ones, preempting the backlog of the others
+	PriorityHigh
+)
+
+// Prioritized is the event carrying its priority
+type Prioritized interface {
+
+	// Priority returns the priority of the event
+	Priority() Priority
+}
+
+// asyncQueue holds the asynchronous work waiting for a free worker, in
+// separate lanes per priority
+type asyncQueue struct {
+	sync.Mutex
+	workers int
+	running int
+	lanes   [PriorityHigh - PriorityLow + 1][]func()
+}
+
+// DispatchAsyncPriority dispatches the event in the background with given
+// priority, regardless of the priority the event carries
+func (d *EventDispatcher) DispatchAsyncPriority(e Event, p Priority) {
+	asyncPriority(d, p, func() {
+		d.Dispatch(e)
+	})
+}
+
+// priorityOf returns the priority of the event, PriorityNormal unless it is
+// Prioritized
+func priorityOf(e Event) Priority {
+	if pe, ok := e.(Prioritized); ok {
+		return pe.Priority()
+	}
+
+	return PriorityNormal
+}
+
+// enqueue runs f on a free worker or queues it in the lane of priority p.
+// Returns false if f should be run on a new worker.
+func enqueue(q *asyncQueue, p Priority, f func()) bool {
+	if p < PriorityLow {
+		p = PriorityLow
+	} else if p > PriorityHigh {
+		p = PriorityHigh
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	if q.running < q.workers {
+		q.running++
+		return false
+	}
+	q.lanes[p-PriorityLow] = append(q.lanes[p-PriorityLow], f)
+
+	return true
+}
+
+// dequeue returns the queued work of the highest priority, or nil and
+// releases the worker if there is none
+func dequeue(q *asyncQueue) func() {
+	q.Lock()
+	defer q.Unlock()
+
+	for i := len(q.lanes) - 1; i >= 0; i-- {
+		if len(q.lanes[i]) != 0 {
+			f := q.lanes[i][0]
+			q.lanes[i][0] = nil
+			q.lanes[i] = q.lanes[i][1:]
+			return f
+		}
+	}
+	q.running--
+
+	return nil
+}
+
+// work runs f and then the queued work until the queue is empty
+func work(d *EventDispatcher, f func()) {
+	for f != nil {
+		f()
+		done(d)
+		f = dequeue(d.queue)
+	}
+}
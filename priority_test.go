@@ -0,0 +1,32 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDispatchAsyncPriority(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithAsync(1))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	d.On("block", func(e Event) {
+		close(started)
+		<-release
+	})
+	var names []string
+	d.On("low normal high urgent", func(e Event) {
+		names = append(names, e.Name())
+	})
+	d.DispatchAsync(NewParamsEvent("block"))
+	<-started
+	d.DispatchAsync(NewParamsEvent("low").SetPriority(PriorityLow))
+	d.DispatchAsync(NewParamsEvent("normal"))
+	d.DispatchAsync(NewParamsEvent("high").SetPriority(PriorityHigh))
+	d.DispatchAsyncPriority(NewParamsEvent("urgent"), PriorityHigh)
+	close(release)
+	d.Drain()
+	assert.Equal([]string{"high", "urgent", "normal", "low"}, names, "The queued events should be dispatched by priority!")
+}
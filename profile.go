@@ -0,0 +1,59 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"reflect"
+	"runtime/pprof"
+	"sync"
+)
+
+const (
+	// LabelEvent is the pprof label holding the name the event has been
+	// dispatched under
+	LabelEvent = "event"
+
+	// LabelListener is the pprof label holding the ListenerName of the
+	// called listener
+	LabelListener = "listener"
+)
+
+// do runs the function with the pprof labels set, replaced in tests
+var do = pprof.Do
+
+// listenerNames caches the names of the listener functions by their code
+// pointers, as resolving them on each call is too slow
+var listenerNames sync.Map
+
+// WithProfilerLabels makes the dispatcher set the LabelEvent and the
+// LabelListener pprof labels while calling the listeners, so the CPU
+// profiles attribute the time to the listeners, e.g. with
+// "go tool pprof -tagfocus listener=main.sendReceipt". The middlewares run
+// outside the labels.
+func WithProfilerLabels() Option {
+	return func(d *EventDispatcher) {
+		d.labels = true
+	}
+}
+
+// callLabeled calls the listener with the pprof labels of the call set
+func callLabeled(d *EventDispatcher, c ListenerCall, l Listener, e Event) {
+	labels := pprof.Labels(LabelEvent, c.Name, LabelListener, cachedListenerName(l))
+	do(context.Background(), labels, func(context.Context) {
+		call(d, l, e)
+	})
+}
+
+// cachedListenerName returns the ListenerName of l, resolved once per
+// listener function
+func cachedListenerName(l Listener) string {
+	pc := reflect.ValueOf(l).Pointer()
+	if name, ok := listenerNames.Load(pc); ok {
+		return name.(string)
+	}
+	name := ListenerName(l)
+	listenerNames.Store(pc, name)
+
+	return name
+}
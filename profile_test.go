@@ -0,0 +1,63 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"runtime/pprof"
+	"testing"
+)
+
+func profileTestListener(e Event) {}
+
+func TestWithProfilerLabels(t *testing.T) {
+	assert := assert.New(t)
+	var labels []map[string]string
+	do = func(ctx context.Context, ls pprof.LabelSet, f func(context.Context)) {
+		pprof.Do(ctx, ls, func(ctx context.Context) {
+			l := make(map[string]string)
+			pprof.ForLabels(ctx, func(k, v string) bool {
+				l[k] = v
+				return true
+			})
+			labels = append(labels, l)
+			f(ctx)
+		})
+	}
+	defer func() {
+		do = pprof.Do
+	}()
+
+	d := NewDispatcher(WithProfilerLabels(), WithMiddleware(func(c ListenerCall, e Event, next Listener) {
+		next(e)
+	}))
+	var called bool
+	d.On(TestEventName, profileTestListener)
+	d.On(TestEventName, func(e Event) {
+		called = true
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.True(called, "The listeners should be called!")
+	assert.Len(labels, 2, "Each listener call should be labeled!")
+	assert.Equal(map[string]string{
+		LabelEvent:    TestEventName,
+		LabelListener: "github.com/gacek85/eventdispatcher.profileTestListener",
+	}, labels[0], "Invalid labels of the listener call!")
+}
+
+func TestWithoutProfilerLabels(t *testing.T) {
+	var labeled bool
+	do = func(ctx context.Context, ls pprof.LabelSet, f func(context.Context)) {
+		labeled = true
+		f(ctx)
+	}
+	defer func() {
+		do = pprof.Do
+	}()
+
+	d := NewDispatcher()
+	d.On(TestEventName, profileTestListener)
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.False(t, labeled, "The listener calls should not be labeled by default!")
+}
@@ -0,0 +1,123 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitPolicy defines what happens to the event dispatched when the
+// rate limit for its name has been exceeded
+type RateLimitPolicy int
+
+const (
+	// RateLimitDrop silently drops the event
+	RateLimitDrop RateLimitPolicy = iota
+
+	// RateLimitQueue blocks the dispatching goroutine until the event may
+	// be dispatched
+	RateLimitQueue
+
+	// RateLimitError drops the event and makes TryDispatch return
+	// ErrRateLimited
+	RateLimitError
+)
+
+// ErrRateLimited is returned by TryDispatch when the event has been
+// dropped by the rate limiter
+var ErrRateLimited = errors.New("eventdispatcher: rate limit exceeded")
+
+// rateLimiter is a token bucket refilled with rate tokens per second and
+// holding at most burst tokens
+type rateLimiter struct {
+	sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	policy RateLimitPolicy
+}
+
+// WithRateLimit limits dispatching of the events with given name n (may
+// contain many space separated names) to rate events per second, allowing
+// bursts of burst events. The policy p defines what happens to the events
+// exceeding the limit. Panics if the rate is not positive.
+func WithRateLimit(n string, rate float64, burst int, p RateLimitPolicy) Option {
+	if rate <= 0 {
+		panic(fmt.Sprintf("eventdispatcher: rate limit of %s must be positive, got %v", n, rate))
+	}
+	return func(d *EventDispatcher) {
+		for _, name := range getNames(n) {
+			d.rateLimiters[name] = newRateLimiter(rate, burst, p)
+		}
+	}
+}
+
+// limit applies the rate limiter configured for given event name n.
+// Returns false if the event must not be dispatched, along with
+// ErrRateLimited unless the policy drops the events silently.
+func limit(d *EventDispatcher, n string) (bool, error) {
+	r, ok := d.rateLimiters[n]
+	if ok == false {
+		return true, nil
+	}
+
+	return r.take(d.clock)
+}
+
+// take consumes a token from the bucket. Depending on the policy waits for
+// the token, returns false or returns false and ErrRateLimited if none is
+// available.
+func (r *rateLimiter) take(c Clock) (bool, error) {
+	r.Lock()
+	refill(r, c.Now())
+	if r.tokens >= 1 {
+		r.tokens--
+		r.Unlock()
+		return true, nil
+	}
+	switch r.policy {
+	case RateLimitDrop:
+		r.Unlock()
+		return false, nil
+	case RateLimitError:
+		r.Unlock()
+		return false, ErrRateLimited
+	}
+
+	// Reserve the token in advance so the queued dispatches keep their order
+	r.tokens--
+	wait := time.Duration(-r.tokens / r.rate * float64(time.Second))
+	r.Unlock()
+	c.Sleep(wait)
+
+	return true, nil
+}
+
+// refill adds the tokens accumulated since the last refill. The bucket is
+// full on the first use.
+func refill(r *rateLimiter, now time.Time) {
+	if r.last.IsZero() {
+		r.last = now
+	}
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+}
+
+func newRateLimiter(rate float64, burst int, p RateLimitPolicy) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		policy: p,
+	}
+}
@@ -0,0 +1,61 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRateLimitDrop(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRateLimit(TestEventName, 1, 2, RateLimitDrop))
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	for i := 0; i < 5; i++ {
+		_, err := d.TryDispatch(NewParamsEvent(TestEventName))
+		assert.Nil(err, "The dropped events should not return an error!")
+	}
+	assert.Equal(2, c, "Only the burst of events should be dispatched!")
+
+	d.On("other_event", func(e Event) {
+		c++
+	})
+	d.Dispatch(NewParamsEvent("other_event"))
+	assert.Equal(3, c, "Events with other names should not be limited!")
+}
+
+func TestRateLimitError(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRateLimit(TestEventName, 1, 1, RateLimitError))
+	_, err := d.TryDispatch(NewParamsEvent(TestEventName))
+	assert.Nil(err, "The first event should be dispatched!")
+	_, err = d.TryDispatch(NewParamsEvent(TestEventName))
+	assert.Equal(ErrRateLimited, err, "The second event should be rate limited!")
+}
+
+func TestRateLimitQueue(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRateLimit(TestEventName, 100, 1, RateLimitQueue))
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := d.TryDispatch(NewParamsEvent(TestEventName))
+		assert.Nil(err, "Queued events should not return an error!")
+	}
+	assert.Equal(3, c, "All queued events should be dispatched!")
+	assert.True(time.Since(start) >= 15*time.Millisecond, "Queued events should wait for the tokens!")
+}
+
+func TestRateLimitInvalidRate(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() {
+		WithRateLimit(TestEventName, 0, 1, RateLimitQueue)
+	}, "The rate should be positive!")
+}
@@ -0,0 +1,132 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces the values of the sensitive params in the logs,
+// the dispatch history and the redacted JSON
+const RedactedValue = "[REDACTED]"
+
+// WithSensitiveParams marks the param keys, e.g. "password" or "token", as
+// sensitive, so their values, also the ones nested in the maps of the
+// params, are masked by the logger and the dispatch history of the
+// dispatcher and by its RedactedJSON and LogValue. Keys are matched case
+// insensitively.
+func WithSensitiveParams(keys ...string) Option {
+	return func(d *EventDispatcher) {
+		for _, k := range keys {
+			d.sensitive[strings.ToLower(k)] = true
+		}
+	}
+}
+
+// SensitiveParams returns the sorted keys marked as sensitive
+func (d *EventDispatcher) SensitiveParams() []string {
+	keys := make([]string, 0, len(d.sensitive))
+	for k := range d.sensitive {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// IsSensitiveParam informs whether the param key k is marked as sensitive
+func (d *EventDispatcher) IsSensitiveParam(k string) bool {
+	return d.sensitive[strings.ToLower(k)]
+}
+
+// RedactParams returns the copy of the params with the values of the
+// sensitive ones replaced by RedactedValue, also in the nested maps and
+// slices, which are copied as map[string]interface{} and []interface{}
+func (d *EventDispatcher) RedactParams(params map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		redacted[k] = redactParam(d, k, v)
+	}
+
+	return redacted
+}
+
+// redactParam returns the value v of the param or the nested value with
+// the key k, masked if k is sensitive
+func redactParam(d *EventDispatcher, k string, v interface{}) interface{} {
+	if d.IsSensitiveParam(k) {
+		return RedactedValue
+	}
+	if len(d.sensitive) == 0 || v == nil {
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		m := make(map[string]interface{}, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			mk := it.Key().String()
+			m[mk] = redactParam(d, mk, it.Value().Interface())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		l := make([]interface{}, rv.Len())
+		for i := range l {
+			l[i] = redactParam(d, "", rv.Index(i).Interface())
+		}
+		return l
+	}
+
+	return v
+}
+
+// RedactedJSON encodes the event like its MarshalJSON does, with the
+// sensitive params masked. MarshalJSON itself stays lossless, as it is used
+// to persist and replay the events.
+func (d *EventDispatcher) RedactedJSON(event *ParamsEvent) ([]byte, error) {
+	return json.Marshal(paramsEventJSON{
+		event.name, event.version, event.id, event.correlationID, event.causationID, event.priority, d.RedactParams(event.params),
+	})
+}
+
+// LogValue represents the event in log/slog records as its name and the
+// params, with the sensitive ones masked
+func (d *EventDispatcher) LogValue(event *ParamsEvent) slog.Value {
+	params := d.RedactParams(event.params)
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, params[k]))
+	}
+
+	return slog.GroupValue(slog.String("name", event.name), slog.Any("params", slog.GroupValue(attrs...)))
+}
+
+// LogValue represents the event in log/slog records as its name and the
+// keys of its params. The values are left out, as the event does not know
+// which of them are sensitive, use the LogValue of the dispatcher to log
+// them masked.
+func (event *ParamsEvent) LogValue() slog.Value {
+	keys := make([]string, 0, len(event.params))
+	for k := range event.params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return slog.GroupValue(slog.String("name", event.name), slog.Any("params", keys))
+}
@@ -0,0 +1,56 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSensitiveParams("password", "Token"))
+	assert.True(d.IsSensitiveParam("PASSWORD"), "Keys should be matched case insensitively!")
+	assert.Equal([]string{"password", "token"}, d.SensitiveParams(), "Invalid sensitive params!")
+	assert.False(NewDispatcher().IsSensitiveParam("password"), "The keys should be sensitive for the configured dispatcher only!")
+
+	e := NewParamsEvent(TestEventName).SetParam("password", "secret").SetParam("user", "john")
+	assert.Equal("password=[REDACTED] user=john", summarizeParams(d, e), "The summary should mask the sensitive params!")
+	data, err := d.RedactedJSON(e)
+	assert.Nil(err, "Encoding should succeed!")
+	assert.JSONEq(`{"name":"test_event","version":1,"params":{"password":"[REDACTED]","user":"john"}}`, string(data), "The JSON should mask the sensitive params!")
+	v, _ := e.GetParam("password")
+	assert.Equal("secret", v, "The event itself should not be modified!")
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("event", "e", d.LogValue(e), "raw", e)
+	assert.Contains(buf.String(), "e.params.password=[REDACTED]", "Logged events should mask the sensitive params!")
+	assert.Contains(buf.String(), "raw.params=\"[password user]\"", "The event should log the keys of its params only!")
+	assert.NotContains(buf.String(), "secret", "Logged events should not leak the sensitive params!")
+}
+
+func TestRedactNestedParams(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSensitiveParams("token"))
+	params := map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":     "john",
+			"sessions": []interface{}{map[string]string{"token": "secret"}},
+		},
+	}
+	assert.Equal(map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":     "john",
+			"sessions": []interface{}{map[string]interface{}{"token": RedactedValue}},
+		},
+	}, d.RedactParams(params), "The nested sensitive params should be masked!")
+	assert.Equal("secret", params["user"].(map[string]interface{})["sessions"].([]interface{})[0].(map[string]string)["token"], "The params should not be modified!")
+}
+
+func TestHistoryRedaction(t *testing.T) {
+	d := NewDispatcher(WithHistory(1), WithSensitiveParams("api_key"))
+	d.Dispatch(NewParamsEvent(TestEventName).SetParam("api_key", "secret"))
+	assert.Equal(t, "api_key=[REDACTED]", d.History()[0].Params, "The history should mask the sensitive params!")
+}
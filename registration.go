@@ -0,0 +1,138 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrEmptyName is returned when the listener is registered for no
+	// event name
+	ErrEmptyName = errors.New("eventdispatcher: empty event name")
+
+	// ErrNilListener is returned when the nil listener is registered
+	ErrNilListener = errors.New("eventdispatcher: nil listener")
+
+	// ErrInvalidPattern is returned when the listener is registered for the
+	// malformed wildcard pattern, e.g. "user*" instead of "user.*"
+	ErrInvalidPattern = errors.New("eventdispatcher: invalid pattern")
+
+	// ErrSealed is returned or reported when the listeners are registered
+	// or removed after the dispatcher has been sealed
+	ErrSealed = errors.New("eventdispatcher: dispatcher sealed")
+)
+
+// RegistrationError is returned when the listener cannot be registered
+type RegistrationError struct {
+
+	// Name is the event name the listener has been registered for
+	Name string
+
+	// Err is the reason, one of ErrEmptyName, ErrNilListener,
+	// ErrInvalidPattern and ErrSealed
+	Err error
+}
+
+// Error returns the error message
+func (err *RegistrationError) Error() string {
+	return fmt.Sprintf("%v: %q", err.Err, err.Name)
+}
+
+// Unwrap returns the reason
+func (err *RegistrationError) Unwrap() error {
+	return err.Err
+}
+
+// OnE registers a listener for given event name like On does, but returns
+// the RegistrationError instead of reporting it, registering the listener
+// for none of the names
+func (d *EventDispatcher) OnE(n string, l Listener) error {
+	names, err := validateRegistration(d, n, l)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		on(d, name, l)
+	}
+
+	return nil
+}
+
+// OnceE registers a listener to be executed only once like Once does, but
+// returns the RegistrationError instead of reporting it, registering the
+// listener for none of the names
+func (d *EventDispatcher) OnceE(n string, l Listener) error {
+	names, err := validateRegistration(d, n, l)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		id := nextID(d)
+		nl := executeRemove(d, name, id, l) // Create a new listener that removes given listener after calling it
+		addEntry(d, name, listenerEntry{id: id, l: nl, once: l})
+	}
+
+	return nil
+}
+
+// OnAnyE registers the listener of all events like OnAny does, but returns
+// the RegistrationError instead of reporting it if the dispatcher is sealed
+func (d *EventDispatcher) OnAnyE(l Listener) (*Subscription, error) {
+	if d.sealed.Load() {
+		return nil, &RegistrationError{WildcardSegments, ErrSealed}
+	}
+
+	return d.OnAny(l), nil
+}
+
+// OffE removes the listener for given event name like Off does, but returns
+// the RegistrationError instead of reporting it if the dispatcher is sealed
+func (d *EventDispatcher) OffE(n string, l Listener) (int, error) {
+	if d.sealed.Load() {
+		return 0, &RegistrationError{n, ErrSealed}
+	}
+
+	return d.Off(n, l), nil
+}
+
+// OffAllE removes all listeners for given name like OffAll does, but
+// returns the RegistrationError instead of reporting it if the dispatcher
+// is sealed
+func (d *EventDispatcher) OffAllE(n string) error {
+	if d.sealed.Load() {
+		return &RegistrationError{n, ErrSealed}
+	}
+	d.OffAll(n)
+
+	return nil
+}
+
+// validateRegistration returns the names of n the listener l is registered
+// for, or the RegistrationError if it must not be registered
+func validateRegistration(d *EventDispatcher, n string, l Listener) ([]string, error) {
+	if d.sealed.Load() {
+		return nil, &RegistrationError{n, ErrSealed}
+	}
+	names := getNames(n)
+	if len(names) == 0 {
+		return nil, &RegistrationError{n, ErrEmptyName}
+	}
+	if l == nil {
+		return nil, &RegistrationError{n, ErrNilListener}
+	}
+	if d.wildcards == false {
+		return names, nil
+	}
+	for _, name := range names {
+		for _, segment := range strings.Split(name, NamespaceSeparator) {
+			if isPattern(segment) && segment != WildcardSegment && segment != WildcardSegments {
+				return nil, &RegistrationError{name, ErrInvalidPattern}
+			}
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,41 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOnE(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	l := func(e Event) {}
+	assert.Nil(d.OnE("a.* b.**", l), "The valid listener should be registered!")
+	assert.ErrorIs(d.OnE(" ", l), ErrEmptyName, "The empty name should be rejected!")
+	assert.ErrorIs(d.OnE("c", nil), ErrNilListener, "The nil listener should be rejected!")
+	err := d.OnE("d user*.created", l)
+	assert.ErrorIs(err, ErrInvalidPattern, "The malformed pattern should be rejected!")
+	var re *RegistrationError
+	assert.ErrorAs(err, &re)
+	assert.Equal("user*.created", re.Name, "The error should name the invalid name!")
+	assert.False(d.HasListeners("d"), "The listener should be registered for none of the names!")
+	assert.ErrorIs(d.OnceE("c", nil), ErrNilListener, "The nil once listener should be rejected!")
+}
+
+func TestOnReportsRegistrationError(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	d.On(TestEventName, nil)
+	d.Once(TestEventName, nil)
+	assert.False(d.HasListeners(TestEventName), "The nil listener should not be registered!")
+	assert.Len(errs, 2, "The registration errors should be reported!")
+	assert.ErrorIs(errs[0], ErrNilListener)
+	assert.NotPanics(func() {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	}, "Dispatching should not call the nil listener!")
+}
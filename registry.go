@@ -0,0 +1,93 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultRegistry is the registry used by GetDispatcher
+var DefaultRegistry = NewRegistry()
+
+// Registry holds the dispatcher instances by key. It is safe for the
+// concurrent use.
+type Registry struct {
+	sync.RWMutex
+	dispatchers map[string]*EventDispatcher
+	opts        []Option
+}
+
+// Get returns the dispatcher for given key, creating it with the options
+// of the registry if there is none
+func (r *Registry) Get(key string) *EventDispatcher {
+	r.RLock()
+	d, ok := r.dispatchers[key]
+	r.RUnlock()
+	if ok {
+		return d
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if d, ok := r.dispatchers[key]; ok {
+		return d
+	}
+	d = NewDispatcher(r.opts...)
+	r.dispatchers[key] = d
+
+	return d
+}
+
+// Lookup returns the dispatcher for given key and false if there is none
+func (r *Registry) Lookup(key string) (*EventDispatcher, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	d, ok := r.dispatchers[key]
+	return d, ok
+}
+
+// Set stores the dispatcher d under given key, replacing the previous one
+func (r *Registry) Set(key string, d *EventDispatcher) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.dispatchers[key] = d
+}
+
+// Delete removes the dispatcher stored under given key
+func (r *Registry) Delete(key string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.dispatchers, key)
+}
+
+// Range calls f for each dispatcher in the order of keys, until f returns
+// false. The registry may be changed by f.
+func (r *Registry) Range(f func(key string, d *EventDispatcher) bool) {
+	r.RLock()
+	keys := make([]string, 0, len(r.dispatchers))
+	for key := range r.dispatchers {
+		keys = append(keys, key)
+	}
+	dispatchers := make(map[string]*EventDispatcher, len(r.dispatchers))
+	for key, d := range r.dispatchers {
+		dispatchers[key] = d
+	}
+	r.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if f(key, dispatchers[key]) == false {
+			return
+		}
+	}
+}
+
+// NewRegistry creates an empty registry creating the dispatchers with the
+// options opts
+func NewRegistry(opts ...Option) *Registry {
+	return &Registry{dispatchers: make(map[string]*EventDispatcher), opts: opts}
+}
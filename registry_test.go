@@ -0,0 +1,52 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRegistry(WithStats())
+	a := r.Get("a")
+	assert.Same(a, r.Get("a"), "The same dispatcher should be returned for the key!")
+	assert.NotNil(a.stats, "The dispatcher should be created with the options of the registry!")
+	_, ok := r.Lookup("b")
+	assert.False(ok, "Lookup should not create the dispatcher!")
+
+	b := NewDispatcher()
+	r.Set("b", b)
+	got, ok := r.Lookup("b")
+	assert.True(ok)
+	assert.Same(b, got, "The set dispatcher should be returned!")
+
+	var keys []string
+	r.Range(func(key string, d *EventDispatcher) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal([]string{"a", "b"}, keys, "All dispatchers should be ranged over in the order of keys!")
+
+	r.Delete("a")
+	assert.NotSame(a, r.Get("a"), "The deleted dispatcher should be created again!")
+}
+
+func TestRegistryConcurrentGet(t *testing.T) {
+	r := NewRegistry()
+	got := make([]*EventDispatcher, 10)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = r.Get("key")
+		}(i)
+	}
+	wg.Wait()
+	for _, d := range got {
+		assert.Same(t, got[0], d, "The concurrent calls should get the same dispatcher!")
+	}
+}
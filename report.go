@@ -0,0 +1,128 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ListenerReport describes a single listener call of the reported dispatch
+type ListenerReport struct {
+
+	// ListenerID identifies the registration of the called listener
+	ListenerID uint64
+
+	// Duration is the time the listener took
+	Duration time.Duration
+
+	// Errors are the errors reported by the listener
+	Errors []error
+
+	// Panicked informs whether the listener panicked. Panics are reported
+	// only by the dispatcher created WithRecover, otherwise they crash the
+	// dispatching goroutine.
+	Panicked bool
+
+	// StoppedPropagation informs whether the listener stopped the event
+	// propagation
+	StoppedPropagation bool
+}
+
+// DispatchReport describes the dispatch done by DispatchWithReport
+type DispatchReport struct {
+
+	// Name is the name the event has been dispatched under
+	Name string
+
+	// Duration is the time all listeners took
+	Duration time.Duration
+
+	// Listeners are the reports of the called listeners in the call order
+	Listeners []ListenerReport
+
+	// Err is the error returned by TryDispatch for the event
+	Err error
+
+	e Event
+}
+
+// reports tracks the reports of the dispatches in flight
+type reports struct {
+	sync.Mutex
+	inFlight []*DispatchReport
+}
+
+// DispatchWithReport dispatches the event like TryDispatch does and returns
+// the report describing each listener call
+func (d *EventDispatcher) DispatchWithReport(e Event) (Event, DispatchReport) {
+	rep := &DispatchReport{Name: e.Name()}
+	e, rep.Err = tryDispatch(d, e.Name(), e, rep)
+	rep.e = nil
+
+	return e, *rep
+}
+
+// startReport registers the report rep of the dispatch of event e under
+// name n as in flight
+func startReport(d *EventDispatcher, rep *DispatchReport, n string, e Event) {
+	rep.Name = n
+	rep.e = e
+	d.reports.Lock()
+	defer d.reports.Unlock()
+	d.reports.inFlight = append(d.reports.inFlight, rep)
+}
+
+// finishReport removes the report rep from the reports in flight
+func finishReport(d *EventDispatcher, rep *DispatchReport) {
+	d.reports.Lock()
+	defer d.reports.Unlock()
+	for i, ir := range d.reports.inFlight {
+		if ir == rep {
+			d.reports.inFlight = append(d.reports.inFlight[:i:i], d.reports.inFlight[i+1:]...)
+			break
+		}
+	}
+}
+
+// reportListener calls the listener through the invoke function and adds
+// its report to rep
+func reportListener(d *EventDispatcher, rep *DispatchReport, id uint64, e Event, invoke func()) {
+	stopped := e.IsPropagationStopped()
+	d.reports.Lock()
+	rep.Listeners = append(rep.Listeners, ListenerReport{ListenerID: id})
+	d.reports.Unlock()
+
+	start := d.clock.Now()
+	invoke()
+	duration := d.clock.Now().Sub(start)
+
+	d.reports.Lock()
+	defer d.reports.Unlock()
+	lr := &rep.Listeners[len(rep.Listeners)-1]
+	lr.Duration = duration
+	lr.StoppedPropagation = stopped == false && e.IsPropagationStopped()
+	rep.Duration += duration
+}
+
+// reportError attaches the error reported for event e to the currently
+// called listener of its in flight reports
+func reportError(d *EventDispatcher, e Event, err error) {
+	if reflect.TypeOf(e).Comparable() == false {
+		return
+	}
+
+	d.reports.Lock()
+	defer d.reports.Unlock()
+	for _, rep := range d.reports.inFlight {
+		if rep.e != e || len(rep.Listeners) == 0 {
+			continue
+		}
+		lr := &rep.Listeners[len(rep.Listeners)-1]
+		lr.Errors = append(lr.Errors, err)
+		if _, ok := err.(*PanicError); ok {
+			lr.Panicked = true
+		}
+	}
+}
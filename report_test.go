@@ -0,0 +1,54 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDispatchWithReport(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRecover())
+	err := errors.New("failed")
+	d.On(TestEventName, func(e Event) {
+		d.ReportError(e, err)
+	})
+	d.On(TestEventName, func(e Event) {
+		panic("boom")
+	})
+	d.On(TestEventName, func(e Event) {
+		e.StopPropagation()
+	})
+	d.On(TestEventName, func(e Event) {
+		t.Error("The listener after the propagation stop should not be called!")
+	})
+
+	_, rep := d.DispatchWithReport(NewParamsEvent(TestEventName))
+	assert.Nil(rep.Err)
+	assert.Equal(TestEventName, rep.Name)
+	assert.Equal(3, len(rep.Listeners), "Only the called listeners should be reported!")
+	assert.Equal([]error{err}, rep.Listeners[0].Errors, "The reported error should be attributed to the listener!")
+	assert.False(rep.Listeners[0].Panicked)
+	assert.True(rep.Listeners[1].Panicked, "The panic should be reported!")
+	assert.Equal(0, len(rep.Listeners[2].Errors))
+	assert.True(rep.Listeners[2].StoppedPropagation, "The propagation stop should be reported!")
+	assert.False(rep.Listeners[1].StoppedPropagation)
+	assert.Equal(uint64(3), rep.Listeners[2].ListenerID)
+}
+
+func TestStopPropagationSkipsListeners(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.On(TestEventName, func(e Event) {
+		c++
+		e.StopPropagation()
+	})
+	d.On(TestEventName, func(e Event) {
+		c++
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The listeners should not be called after the propagation is stopped!")
+}
@@ -0,0 +1,108 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// Response is the value returned by the RequestHandler
+type Response interface{}
+
+// RequestHandler handles the request event and returns the response
+type RequestHandler func(Event) (Response, error)
+
+var (
+	// ErrNoHandler is returned by Request when no handler is registered
+	// for the event name
+	ErrNoHandler = errors.New("eventdispatcher: no request handler")
+
+	// ErrHandlerExists is returned by Handle when a handler is already
+	// registered for the event name
+	ErrHandlerExists = errors.New("eventdispatcher: request handler already registered")
+
+	// ErrRequestDropped is returned by Request when a filter dropped the
+	// request event
+	ErrRequestDropped = errors.New("eventdispatcher: request dropped by filter")
+)
+
+// Handle registers the request handler h for given event name n (may
+// contain many space separated names). Exactly one handler may be
+// registered per name, returns ErrHandlerExists otherwise, registering the
+// handler for none of the names.
+func (d *EventDispatcher) Handle(n string, h RequestHandler) error {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	names := getNames(n)
+	for i, name := range names {
+		names[i] = normalize(d, name)
+	}
+	for _, name := range names {
+		if _, ok := d.handlers[name]; ok {
+			return fmt.Errorf("%w: %s", ErrHandlerExists, name)
+		}
+	}
+	for _, name := range names {
+		d.handlers[name] = h
+	}
+
+	return nil
+}
+
+// RemoveHandler removes the request handler registered for given name n
+func (d *EventDispatcher) RemoveHandler(n string) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	delete(d.handlers, normalize(d, n))
+}
+
+// HasHandler informs whether the request handler is registered for given
+// event name n
+func (d *EventDispatcher) HasHandler(n string) bool {
+	d.RWMutex.RLock()
+	defer d.RWMutex.RUnlock()
+
+	_, ok := d.handlers[normalize(d, n)]
+	return ok
+}
+
+// Request passes the event through the filters and the schema validation
+// and returns the response of the handler registered for its name. Returns
+// ErrNoHandler if there is no handler and ErrRequestDropped if the event
+// has been dropped by a filter.
+func (d *EventDispatcher) Request(e Event) (Response, error) {
+	e, n, ok := filter(d, normalize(d, e.Name()), e)
+	if ok == false {
+		return nil, ErrRequestDropped
+	}
+	if err := validate(d, n, e); err != nil {
+		return nil, err
+	}
+
+	d.RWMutex.RLock()
+	h, ok := d.handlers[n]
+	d.RWMutex.RUnlock()
+	if ok == false {
+		return nil, fmt.Errorf("%w: %s", ErrNoHandler, n)
+	}
+
+	return handle(d, h, e)
+}
+
+// handle calls the request handler, converting its panic into PanicError
+// if the dispatcher is configured to recover
+func handle(d *EventDispatcher, h RequestHandler, e Event) (resp Response, err error) {
+	if d.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, &PanicError{r, debug.Stack()}
+			}
+		}()
+	}
+
+	return h(e)
+}
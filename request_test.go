@@ -0,0 +1,50 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRequest(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	err := d.Handle("user.find user.get", func(e Event) (Response, error) {
+		id, _ := e.(*ParamsEvent).GetParam("id")
+		if id == 0 {
+			return nil, errors.New("not found")
+		}
+		return "user " + id.(string), nil
+	})
+	assert.Nil(err)
+	assert.True(d.HasHandler("user.get"))
+	assert.True(errors.Is(d.Handle("user.get", nil), ErrHandlerExists), "Only one handler per name should be allowed!")
+
+	resp, err := d.Request(NewParamsEvent("user.find").SetParam("id", "12"))
+	assert.Nil(err)
+	assert.Equal("user 12", resp)
+	_, err = d.Request(NewParamsEvent("user.get").SetParam("id", 0))
+	assert.EqualError(err, "not found", "The handler error should be returned!")
+
+	d.RemoveHandler("user.find")
+	_, err = d.Request(NewParamsEvent("user.find"))
+	assert.True(errors.Is(err, ErrNoHandler), "The removed handler should not be called!")
+}
+
+func TestRequestFilteredAndRecovered(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRecover())
+	d.Handle("query", func(e Event) (Response, error) {
+		panic("boom")
+	})
+	_, err := d.Request(NewParamsEvent("query"))
+	assert.IsType(&PanicError{}, err, "The handler panic should be returned as an error!")
+
+	d.AddFilterOn("query", func(e Event) (Event, bool) {
+		return e, false
+	})
+	_, err = d.Request(NewParamsEvent("query"))
+	assert.Equal(ErrRequestDropped, err)
+}
@@ -0,0 +1,39 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// ResultEvent is the event the listeners attach their results to, so the
+// dispatcher may be used to collect e.g. the menu items or the validation
+// errors from the plugins. Safe for concurrent listeners.
+type ResultEvent struct {
+	*ParamsEvent
+	mu      sync.Mutex
+	results []interface{}
+}
+
+// AddResult attaches the result v to the event. Returns this event instance
+func (event *ResultEvent) AddResult(v interface{}) *ResultEvent {
+	event.mu.Lock()
+	defer event.mu.Unlock()
+
+	event.results = append(event.results, v)
+	return event
+}
+
+// Results returns a copy of the results attached by the listeners, in the
+// order they were added
+func (event *ResultEvent) Results() []interface{} {
+	event.mu.Lock()
+	defer event.mu.Unlock()
+
+	return append([]interface{}(nil), event.results...)
+}
+
+// NewResultEvent creates the event collecting the results with given name
+func NewResultEvent(n string) *ResultEvent {
+	return &ResultEvent{ParamsEvent: NewParamsEvent(n)}
+}
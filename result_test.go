@@ -0,0 +1,22 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestResultEvent(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.On("menu.build", func(e Event) {
+		e.(*ResultEvent).AddResult("home")
+	})
+	d.On("menu.build", func(e Event) {
+		e.(*ResultEvent).AddResult("settings").AddResult("logout")
+	})
+	e := NewResultEvent("menu.build")
+	d.Dispatch(e)
+	assert.Equal([]interface{}{"home", "settings", "logout"}, e.Results(), "The results should be collected in order!")
+}
@@ -0,0 +1,138 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"time"
+)
+
+// FallibleListener is the listener reporting the failure of handling the
+// event by returning an error
+type FallibleListener func(Event) error
+
+// RetryPolicy defines how the failed invocations of the listener are
+// retried
+type RetryPolicy struct {
+
+	// MaxAttempts is the max number of invocations, including the first one
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between the retries, unless zero
+	MaxBackoff time.Duration
+
+	// Multiplier multiplies the delay after each retry, 2 if not set
+	Multiplier float64
+
+	// Retryable decides whether the invocation failed with given error
+	// should be retried. All errors are retried if nil.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy is used by OnRetry when no policy is given nor
+// configured for the event name
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     100 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+	Multiplier:  2,
+}
+
+// RetryError is reported when the listener exhausted all retries
+type RetryError struct {
+
+	// Attempts is the number of attempts made
+	Attempts int
+
+	// Err is the error of the last attempt
+	Err error
+}
+
+// Error returns the error message
+func (err *RetryError) Error() string {
+	return fmt.Sprintf("eventdispatcher: listener failed after %d attempts: %v", err.Attempts, err.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (err *RetryError) Unwrap() error {
+	return err.Err
+}
+
+// WithRetryPolicy sets the retry policy used by the listeners registered
+// with OnRetry without own policy for the events with given name n (may
+// contain many space separated names)
+func WithRetryPolicy(n string, p RetryPolicy) Option {
+	return func(d *EventDispatcher) {
+		for _, name := range getNames(n) {
+			d.retryPolicies[name] = p
+		}
+	}
+}
+
+// OnRetry registers the fallible listener for given event name. The first
+// invocation is synchronous. Failed invocations are retried in the
+// background according to the policy p, or the one configured for the
+// event name if p is nil. The same event instance is passed to all
+// attempts. Once the retries are exhausted, the event is put to the dead
+// letter queue and the RetryError is reported.
+func (d *EventDispatcher) OnRetry(n string, l FallibleListener, p *RetryPolicy) {
+	for _, name := range getNames(n) {
+		rp := retryPolicy(d, normalize(d, name), p)
+		name := name
+		on(d, name, func(e Event) {
+			attempt(d, name, e, l, rp, 1)
+		})
+	}
+}
+
+// retryPolicy returns the policy p or the one configured for event name n
+func retryPolicy(d *EventDispatcher, n string, p *RetryPolicy) RetryPolicy {
+	if p != nil {
+		return *p
+	}
+	if rp, ok := d.retryPolicies[n]; ok {
+		return rp
+	}
+
+	return DefaultRetryPolicy
+}
+
+// attempt invokes the listener l for the a-th time and schedules the next
+// attempt if it fails
+func attempt(d *EventDispatcher, n string, e Event, l FallibleListener, p RetryPolicy, a int) {
+	err := l(e)
+	if err == nil {
+		return
+	}
+	if a >= p.MaxAttempts || (p.Retryable != nil && p.Retryable(err) == false) {
+		deadLetter(d, DeadLetter{n, e, err, a, d.clock.Now()})
+		d.ReportError(e, &RetryError{a, err})
+		return
+	}
+	later(d, backoff(p, a), func() {
+		attempt(d, n, e, l, p, a+1)
+	})
+}
+
+// backoff returns the delay before the retry following the a-th attempt
+func backoff(p RetryPolicy, a int) time.Duration {
+	m := p.Multiplier
+	if m == 0 {
+		m = 2
+	}
+	b := float64(p.Backoff)
+	for i := 1; i < a; i++ {
+		b *= m
+		if p.MaxBackoff > 0 && b > float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && b > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+
+	return time.Duration(b)
+}
@@ -0,0 +1,76 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOnRetry(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.OnRetry(TestEventName, func(e Event) error {
+		c++
+		if c < 3 {
+			return errors.New("failed")
+		}
+		return nil
+	}, &RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Drain()
+	assert.Equal(3, c, "The listener should be retried until it succeeds!")
+	assert.Empty(d.DeadLetters(), "The succeeded event should not be dead lettered!")
+}
+
+func TestOnRetryExhausted(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode(), WithRetryPolicy(TestEventName, RetryPolicy{MaxAttempts: 2}))
+	err := errors.New("failed")
+	var c int
+	var ee *ErrorEvent
+	d.OnRetry(TestEventName, func(e Event) error {
+		c++
+		return err
+	}, nil)
+	d.On(ErrorEventName, func(e Event) {
+		ee = e.(*ErrorEvent)
+	})
+	e := NewParamsEvent(TestEventName)
+	d.Dispatch(e)
+	assert.Equal(2, c, "The policy configured for the event name should be used!")
+	letters := d.TakeDeadLetters()
+	assert.Equal(1, len(letters), "The event should be dead lettered!")
+	assert.Equal(e, letters[0].Event)
+	assert.Equal(2, letters[0].Attempts)
+	assert.Empty(d.DeadLetters(), "The taken dead letters should be removed!")
+	assert.NotNil(ee, "The error event should be dispatched!")
+	assert.ErrorIs(ee.Err, err, "The RetryError should wrap the last error!")
+}
+
+func TestOnRetryNotRetryable(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithSyncMode())
+	var c int
+	d.OnRetry(TestEventName, func(e Event) error {
+		c++
+		return errors.New("fatal")
+	}, &RetryPolicy{MaxAttempts: 5, Retryable: func(err error) bool {
+		return false
+	}})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "Not retryable errors should not be retried!")
+	assert.Equal(1, len(d.DeadLetters()), "The event should be dead lettered!")
+}
+
+func TestBackoff(t *testing.T) {
+	assert := assert.New(t)
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(time.Second, backoff(p, 1))
+	assert.Equal(2*time.Second, backoff(p, 2))
+	assert.Equal(4*time.Second, backoff(p, 3))
+	assert.Equal(5*time.Second, backoff(p, 4), "The backoff should be capped!")
+}
@@ -0,0 +1,45 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// Router computes the names of the listener buckets the event should reach,
+// e.g. "tenant.acme.order.paid" from the tenant param, instead of relying
+// on the event name only. Returning no names routes the event by the name
+// it has been dispatched under.
+type Router func(Event) []string
+
+// WithRouter makes the dispatcher deliver the events to the listeners of
+// the names returned by the router r, after the filters have been applied.
+// The listeners registered for many of the names are called once per
+// registration.
+func WithRouter(r Router) Option {
+	return func(d *EventDispatcher) {
+		d.router = r
+	}
+}
+
+// route returns the listeners of the event e dispatched under name n,
+// computed by the router of the dispatcher if any
+func route(d *EventDispatcher, n string, e Event) listenersCollection {
+	if d.router == nil {
+		return listenersFor(d, n)
+	}
+	names := d.router(e)
+	switch len(names) {
+	case 0:
+		return listenersFor(d, n)
+	case 1:
+		return listenersFor(d, names[0])
+	}
+
+	sources := make([]listenersCollection, 0, len(names))
+	for _, name := range names {
+		sources = append(sources, listenersFor(d, name))
+	}
+	routed := mergeListeners(sources)
+	for _, name := range names {
+		routed = orderDependencies(d, normalize(d, name), routed)
+	}
+
+	return routed
+}
@@ -0,0 +1,37 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithRouter(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithRouter(func(e Event) []string {
+		shard, ok := e.(*ParamsEvent).GetParam("shard")
+		if ok == false {
+			return nil
+		}
+		return []string{e.Name() + "." + shard.(string), e.Name() + ".all"}
+	}))
+	var got []string
+	d.On("order.paid", func(e Event) {
+		got = append(got, "default")
+	})
+	d.On("order.paid.1", func(e Event) {
+		got = append(got, "1")
+	})
+	d.On("order.paid.all order.paid.2", func(e Event) {
+		got = append(got, "all")
+	})
+	d.Dispatch(NewParamsEvent("order.paid").SetParam("shard", "1"))
+	assert.Equal([]string{"1", "all"}, got, "The event should reach the routed buckets!")
+	got = nil
+	d.Dispatch(NewParamsEvent("order.paid").SetParam("shard", "2"))
+	assert.Equal([]string{"all", "all"}, got, "Each registration should be called once!")
+	got = nil
+	d.Dispatch(NewParamsEvent("order.paid"))
+	assert.Equal([]string{"default"}, got, "The event without routes should reach the listeners of its name!")
+}
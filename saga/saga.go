@@ -0,0 +1,320 @@
+// Package saga contains the process manager coordinating long running
+// workflows on the event dispatcher. The saga listens on many events,
+// keeps the state of each workflow instance identified by the correlation
+// id and dispatches the follow-up events or the timeout event.
+package saga
+
+import (
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+	"time"
+)
+
+// ParamSagaID is the param of the timeout event holding the instance id
+const ParamSagaID = "saga_id"
+
+// StateDeadline is the key of the instance state holding the time the
+// instance times out at, formatted as RFC 3339, so the timeouts of the
+// stored instances can be armed again after a restart
+const StateDeadline = "saga_deadline"
+
+// Step handles the event received by the saga instance
+type Step func(c *Context) error
+
+// CorrelationFunc returns the id of the saga instance the event belongs to
+// and false if the event should be ignored
+type CorrelationFunc func(e ed.Event) (string, bool)
+
+// Context is passed to the step handling the event
+type Context struct {
+
+	// ID is the correlation id of the saga instance
+	ID string
+
+	// State is the state of the instance, saved after the step
+	State State
+
+	// Event is the handled event
+	Event ed.Event
+
+	followUps []ed.Event
+	completed bool
+}
+
+// Dispatch schedules the follow-up event, dispatched once the step is
+// done. The event is linked with the handled one using ed.CausedBy.
+func (c *Context) Dispatch(e ed.Event) {
+	c.followUps = append(c.followUps, ed.CausedBy(e, c.Event))
+}
+
+// Complete finishes the saga instance, removing its state and timeout
+func (c *Context) Complete() {
+	c.completed = true
+}
+
+// Option configures the saga
+type Option func(*Saga)
+
+// WithStore makes the saga keep the instance states in the store s
+func WithStore(s Store) Option {
+	return func(sg *Saga) {
+		sg.store = s
+	}
+}
+
+// WithCorrelation makes the saga find the instance id of the events with
+// the function f
+func WithCorrelation(f CorrelationFunc) Option {
+	return func(sg *Saga) {
+		sg.correlate = f
+	}
+}
+
+// WithTimeout makes the saga dispatch the event with name n when the
+// instance is not completed within the duration d since its first event.
+// The deadline is kept in the state of the instance under StateDeadline.
+func WithTimeout(d time.Duration, n string) Option {
+	return func(sg *Saga) {
+		sg.timeout = d
+		sg.timeoutName = n
+	}
+}
+
+// WithClock makes the saga use the clock c for the timeouts
+func WithClock(c ed.Clock) Option {
+	return func(sg *Saga) {
+		sg.clock = c
+	}
+}
+
+// Saga coordinates the workflow instances
+type Saga struct {
+	mu          sync.Mutex
+	name        string
+	d           *ed.EventDispatcher
+	store       Store
+	correlate   CorrelationFunc
+	steps       map[string]Step
+	timeout     time.Duration
+	timeoutName string
+	clock       ed.Clock
+	timers      map[string]*timeout
+	group       *ed.ListenerGroup
+}
+
+// timeout is the armed timeout of the instance, compared by identity, so
+// the timer fired after being replaced is ignored
+type timeout struct {
+	ed.Timer
+}
+
+// On registers the step handling the events with given name n (may
+// contain many space separated names). Must be called before Start.
+func (sg *Saga) On(n string, step Step) *Saga {
+	sg.steps[n] = step
+	return sg
+}
+
+// Start registers the listeners of the saga in the dispatcher. If the store
+// is a Lister, the timeouts of the stored instances are armed again, the
+// ones past their deadline firing at once. Returns the error of the store
+// listing or loading the instances.
+func (sg *Saga) Start() error {
+	for n := range sg.steps {
+		n := n
+		sg.group.On(n, func(e ed.Event) {
+			sg.handle(n, e)
+		})
+	}
+
+	return rearm(sg)
+}
+
+// rearm arms the timeouts of the instances kept in the store
+func rearm(sg *Saga) error {
+	l, ok := sg.store.(Lister)
+	if sg.timeout <= 0 || ok == false {
+		return nil
+	}
+	ids, err := l.IDs()
+	if err != nil {
+		return err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	for _, id := range ids {
+		state, _, err := sg.store.Load(id)
+		if err != nil {
+			return err
+		}
+		if at, ok := deadline(state); ok {
+			armTimeout(sg, id, at)
+		}
+	}
+
+	return nil
+}
+
+// Stop removes the listeners of the saga from the dispatcher and stops all
+// pending timeouts. The instance states are kept in the store.
+func (sg *Saga) Stop() {
+	sg.group.RemoveAll()
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	for id, t := range sg.timers {
+		t.Stop()
+		delete(sg.timers, id)
+	}
+}
+
+// handle passes the event to the step registered under name n
+func (sg *Saga) handle(n string, e ed.Event) {
+	id, ok := sg.correlate(e)
+	if ok == false {
+		return
+	}
+
+	followUps, err := sg.step(n, id, e)
+	if err != nil {
+		sg.d.ReportError(e, fmt.Errorf("saga %s: %w", sg.name, err))
+	}
+	for _, fe := range followUps {
+		sg.d.Dispatch(fe)
+	}
+}
+
+// step runs the step for the instance with given id and persists its
+// state. Returns the follow-up events to dispatch.
+func (sg *Saga) step(n string, id string, e ed.Event) ([]ed.Event, error) {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	state, ok, err := sg.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if ok == false {
+		state = make(State)
+		if sg.timeout > 0 {
+			state[StateDeadline] = sg.clock.Now().Add(sg.timeout).Format(time.RFC3339Nano)
+		}
+	}
+	at, timed := deadline(state)
+
+	c := &Context{ID: id, State: state, Event: e}
+	if err := sg.steps[n](c); err != nil {
+		return c.followUps, err
+	}
+	if c.completed {
+		disarmTimeout(sg, id)
+		return c.followUps, sg.store.Delete(id)
+	}
+	// The deadline is kept even if the step removed it from the state
+	if timed {
+		c.State[StateDeadline] = at.Format(time.RFC3339Nano)
+	}
+	if err := sg.store.Save(id, c.State); err != nil {
+		return c.followUps, err
+	}
+	if _, armed := sg.timers[id]; timed && armed == false {
+		armTimeout(sg, id, at)
+	}
+
+	return c.followUps, nil
+}
+
+// deadline returns the time the instance with given state times out at
+// and false if it has none
+func deadline(state State) (time.Time, bool) {
+	switch v := state[StateDeadline].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		at, err := time.Parse(time.RFC3339Nano, v)
+		return at, err == nil
+	}
+
+	return time.Time{}, false
+}
+
+// armTimeout starts the timeout of the instance with given id firing at the
+// time at, replacing the one armed before
+func armTimeout(sg *Saga, id string, at time.Time) {
+	disarmTimeout(sg, id)
+	t := &timeout{}
+	sg.timers[id] = t
+	t.Timer = sg.clock.AfterFunc(at.Sub(sg.clock.Now()), func() {
+		sg.mu.Lock()
+		ok := sg.timers[id] == t
+		if ok {
+			delete(sg.timers, id)
+			sg.store.Delete(id)
+		}
+		sg.mu.Unlock()
+
+		if ok {
+			sg.d.Dispatch(ed.NewParamsEvent(sg.timeoutName).SetParam(ParamSagaID, id))
+		}
+	})
+}
+
+// disarmTimeout stops the timeout of the instance with given id
+func disarmTimeout(sg *Saga, id string) {
+	if t, ok := sg.timers[id]; ok {
+		t.Stop()
+		delete(sg.timers, id)
+	}
+}
+
+// correlationID is the default CorrelationFunc using the correlation id of
+// the ed.Correlated events
+func correlationID(e ed.Event) (string, bool) {
+	ce, ok := e.(ed.Correlated)
+	if ok == false || ce.CorrelationID() == "" {
+		return "", false
+	}
+
+	return ce.CorrelationID(), true
+}
+
+// ParamCorrelation returns the CorrelationFunc taking the instance id from
+// the event param with given key k
+func ParamCorrelation(k string) CorrelationFunc {
+	return func(e ed.Event) (string, bool) {
+		pe, ok := e.(interface {
+			GetParam(k string) (interface{}, bool)
+		})
+		if ok == false {
+			return "", false
+		}
+		v, ok := pe.GetParam(k)
+		if ok == false {
+			return "", false
+		}
+
+		return fmt.Sprint(v), true
+	}
+}
+
+// New creates the saga with given name on the dispatcher d. By default the
+// instances are kept in memory and correlated by ed.Correlated ids.
+func New(name string, d *ed.EventDispatcher, opts ...Option) *Saga {
+	sg := &Saga{
+		name:      name,
+		d:         d,
+		store:     NewMemoryStore(),
+		correlate: correlationID,
+		steps:     make(map[string]Step),
+		clock:     ed.SystemClock{},
+		timers:    make(map[string]*timeout),
+		group:     d.Group("saga." + name),
+	}
+	for _, opt := range opts {
+		opt(sg)
+	}
+
+	return sg
+}
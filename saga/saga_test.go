@@ -0,0 +1,117 @@
+package saga
+
+import (
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/gacek85/eventdispatcher/eventdispatchertest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSaga(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	clock := eventdispatchertest.NewClock(time.Now())
+	store := NewMemoryStore()
+	sg := New("onboarding", d,
+		WithStore(store),
+		WithCorrelation(ParamCorrelation("user_id")),
+		WithTimeout(time.Hour, "onboarding.timeout"),
+		WithClock(clock))
+	step := func(k string) Step {
+		return func(c *Context) error {
+			c.State[k] = true
+			if c.State["email"] == true && c.State["profile"] == true {
+				c.Dispatch(ed.NewParamsEvent("onboarding.complete").SetParam("user_id", c.ID))
+				c.Complete()
+			}
+			return nil
+		}
+	}
+	assert.Nil(sg.On("email.verified", step("email")).On("profile.filled", step("profile")).Start())
+	spy := eventdispatchertest.NewSpy()
+	d.On("onboarding.complete onboarding.timeout", spy.Listener)
+
+	d.Dispatch(ed.NewParamsEvent("email.verified").SetParam("user_id", 1))
+	d.Dispatch(ed.NewParamsEvent("email.verified").SetParam("user_id", 2))
+	s, ok, _ := store.Load("1")
+	assert.True(ok, "The instance state should be stored!")
+	assert.Equal(true, s["email"])
+
+	d.Dispatch(ed.NewParamsEvent("profile.filled").SetParam("user_id", 1))
+	assert.Equal(1, len(spy.Calls()), "The follow-up event should be dispatched!")
+	assert.Equal("onboarding.complete", spy.Calls()[0].Name())
+	_, ok, _ = store.Load("1")
+	assert.False(ok, "The completed instance should be removed!")
+
+	clock.Advance(time.Hour)
+	assert.Equal(2, len(spy.Calls()), "The timeout event should be dispatched!")
+	id, _ := spy.Calls()[1].(*ed.ParamsEvent).GetParam(ParamSagaID)
+	assert.Equal("2", id, "Only the pending instance should time out!")
+	_, ok, _ = store.Load("2")
+	assert.False(ok, "The timed out instance should be removed!")
+
+	sg.Stop()
+	assert.False(d.HasListeners("email.verified"), "The stopped saga should remove its listeners!")
+}
+
+func TestSagaTimeoutAfterFailedStep(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	clock := eventdispatchertest.NewClock(time.Now())
+	sg := New("onboarding", d,
+		WithCorrelation(ParamCorrelation("user_id")),
+		WithTimeout(time.Hour, "onboarding.timeout"),
+		WithClock(clock))
+	fail := true
+	sg.On("email.verified", func(c *Context) error {
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	assert.Nil(sg.Start())
+	spy := eventdispatchertest.NewSpy()
+	d.On("onboarding.timeout", spy.Listener)
+
+	d.Dispatch(ed.NewParamsEvent("email.verified").SetParam("user_id", 1))
+	clock.Advance(30 * time.Minute)
+	fail = false
+	d.Dispatch(ed.NewParamsEvent("email.verified").SetParam("user_id", 1))
+	clock.Advance(30 * time.Minute)
+	assert.Equal(0, len(spy.Calls()), "The failed step should not arm the timeout!")
+	clock.Advance(30 * time.Minute)
+	assert.Equal(1, len(spy.Calls()), "The timeout should be armed by the saved instance!")
+}
+
+func TestSagaRearmsStoredTimeouts(t *testing.T) {
+	assert := assert.New(t)
+	d := ed.NewDispatcher()
+	clock := eventdispatchertest.NewClock(time.Now())
+	store := NewMemoryStore()
+	opts := []Option{
+		WithStore(store),
+		WithCorrelation(ParamCorrelation("user_id")),
+		WithTimeout(time.Hour, "onboarding.timeout"),
+		WithClock(clock),
+	}
+	noop := func(c *Context) error {
+		return nil
+	}
+	sg := New("onboarding", d, opts...).On("email.verified", noop)
+	assert.Nil(sg.Start())
+	d.Dispatch(ed.NewParamsEvent("email.verified").SetParam("user_id", 1))
+	sg.Stop()
+
+	spy := eventdispatchertest.NewSpy()
+	d.On("onboarding.timeout", spy.Listener)
+	clock.Advance(30 * time.Minute)
+	assert.Nil(New("onboarding", d, opts...).On("email.verified", noop).Start())
+	clock.Advance(29 * time.Minute)
+	assert.Equal(0, len(spy.Calls()), "The timeout should keep its deadline!")
+	clock.Advance(time.Minute)
+	assert.Equal(1, len(spy.Calls()), "The timeout of the stored instance should be armed again!")
+	_, ok, _ := store.Load("1")
+	assert.False(ok, "The timed out instance should be removed!")
+}
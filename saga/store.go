@@ -0,0 +1,94 @@
+package saga
+
+import (
+	"sync"
+)
+
+// State is the state of a single saga instance
+type State map[string]interface{}
+
+// Store persists the state of the saga instances by correlation id
+type Store interface {
+
+	// Load returns the state of the instance with given id and false if
+	// the instance does not exist
+	Load(id string) (State, bool, error)
+
+	// Save stores the state of the instance with given id
+	Save(id string, s State) error
+
+	// Delete removes the state of the instance with given id
+	Delete(id string) error
+}
+
+// Lister is implemented by the stores able to list the ids of their
+// instances, so the saga arms their timeouts again on Start
+type Lister interface {
+
+	// IDs returns the ids of all stored instances
+	IDs() ([]string, error)
+}
+
+// MemoryStore is the Store keeping the states in memory
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// Load returns the copy of the state of the instance with given id
+func (ms *MemoryStore) Load(id string) (State, bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	s, ok := ms.states[id]
+	if ok == false {
+		return nil, false, nil
+	}
+
+	return copyState(s), true, nil
+}
+
+// Save stores the copy of the state of the instance with given id
+func (ms *MemoryStore) Save(id string, s State) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.states[id] = copyState(s)
+	return nil
+}
+
+// Delete removes the state of the instance with given id
+func (ms *MemoryStore) Delete(id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.states, id)
+	return nil
+}
+
+// IDs returns the ids of all stored instances
+func (ms *MemoryStore) IDs() ([]string, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ids := make([]string, 0, len(ms.states))
+	for id := range ms.states {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func copyState(s State) State {
+	c := make(State, len(s))
+	for k, v := range s {
+		c[k] = v
+	}
+
+	return c
+}
+
+// NewMemoryStore creates an empty in memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
@@ -0,0 +1,59 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"math/rand/v2"
+)
+
+// sampleFloat returns the pseudo-random number in [0.0, 1.0), replaced in
+// tests
+var sampleFloat = rand.Float64
+
+// Sample wraps the listener l so it is called for the fraction rate of the
+// events only, e.g. 0.01 for every hundredth on average, so the high volume
+// events may be observed statistically. The counts observed by l should be
+// scaled by 1/rate.
+func Sample(rate float64, l Listener) Listener {
+	return func(e Event) {
+		if sampled(rate) {
+			l(e)
+		}
+	}
+}
+
+// WithSampling makes the dispatcher dispatch the fraction rate of the
+// events with given name n (may contain many space separated names), e.g.
+// 0.01 for every hundredth on average, silently dropping the others before
+// any listener is called
+func WithSampling(n string, rate float64) Option {
+	return func(d *EventDispatcher) {
+		for _, name := range getNames(n) {
+			d.sampling[name] = rate
+		}
+	}
+}
+
+// sample decides whether the event with given name n is dispatched
+// according to the sampling rate configured for it
+func sample(d *EventDispatcher, n string) bool {
+	rate, ok := d.sampling[n]
+	if ok == false {
+		return true
+	}
+
+	return sampled(rate)
+}
+
+// sampled decides randomly whether to keep the event, with the probability
+// rate
+func sampled(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	return sampleFloat() < rate
+}
@@ -0,0 +1,71 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand/v2"
+	"testing"
+)
+
+// sequence returns the function returning given numbers in turn
+func sequence(numbers ...float64) func() float64 {
+	var i int
+	return func() float64 {
+		n := numbers[i%len(numbers)]
+		i++
+		return n
+	}
+}
+
+func TestSample(t *testing.T) {
+	assert := assert.New(t)
+	sampleFloat = sequence(0.05, 0.5, 0.09, 0.95)
+	defer func() {
+		sampleFloat = rand.Float64
+	}()
+
+	d := NewDispatcher()
+	var c int
+	d.On(TestEventName, Sample(0.1, func(e Event) {
+		c++
+	}))
+	for i := 0; i < 4; i++ {
+		d.Dispatch(NewParamsEvent(TestEventName))
+	}
+	assert.Equal(2, c, "Only the sampled events should be passed to the listener!")
+
+	c = 0
+	all := Sample(1, func(e Event) {
+		c++
+	})
+	none := Sample(0, func(e Event) {
+		c--
+	})
+	all(NewParamsEvent(TestEventName))
+	none(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The rate of 1 should keep all events and 0 none!")
+}
+
+func TestWithSampling(t *testing.T) {
+	assert := assert.New(t)
+	sampleFloat = sequence(0.3, 0.7)
+	defer func() {
+		sampleFloat = rand.Float64
+	}()
+
+	d := NewDispatcher(WithSampling("request", 0.5))
+	var requests, others int
+	d.On("request", func(e Event) {
+		requests++
+	})
+	d.On(TestEventName, func(e Event) {
+		others++
+	})
+	for i := 0; i < 4; i++ {
+		d.Dispatch(NewParamsEvent("request"))
+		d.Dispatch(NewParamsEvent(TestEventName))
+	}
+	assert.Equal(2, requests, "Only the sampled events should be dispatched!")
+	assert.Equal(4, others, "The events of the other names should not be sampled!")
+}
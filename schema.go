@@ -0,0 +1,151 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationMode defines what happens to the event not matching the schema
+// registered for its name
+type ValidationMode int
+
+const (
+	// ValidationReject drops the invalid event and makes TryDispatch
+	// return the ValidationError
+	ValidationReject ValidationMode = iota
+
+	// ValidationFlag dispatches the invalid event and reports the
+	// ValidationError with ReportError
+	ValidationFlag
+)
+
+// ParamSpec describes the expected param of the event
+type ParamSpec struct {
+
+	// Required informs whether the param must be present
+	Required bool
+
+	// Type is the expected type of the param value, nil means any type
+	Type reflect.Type
+}
+
+// Schema describes the params expected by the listeners of the event
+type Schema struct {
+	Params map[string]ParamSpec
+}
+
+// Require adds the required param with given key k and value type t (nil
+// means any type). Returns this schema instance
+func (s *Schema) Require(k string, t reflect.Type) *Schema {
+	s.Params[k] = ParamSpec{true, t}
+	return s
+}
+
+// Optional adds the optional param with given key k and value type t (nil
+// means any type) checked only when present. Returns this schema instance
+func (s *Schema) Optional(k string, t reflect.Type) *Schema {
+	s.Params[k] = ParamSpec{false, t}
+	return s
+}
+
+// Validate checks the event against the schema. Returns the
+// ValidationError listing all problems found or nil if the event is valid.
+func (s *Schema) Validate(e Event) error {
+	var params map[string]interface{}
+	if pe, ok := e.(interface {
+		Params() map[string]interface{}
+	}); ok {
+		params = pe.Params()
+	}
+
+	keys := make([]string, 0, len(s.Params))
+	for k := range s.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var problems []string
+	for _, k := range keys {
+		spec := s.Params[k]
+		v, ok := params[k]
+		if ok == false {
+			if spec.Required {
+				problems = append(problems, fmt.Sprintf("missing param %q", k))
+			}
+			continue
+		}
+		if spec.Type != nil && (v == nil || reflect.TypeOf(v).AssignableTo(spec.Type) == false) {
+			problems = append(problems, fmt.Sprintf("param %q is %T, expected %s", k, v, spec.Type))
+		}
+	}
+	if len(problems) != 0 {
+		return &ValidationError{e.Name(), problems}
+	}
+
+	return nil
+}
+
+// ValidationError is the error of the event not matching its schema
+type ValidationError struct {
+	Name     string
+	Problems []string
+}
+
+// Error returns the error message
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("eventdispatcher: invalid event %s: %s", err.Name, strings.Join(err.Problems, ", "))
+}
+
+// schemaEntry is the schema registered for the event name with its mode
+type schemaEntry struct {
+	s    *Schema
+	mode ValidationMode
+}
+
+// SetSchema registers the schema s validating the events with given name n
+// (may contain many space separated names) at dispatch time. The mode
+// defines what happens to the invalid events. Nil schema removes the
+// registered one.
+func (d *EventDispatcher) SetSchema(n string, s *Schema, mode ValidationMode) {
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+
+	for _, name := range getNames(n) {
+		name = normalize(d, name)
+		if s == nil {
+			delete(d.schemas, name)
+			continue
+		}
+		d.schemas[name] = schemaEntry{s, mode}
+	}
+}
+
+// validate checks the event dispatched under name n against its schema.
+// Returns the ValidationError if the event must not be dispatched.
+func validate(d *EventDispatcher, n string, e Event) error {
+	d.RWMutex.RLock()
+	se, ok := d.schemas[n]
+	d.RWMutex.RUnlock()
+	if ok == false {
+		return nil
+	}
+
+	err := se.s.Validate(e)
+	if err == nil {
+		return nil
+	}
+	if se.mode == ValidationFlag {
+		d.ReportError(e, err)
+		return nil
+	}
+
+	return err
+}
+
+// NewSchema creates an empty schema
+func NewSchema() *Schema {
+	return &Schema{Params: make(map[string]ParamSpec)}
+}
@@ -0,0 +1,63 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func newTestSchema() *Schema {
+	return NewSchema().
+		Require("id", reflect.TypeOf(0)).
+		Require("email", nil).
+		Optional("note", reflect.TypeOf(""))
+}
+
+func TestSchemaValidate(t *testing.T) {
+	assert := assert.New(t)
+	s := newTestSchema()
+	e := NewParamsEvent("user.created").SetParam("id", 1).SetParam("email", "john@example.com")
+	assert.Nil(s.Validate(e), "The valid event should pass!")
+
+	e.SetParam("id", "1").RemoveParam("email").SetParam("note", 5)
+	err := s.Validate(e)
+	assert.NotNil(err)
+	assert.Equal([]string{
+		`missing param "email"`,
+		`param "id" is string, expected int`,
+		`param "note" is int, expected string`,
+	}, err.(*ValidationError).Problems)
+}
+
+func TestSchemaReject(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.On("user.created", func(e Event) {
+		c++
+	})
+	d.SetSchema("user.created", newTestSchema(), ValidationReject)
+	_, err := d.TryDispatch(NewParamsEvent("user.created"))
+	assert.IsType(&ValidationError{}, err, "The invalid event should be rejected!")
+	assert.Equal(0, c, "The rejected event should not be dispatched!")
+
+	d.SetSchema("user.created", nil, ValidationReject)
+	_, err = d.TryDispatch(NewParamsEvent("user.created"))
+	assert.Nil(err, "The removed schema should not be validated!")
+}
+
+func TestSchemaFlag(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.On("user.created", func(e Event) {
+		c++
+	})
+	d.SetSchema("user.created", newTestSchema(), ValidationFlag)
+	_, err := d.TryDispatch(NewParamsEvent("user.created"))
+	assert.Nil(err, "The flagged event should be dispatched!")
+	assert.Equal(1, c)
+	assert.IsType(&ValidationError{}, <-d.Errors(), "The validation error should be reported!")
+}
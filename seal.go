@@ -0,0 +1,30 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// Seal fixes the listener topology of the dispatcher, e.g. once the
+// service has been initialized. Registering and removing the listeners,
+// including the ones of all events, the groups, the subscribers and the
+// modules afterwards is rejected with ErrSealed, returned by OnE, OnceE,
+// OnAnyE, OffE and OffAllE and reported with ReportError otherwise. The
+// listeners removing themselves, like the once listeners and the
+// subscriptions, still work.
+func (d *EventDispatcher) Seal() {
+	d.sealed.Store(true)
+}
+
+// IsSealed informs whether the dispatcher has been sealed
+func (d *EventDispatcher) IsSealed() bool {
+	return d.sealed.Load()
+}
+
+// rejectSealed reports the change of the listeners of name n if the
+// dispatcher is sealed. Returns true if the change must be rejected.
+func rejectSealed(d *EventDispatcher, n string) bool {
+	if d.sealed.Load() == false {
+		return false
+	}
+	d.ReportError(NewParamsEvent(n), &RegistrationError{n, ErrSealed})
+
+	return true
+}
@@ -0,0 +1,82 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeal(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	l := func(e Event) {}
+	d.On("a", l)
+	var c int
+	d.Once("b", func(e Event) {
+		c++
+	})
+	d.Seal()
+	assert.True(d.IsSealed())
+
+	assert.ErrorIs(d.OnE("c", l), ErrSealed, "OnE should be rejected!")
+	d.On("c", l)
+	d.Subscribe("c", l)
+	assert.False(d.HasListeners("c"), "The listeners should not be registered!")
+	assert.Equal(0, d.Off("a", l), "Off should be rejected!")
+	d.OffAll("a")
+	assert.True(d.HasListeners("a"), "The listeners should not be removed!")
+	assert.Len(errs, 4, "The rejected changes should be reported!")
+	for _, err := range errs {
+		assert.ErrorIs(err, ErrSealed)
+	}
+
+	d.Dispatch(NewParamsEvent("b"))
+	assert.Equal(1, c, "The once listener should be called!")
+	assert.False(d.HasListeners("b"), "The once listener should still remove itself!")
+}
+
+func TestSealAllMutators(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var errs []error
+	d.On(ErrorEventName, func(e Event) {
+		errs = append(errs, e.(*ErrorEvent).Err)
+	})
+	l := func(e Event) {}
+	g := d.Group("plugins")
+	g.On("a", l)
+	s := &testSubscriber{}
+	d.AddSubscriber(s)
+	d.Seal()
+
+	var c int
+	d.OnAny(func(e Event) {
+		c++
+	})
+	g.On("b", l)
+	g.Off("a", l)
+	g.OffAll("a")
+	g.RemoveAll()
+	d.RemoveSubscriber(s)
+	assert.Len(errs, 6, "The rejected changes should be reported!")
+	for _, err := range errs {
+		assert.ErrorIs(err, ErrSealed)
+	}
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal(0, c, "The listener of all events should not be registered!")
+	assert.Equal(1, s.created, "The subscriber should not be removed!")
+	assert.True(g.HasListeners("a"), "The group listeners should not be removed!")
+	assert.False(g.HasListeners("b"), "The group listener should not be registered!")
+
+	_, err := d.OnAnyE(l)
+	assert.ErrorIs(err, ErrSealed, "OnAnyE should be rejected!")
+	_, err = d.OffE("a", l)
+	assert.ErrorIs(err, ErrSealed, "OffE should be rejected!")
+	assert.ErrorIs(d.OffAllE("a"), ErrSealed, "OffAllE should be rejected!")
+	assert.True(d.HasListeners("a"), "The listeners should not be removed!")
+}
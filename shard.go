@@ -0,0 +1,81 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// DefaultShardCount is the number of shards of the listeners map, unless
+// configured with WithShards
+const DefaultShardCount = 32
+
+// listenerShard holds the listeners of the event names hashed to it, so
+// registering the listeners of one name does not block dispatching others
+type listenerShard struct {
+	sync.RWMutex
+	listeners map[string]listenersCollection
+}
+
+// WithShards sets the number of shards the listeners map is split into by
+// the event name hash. More shards reduce the lock contention when many
+// goroutines register and dispatch different events concurrently.
+func WithShards(n int) Option {
+	return func(d *EventDispatcher) {
+		d.shards = newShards(n)
+	}
+}
+
+// shardOf returns the shard holding the listeners of event name n
+func shardOf(d *EventDispatcher, n string) *listenerShard {
+	// Inlined FNV-1a, so hashing does not allocate
+	h := uint32(2166136261)
+	for i := 0; i < len(n); i++ {
+		h ^= uint32(n[i])
+		h *= 16777619
+	}
+
+	return d.shards[h%uint32(len(d.shards))]
+}
+
+// lockShards locks all shards in order, e.g. to change the listeners of
+// many names atomically
+func lockShards(d *EventDispatcher) {
+	for _, s := range d.shards {
+		s.Lock()
+	}
+}
+
+// unlockShards unlocks all shards locked with lockShards
+func unlockShards(d *EventDispatcher) {
+	for _, s := range d.shards {
+		s.Unlock()
+	}
+}
+
+// rlockShards read locks all shards in order, e.g. to read the listeners of
+// many names consistently
+func rlockShards(d *EventDispatcher) {
+	for _, s := range d.shards {
+		s.RLock()
+	}
+}
+
+// runlockShards unlocks all shards locked with rlockShards
+func runlockShards(d *EventDispatcher) {
+	for _, s := range d.shards {
+		s.RUnlock()
+	}
+}
+
+func newShards(n int) []*listenerShard {
+	if n < 1 {
+		n = 1
+	}
+	shards := make([]*listenerShard, n)
+	for i := range shards {
+		shards[i] = &listenerShard{listeners: make(map[string]listenersCollection)}
+	}
+
+	return shards
+}
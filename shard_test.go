@@ -0,0 +1,46 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShards(t *testing.T) {
+	for _, shards := range []int{0, 1, 7} {
+		t.Run(fmt.Sprintf("shards=%d", shards), func(t *testing.T) {
+			assert := assert.New(t)
+			d := NewDispatcher(WithShards(shards))
+			var c int32
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(n string) {
+					defer wg.Done()
+					l := func(e Event) {
+						atomic.AddInt32(&c, 1)
+					}
+					for j := 0; j < 100; j++ {
+						d.On(n, l)
+						d.Dispatch(NewParamsEvent(n))
+						d.Off(n, l)
+					}
+				}(fmt.Sprintf("event.%d", i))
+			}
+			wg.Wait()
+			assert.Equal(int32(800), atomic.LoadInt32(&c), "Every dispatch should reach its listener!")
+			for i := 0; i < 8; i++ {
+				assert.False(d.HasListeners(fmt.Sprintf("event.%d", i)))
+			}
+		})
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	d := NewDispatcher(WithShards(4))
+	assert.Same(t, shardOf(d, TestEventName), shardOf(d, TestEventName), "The name should always map to the same shard!")
+}
@@ -0,0 +1,73 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const (
+	// SignalEventPrefix prefixes the names of the events dispatched for the
+	// OS signals, e.g. "os.signal.SIGTERM"
+	SignalEventPrefix = "os.signal."
+
+	// ParamSignal is the param of the signal event holding the os.Signal
+	ParamSignal = "signal"
+)
+
+// signalNames are the names of the common signals, as their String method
+// returns the description instead
+var signalNames = map[os.Signal]string{
+	syscall.SIGHUP:  "SIGHUP",
+	syscall.SIGINT:  "SIGINT",
+	syscall.SIGQUIT: "SIGQUIT",
+	syscall.SIGTERM: "SIGTERM",
+	syscall.SIGKILL: "SIGKILL",
+}
+
+// NotifySignals dispatches the event named SignalEventPrefix followed by the
+// signal name, e.g. "os.signal.SIGTERM", for each of given OS signals
+// received by the process. All incoming signals are relayed if none are
+// given. Returns the function stopping the relay.
+func (d *EventDispatcher) NotifySignals(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case s := <-ch:
+				d.Dispatch(NewSignalEvent(s))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// SignalEventName returns the name of the event dispatched for the signal s
+func SignalEventName(s os.Signal) string {
+	name, ok := signalNames[s]
+	if ok == false {
+		name = strings.ReplaceAll(s.String(), " ", "_")
+	}
+
+	return SignalEventPrefix + name
+}
+
+// NewSignalEvent creates the event for the OS signal s
+func NewSignalEvent(s os.Signal) *ParamsEvent {
+	return NewParamsEvent(SignalEventName(s)).SetParam(ParamSignal, s)
+}
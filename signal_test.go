@@ -0,0 +1,40 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifySignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Signals can not be sent to the own process on windows")
+	}
+	assert := assert.New(t)
+	d := NewDispatcher()
+	received := make(chan Event, 1)
+	d.On("os.signal.SIGHUP", func(e Event) {
+		received <- e
+	})
+	stop := d.NotifySignals(syscall.SIGHUP)
+	defer stop()
+
+	p, _ := os.FindProcess(os.Getpid())
+	assert.Nil(p.Signal(syscall.SIGHUP))
+	select {
+	case e := <-received:
+		s, _ := e.(*ParamsEvent).GetParam(ParamSignal)
+		assert.Equal(syscall.SIGHUP, s, "The signal should be passed in the event!")
+	case <-time.After(time.Second):
+		t.Fatal("The signal event should be dispatched!")
+	}
+}
+
+func TestSignalEventName(t *testing.T) {
+	assert.Equal(t, "os.signal.SIGTERM", SignalEventName(syscall.SIGTERM))
+}
@@ -0,0 +1,169 @@
+// Package signing contains the signing and verification of the events
+// serialized to be bridged across processes, with the key rotation support
+package signing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	ed "github.com/gacek85/eventdispatcher"
+	"sync"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of the event does
+	// not match, e.g. the event has been tampered with
+	ErrInvalidSignature = errors.New("signing: invalid signature")
+
+	// ErrUnknownKey is returned when the event is signed with the key not
+	// in the keyring, e.g. already retired
+	ErrUnknownKey = errors.New("signing: unknown key")
+)
+
+// QuarantineFunc receives the serialized events failing the verification
+// along with the reason
+type QuarantineFunc func(data []byte, err error)
+
+// Option configures the keyring
+type Option func(*Keyring)
+
+// WithQuarantine makes the keyring pass the events failing the
+// verification in Receive to f, instead of only rejecting them
+func WithQuarantine(f QuarantineFunc) Option {
+	return func(k *Keyring) {
+		k.quarantine = f
+	}
+}
+
+// envelope is the serialized signed event
+type envelope struct {
+	KeyID     string          `json:"kid"`
+	Algorithm string          `json:"alg"`
+	Event     json.RawMessage `json:"event"`
+	Signature []byte          `json:"sig"`
+}
+
+// message returns the signed message binding the key and the algorithm to
+// the event
+func (env envelope) message() []byte {
+	return append([]byte(env.Algorithm+"."+env.KeyID+"."), env.Event...)
+}
+
+// Keyring signs the events with its current key and verifies them with any
+// of its keys. Rotate the keys by adding the new current one, then retire
+// the old one once all peers sign with the new key.
+type Keyring struct {
+	sync.RWMutex
+	current    Key
+	keys       map[string]Key
+	quarantine QuarantineFunc
+}
+
+// Rotate adds the key k and makes it the current one used for signing. The
+// previous keys still verify until retired.
+func (k *Keyring) Rotate(key Key) {
+	k.Lock()
+	defer k.Unlock()
+
+	k.keys[key.ID()] = key
+	k.current = key
+}
+
+// Add adds the key k only verifying the events, e.g. the public key of the
+// peer
+func (k *Keyring) Add(key Key) {
+	k.Lock()
+	defer k.Unlock()
+
+	k.keys[key.ID()] = key
+}
+
+// Retire removes the key with given id, so the events signed with it are
+// no longer accepted. The current key cannot be retired.
+func (k *Keyring) Retire(id string) {
+	k.Lock()
+	defer k.Unlock()
+
+	if k.current != nil && k.current.ID() == id {
+		return
+	}
+	delete(k.keys, id)
+}
+
+// Sign serializes the event and signs it with the current key
+func (k *Keyring) Sign(e *ed.ParamsEvent) ([]byte, error) {
+	k.RLock()
+	key := k.current
+	k.RUnlock()
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	env := envelope{KeyID: key.ID(), Algorithm: key.Algorithm(), Event: data}
+	if env.Signature, err = key.Sign(env.message()); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(env)
+}
+
+// Verify checks the signature of the serialized event and decodes it.
+// Returns ErrUnknownKey or ErrInvalidSignature if the event cannot be
+// trusted.
+func (k *Keyring) Verify(data []byte) (*ed.ParamsEvent, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	k.RLock()
+	key, ok := k.keys[env.KeyID]
+	k.RUnlock()
+	if ok == false {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, env.KeyID)
+	}
+	if env.Algorithm != key.Algorithm() || key.Verify(env.message(), env.Signature) == false {
+		return nil, ErrInvalidSignature
+	}
+
+	e := ed.NewParamsEvent("")
+	if err := json.Unmarshal(env.Event, e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	return e, nil
+}
+
+// Receive verifies the serialized event and dispatches it with the
+// dispatcher d. The events failing the verification are passed to the
+// quarantine function, if configured, and the error is returned.
+func (k *Keyring) Receive(d ed.Dispatcher, data []byte) error {
+	e, err := k.Verify(data)
+	if err != nil {
+		if k.quarantine != nil {
+			k.quarantine(data, err)
+		}
+		return err
+	}
+	d.Dispatch(e)
+
+	return nil
+}
+
+// NewKeyring creates the keyring signing with the current key. The current
+// key may be nil for the keyring only verifying the events.
+func NewKeyring(current Key, opts ...Option) *Keyring {
+	k := &Keyring{keys: make(map[string]Key)}
+	if current != nil {
+		k.Rotate(current)
+	}
+	for _, opt := range opts {
+		opt(k)
+	}
+
+	return k
+}
@@ -0,0 +1,65 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	assert := assert.New(t)
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	sk, _ := Ed25519Key("k1", priv)
+	pk, _ := Ed25519PublicKey("k1", pub)
+	sender := NewKeyring(sk)
+	receiver := NewKeyring(nil)
+	receiver.Add(pk)
+
+	data, err := sender.Sign(ed.NewParamsEvent("order.paid").SetParam("amount", "10"))
+	assert.Nil(err, "Signing should succeed!")
+	e, err := receiver.Verify(data)
+	assert.Nil(err, "The signed event should be verified!")
+	assert.Equal("order.paid", e.Name(), "Invalid event name!")
+	v, _ := e.GetParam("amount")
+	assert.Equal("10", v, "Invalid event params!")
+
+	_, err = receiver.Verify(bytes.Replace(data, []byte("order.paid"), []byte("order.free"), 1))
+	assert.ErrorIs(err, ErrInvalidSignature, "The tampered event should be rejected!")
+}
+
+func TestKeyringRotation(t *testing.T) {
+	assert := assert.New(t)
+	k := NewKeyring(mustHMACKey("old", "old secret"))
+	old, _ := k.Sign(ed.NewParamsEvent("test"))
+	k.Rotate(mustHMACKey("new", "new secret"))
+	_, err := k.Verify(old)
+	assert.Nil(err, "The old key should verify until retired!")
+	k.Retire("old")
+	_, err = k.Verify(old)
+	assert.ErrorIs(err, ErrUnknownKey, "The retired key should not verify!")
+	k.Retire("new")
+	data, _ := k.Sign(ed.NewParamsEvent("test"))
+	_, err = k.Verify(data)
+	assert.Nil(err, "The current key should not be retired!")
+}
+
+func TestKeyringReceive(t *testing.T) {
+	assert := assert.New(t)
+	var quarantined [][]byte
+	k := NewKeyring(mustHMACKey("k1", "secret"), WithQuarantine(func(data []byte, err error) {
+		quarantined = append(quarantined, data)
+	}))
+	d := ed.NewDispatcher()
+	var c int
+	d.On("test", func(e ed.Event) {
+		c++
+	})
+	data, _ := k.Sign(ed.NewParamsEvent("test"))
+	assert.Nil(k.Receive(d, data), "The valid event should be received!")
+	forged, _ := NewKeyring(mustHMACKey("k1", "guess")).Sign(ed.NewParamsEvent("test"))
+	assert.ErrorIs(k.Receive(d, forged), ErrInvalidSignature, "The forged event should be rejected!")
+	assert.Equal(1, c, "Only the valid event should be dispatched!")
+	assert.Equal([][]byte{forged}, quarantined, "The forged event should be quarantined!")
+}
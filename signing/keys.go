@@ -0,0 +1,139 @@
+// Package signing contains the signing and verification of the events
+// serialized to be bridged across processes, with the key rotation support
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// AlgorithmHMAC is the algorithm of the keys created with HMACKey
+	AlgorithmHMAC = "HS256"
+
+	// AlgorithmEd25519 is the algorithm of the keys created with
+	// Ed25519Key and Ed25519PublicKey
+	AlgorithmEd25519 = "EdDSA"
+)
+
+// ErrVerifyOnly is returned when signing with the public key
+var ErrVerifyOnly = errors.New("signing: key may only verify")
+
+// ErrInvalidKey is returned when the key material is empty or has invalid
+// length
+var ErrInvalidKey = errors.New("signing: invalid key")
+
+// Key signs the messages and verifies their signatures
+type Key interface {
+
+	// ID returns the identifier of the key, sent along with the signature
+	ID() string
+
+	// Algorithm returns the name of the signing algorithm
+	Algorithm() string
+
+	// Sign returns the signature of the message
+	Sign(msg []byte) ([]byte, error)
+
+	// Verify informs whether the signature of the message is valid
+	Verify(msg []byte, sig []byte) bool
+}
+
+// hmacKey is the Key signing with HMAC-SHA256
+type hmacKey struct {
+	id     string
+	secret []byte
+}
+
+// ID returns the identifier of the key
+func (k hmacKey) ID() string {
+	return k.id
+}
+
+// Algorithm returns AlgorithmHMAC
+func (k hmacKey) Algorithm() string {
+	return AlgorithmHMAC
+}
+
+// Sign returns the HMAC-SHA256 of the message
+func (k hmacKey) Sign(msg []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(msg)
+
+	return mac.Sum(nil), nil
+}
+
+// Verify compares the signature with the HMAC-SHA256 of the message in
+// constant time
+func (k hmacKey) Verify(msg []byte, sig []byte) bool {
+	expected, _ := k.Sign(msg)
+
+	return hmac.Equal(expected, sig)
+}
+
+// ed25519Key is the Key signing with Ed25519. The private key is nil for
+// the verify only keys.
+type ed25519Key struct {
+	id   string
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+// ID returns the identifier of the key
+func (k ed25519Key) ID() string {
+	return k.id
+}
+
+// Algorithm returns AlgorithmEd25519
+func (k ed25519Key) Algorithm() string {
+	return AlgorithmEd25519
+}
+
+// Sign returns the Ed25519 signature of the message, or ErrVerifyOnly if
+// the key has no private part
+func (k ed25519Key) Sign(msg []byte) ([]byte, error) {
+	if k.priv == nil {
+		return nil, ErrVerifyOnly
+	}
+
+	return ed25519.Sign(k.priv, msg), nil
+}
+
+// Verify checks the Ed25519 signature of the message
+func (k ed25519Key) Verify(msg []byte, sig []byte) bool {
+	return ed25519.Verify(k.pub, msg, sig)
+}
+
+// HMACKey creates the key signing with HMAC-SHA256 and the shared secret.
+// Returns ErrInvalidKey if the secret is empty.
+func HMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%w: secret %s is empty", ErrInvalidKey, id)
+	}
+
+	return hmacKey{id, secret}, nil
+}
+
+// Ed25519Key creates the key signing with the Ed25519 private key. Returns
+// ErrInvalidKey if the private key has invalid length.
+func Ed25519Key(id string, priv ed25519.PrivateKey) (Key, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%w: private key %s has %d bytes", ErrInvalidKey, id, len(priv))
+	}
+
+	return ed25519Key{id, priv, priv.Public().(ed25519.PublicKey)}, nil
+}
+
+// Ed25519PublicKey creates the key only verifying the signatures with the
+// Ed25519 public key, for the receiving side. Returns ErrInvalidKey if the
+// public key has invalid length.
+func Ed25519PublicKey(id string, pub ed25519.PublicKey) (Key, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: public key %s has %d bytes", ErrInvalidKey, id, len(pub))
+	}
+
+	return ed25519Key{id, nil, pub}, nil
+}
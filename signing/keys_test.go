@@ -0,0 +1,59 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// mustHMACKey creates the HMAC key with the valid secret
+func mustHMACKey(id string, secret string) Key {
+	k, err := HMACKey(id, []byte(secret))
+	if err != nil {
+		panic(err)
+	}
+
+	return k
+}
+
+func TestHMACKey(t *testing.T) {
+	assert := assert.New(t)
+	k, err := HMACKey("k1", []byte("secret"))
+	assert.Nil(err, "The secret should be valid!")
+	sig, err := k.Sign([]byte("msg"))
+	assert.Nil(err, "Signing should succeed!")
+	assert.True(k.Verify([]byte("msg"), sig), "The signature should be valid!")
+	assert.False(k.Verify([]byte("tampered"), sig), "The signature of other message should be invalid!")
+	assert.False(mustHMACKey("k1", "other").Verify([]byte("msg"), sig), "The signature with other secret should be invalid!")
+}
+
+func TestHMACInvalidKey(t *testing.T) {
+	assert := assert.New(t)
+	_, err := HMACKey("k1", nil)
+	assert.ErrorIs(err, ErrInvalidKey, "The nil secret should be rejected!")
+	_, err = HMACKey("k1", []byte{})
+	assert.ErrorIs(err, ErrInvalidKey, "The empty secret should be rejected!")
+}
+
+func TestEd25519Key(t *testing.T) {
+	assert := assert.New(t)
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	sk, err := Ed25519Key("k1", priv)
+	assert.Nil(err, "The private key should be valid!")
+	sig, err := sk.Sign([]byte("msg"))
+	assert.Nil(err, "Signing should succeed!")
+	k, err := Ed25519PublicKey("k1", pub)
+	assert.Nil(err, "The public key should be valid!")
+	assert.True(k.Verify([]byte("msg"), sig), "The signature should be valid!")
+	assert.False(k.Verify([]byte("tampered"), sig), "The signature of other message should be invalid!")
+	_, err = k.Sign([]byte("msg"))
+	assert.ErrorIs(err, ErrVerifyOnly, "The public key should not sign!")
+}
+
+func TestEd25519InvalidKey(t *testing.T) {
+	assert := assert.New(t)
+	_, err := Ed25519PublicKey("k1", ed25519.PublicKey("short"))
+	assert.ErrorIs(err, ErrInvalidKey, "The public key of invalid length should be rejected!")
+	_, err = Ed25519Key("k1", ed25519.PrivateKey("short"))
+	assert.ErrorIs(err, ErrInvalidKey, "The private key of invalid length should be rejected!")
+}
@@ -0,0 +1,122 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// Stats is the snapshot of the dispatcher counters
+type Stats struct {
+
+	// Dispatches is the total number of dispatches
+	Dispatches uint64 `json:"dispatches"`
+
+	// DispatchesByName is the number of dispatches per event name
+	DispatchesByName map[string]uint64 `json:"dispatches_by_name"`
+
+	// Errors is the number of errors reported
+	Errors uint64 `json:"errors"`
+
+	// Listeners is the number of listeners registered per event name or
+	// pattern
+	Listeners map[string]int `json:"listeners"`
+
+	// QueueDepth is the number of asynchronous tasks pending
+	QueueDepth int `json:"queue_depth"`
+}
+
+// stats holds the counters of the dispatcher created WithStats
+type stats struct {
+	sync.Mutex
+	dispatches uint64
+	byName     map[string]uint64
+	errors     uint64
+}
+
+// WithStats makes the dispatcher count the dispatches and the errors
+// reported by Stats
+func WithStats() Option {
+	return func(d *EventDispatcher) {
+		d.stats = &stats{byName: make(map[string]uint64)}
+		WithDispatchObserver(func(r DispatchRecord) {
+			d.stats.Lock()
+			defer d.stats.Unlock()
+
+			d.stats.dispatches++
+			d.stats.byName[r.Name]++
+		})(d)
+	}
+}
+
+// Stats returns the snapshot of the dispatcher counters. The dispatches
+// and the errors are counted only by the dispatcher created WithStats, the
+// listeners and the queue depth are always reported.
+func (d *EventDispatcher) Stats() Stats {
+	st := Stats{
+		DispatchesByName: make(map[string]uint64),
+		Listeners:        make(map[string]int),
+		QueueDepth:       d.QueueDepth(),
+	}
+	for _, s := range d.shards {
+		s.RLock()
+		for n, listeners := range s.listeners {
+			if len(listeners) != 0 {
+				st.Listeners[n] = len(listeners)
+			}
+		}
+		s.RUnlock()
+	}
+	if d.stats == nil {
+		return st
+	}
+
+	d.stats.Lock()
+	defer d.stats.Unlock()
+	st.Dispatches, st.Errors = d.stats.dispatches, d.stats.errors
+	for n, c := range d.stats.byName {
+		st.DispatchesByName[n] = c
+	}
+
+	return st
+}
+
+// ListenerNames returns the names of the listeners registered per event
+// name or pattern, in the order they are called
+func (d *EventDispatcher) ListenerNames() map[string][]string {
+	names := make(map[string][]string)
+	for _, s := range d.shards {
+		s.RLock()
+		for n, listeners := range s.listeners {
+			for _, le := range listeners {
+				names[n] = append(names[n], ListenerName(le.l))
+			}
+		}
+		s.RUnlock()
+	}
+
+	return names
+}
+
+// ResetStats zeroes the counters of the dispatches and the errors
+func (d *EventDispatcher) ResetStats() {
+	if d.stats == nil {
+		return
+	}
+
+	d.stats.Lock()
+	defer d.stats.Unlock()
+	d.stats.dispatches, d.stats.errors = 0, 0
+	d.stats.byName = make(map[string]uint64)
+}
+
+// countError counts the reported error
+func countError(d *EventDispatcher) {
+	if d.stats == nil {
+		return
+	}
+
+	d.stats.Lock()
+	defer d.stats.Unlock()
+	d.stats.errors++
+}
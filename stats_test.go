@@ -0,0 +1,58 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithStats())
+	d.On("a b", func(e Event) {})
+	d.On("a", func(e Event) {
+		d.ReportError(e, errors.New("failed"))
+	})
+	d.Dispatch(NewParamsEvent("a"))
+	d.Dispatch(NewParamsEvent("a"))
+	d.Dispatch(NewParamsEvent("c"))
+
+	st := d.Stats()
+	assert.Equal(uint64(5), st.Dispatches, "All dispatches should be counted, including the error events!")
+	assert.Equal(map[string]uint64{"a": 2, "c": 1, ErrorEventName: 2}, st.DispatchesByName, "Invalid dispatches by name!")
+	assert.Equal(uint64(2), st.Errors, "The errors should be counted!")
+	assert.Equal(map[string]int{"a": 2, "b": 1}, st.Listeners, "Invalid listener counts!")
+	assert.Equal(0, st.QueueDepth, "Invalid queue depth!")
+
+	d.ResetStats()
+	st = d.Stats()
+	assert.Equal(uint64(0), st.Dispatches, "The dispatches should be reset!")
+	assert.Empty(st.DispatchesByName, "The dispatches by name should be reset!")
+	assert.Equal(uint64(0), st.Errors, "The errors should be reset!")
+	assert.Len(st.Listeners, 2, "The listeners should not be reset!")
+}
+
+func TestStatsDisabled(t *testing.T) {
+	d := NewDispatcher()
+	d.On("a", func(e Event) {})
+	d.Dispatch(NewParamsEvent("a"))
+	st := d.Stats()
+	assert.Equal(t, uint64(0), st.Dispatches, "The dispatches should not be counted without WithStats!")
+	assert.Equal(t, map[string]int{"a": 1}, st.Listeners, "The listeners should always be reported!")
+}
+
+func statsTestListener(e Event) {}
+
+func TestListenerNames(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.On("a", statsTestListener)
+	d.On("a", func(e Event) {})
+	names := d.ListenerNames()
+	assert.Len(names["a"], 2, "All listeners should be named!")
+	assert.Equal("github.com/gacek85/eventdispatcher.statsTestListener", names["a"][0], "Invalid listener name!")
+	assert.Contains(names["a"][1], "TestListenerNames.func", "Invalid closure name!")
+	assert.Equal("", ListenerName(nil), "The nil listener should have no name!")
+}
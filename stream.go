@@ -0,0 +1,45 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultStreamBufferSize is the number of events buffered by Stream before
+// the dispatch blocks waiting for the consumer
+const DefaultStreamBufferSize = 64
+
+// Stream returns the sequence of the events with given name (may contain
+// many space separated names, or patterns if the dispatcher matches
+// wildcards) dispatched while ranging over it. The listener is registered
+// when the loop starts and removed when it exits or the context is done.
+// When the consumer falls behind by more than DefaultStreamBufferSize
+// events, the dispatch blocks until it catches up.
+func (d *EventDispatcher) Stream(ctx context.Context, n string) iter.Seq[Event] {
+	return func(yield func(Event) bool) {
+		events := make(chan Event, DefaultStreamBufferSize)
+		done := make(chan struct{})
+		defer close(done)
+		s := d.Subscribe(n, func(e Event) {
+			select {
+			case events <- e:
+			case <-done:
+			case <-ctx.Done():
+			}
+		})
+		defer s.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-events:
+				if yield(e) == false {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	go func() {
+		// Wait until the loop starts and registers its listener
+		for d.HasListeners("user.created") == false {
+			runtime.Gosched()
+		}
+		for _, n := range []string{"user.created", "order.created", "user.deleted", "user.stop"} {
+			d.Dispatch(NewParamsEvent(n))
+		}
+	}()
+
+	var names []string
+	for e := range d.Stream(context.Background(), "user.*") {
+		if e.Name() == "user.stop" {
+			break
+		}
+		names = append(names, e.Name())
+	}
+	assert.Equal([]string{"user.created", "user.deleted"}, names, "Only the matching events should be streamed!")
+	assert.False(d.HasListeners("user.created"), "The listener should be removed when the loop exits!")
+}
+
+func TestStreamContextDone(t *testing.T) {
+	d := NewDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range d.Stream(ctx, TestEventName) {
+		t.Fatal("No events should be streamed after the context is done!")
+	}
+	assert.False(t, d.HasListeners(TestEventName), "The listener should be removed!")
+}
@@ -0,0 +1,78 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+// Subscriber is implemented by the types providing their own set of
+// listeners, e.g. the services reacting to many events of a domain
+type Subscriber interface {
+
+	// SubscribedEvents returns the listeners by event name (may contain
+	// many space separated names)
+	SubscribedEvents() map[string][]Listener
+}
+
+// AddSubscriber registers all listeners provided by the subscriber s.
+// Adding the same subscriber twice has no effect. The subscriber should be
+// comparable (usually a pointer), the uncomparable one is registered each
+// time it is added and can not be removed with RemoveSubscriber.
+func (d *EventDispatcher) AddSubscriber(s Subscriber) {
+	tracked := isComparable(s)
+	if tracked {
+		// The subscriber is reserved before its listeners are registered, so
+		// the concurrent adds do not register it twice
+		d.RWMutex.Lock()
+		_, ok := d.subscribers[s]
+		if ok == false {
+			d.subscribers[s] = nil
+		}
+		d.RWMutex.Unlock()
+		if ok {
+			return
+		}
+	}
+
+	var registrations []registration
+	for n, listeners := range s.SubscribedEvents() {
+		for _, name := range getNames(n) {
+			for _, l := range listeners {
+				registrations = append(registrations, registration{name, on(d, name, l)})
+			}
+		}
+	}
+	if tracked == false {
+		return
+	}
+
+	d.RWMutex.Lock()
+	_, ok := d.subscribers[s]
+	if ok {
+		d.subscribers[s] = registrations
+	}
+	d.RWMutex.Unlock()
+	// Removed while its listeners were being registered
+	if ok == false {
+		offRegistrations(d, registrations)
+	}
+}
+
+// RemoveSubscriber removes all listeners registered by AddSubscriber for
+// the subscriber s
+func (d *EventDispatcher) RemoveSubscriber(s Subscriber) {
+	if isComparable(s) == false {
+		return
+	}
+	d.RWMutex.Lock()
+	registrations := d.subscribers[s]
+	sealed := d.sealed.Load()
+	if sealed == false {
+		delete(d.subscribers, s)
+	}
+	d.RWMutex.Unlock()
+
+	if len(registrations) != 0 && sealed {
+		rejectSealed(d, registrations[0].n)
+		return
+	}
+
+	offRegistrations(d, registrations)
+}
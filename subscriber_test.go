@@ -0,0 +1,93 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type testSubscriber struct {
+	created int
+	deleted int
+}
+
+func (s *testSubscriber) SubscribedEvents() map[string][]Listener {
+	return map[string][]Listener{
+		"user.created user.restored": {func(e Event) {
+			s.created++
+		}},
+		"user.deleted": {func(e Event) {
+			s.deleted++
+		}, func(e Event) {
+			s.deleted++
+		}},
+	}
+}
+
+func TestAddRemoveSubscriber(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	s := &testSubscriber{}
+	d.AddSubscriber(s)
+	d.AddSubscriber(s)
+	d.Dispatch(NewParamsEvent("user.created"))
+	d.Dispatch(NewParamsEvent("user.restored"))
+	d.Dispatch(NewParamsEvent("user.deleted"))
+	assert.Equal(2, s.created, "Invalid subscribed listener calls number!")
+	assert.Equal(2, s.deleted, "All listeners subscribed for the event should be called once!")
+
+	d.RemoveSubscriber(s)
+	for _, n := range []string{"user.created", "user.restored", "user.deleted"} {
+		assert.False(d.HasListeners(n), "All subscribed listeners should be removed!")
+	}
+}
+
+// funcSubscriber is the subscriber of the type not comparable
+type funcSubscriber func() map[string][]Listener
+
+func (s funcSubscriber) SubscribedEvents() map[string][]Listener {
+	return s()
+}
+
+func TestAddSubscriberConcurrently(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int32
+	// The pointer to the function is comparable
+	s := funcSubscriberOf(&c)
+	p := &s
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.AddSubscriber(p)
+		}()
+	}
+	wg.Wait()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(int32(1), c, "The subscriber added concurrently should be registered once!")
+}
+
+func TestAddSubscriberUncomparable(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int32
+	assert.NotPanics(func() {
+		d.AddSubscriber(funcSubscriberOf(&c))
+		d.RemoveSubscriber(funcSubscriberOf(&c))
+	}, "The uncomparable subscriber should not panic!")
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(int32(1), c, "The uncomparable subscriber should be registered!")
+}
+
+func funcSubscriberOf(c *int32) funcSubscriber {
+	return func() map[string][]Listener {
+		return map[string][]Listener{TestEventName: {func(e Event) {
+			atomic.AddInt32(c, 1)
+		}}}
+	}
+}
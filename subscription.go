@@ -0,0 +1,55 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+)
+
+// Subscription is the handle of the listener registered with Subscribe,
+// removing exactly that registration. Unlike Off, it works for all kinds of
+// listeners, including the method values and the closures.
+type Subscription struct {
+	d             *EventDispatcher
+	once          sync.Once
+	registrations []registration
+
+	// mu guards stop, set once the subscription may already be removed
+	// by the timer or the context it stops
+	mu   sync.Mutex
+	stop func() bool
+}
+
+// Subscribe registers a listener for given event name (may contain many
+// space separated names) and returns the subscription removing it
+func (d *EventDispatcher) Subscribe(n string, l Listener) *Subscription {
+	s := &Subscription{d: d}
+	for _, name := range getNames(n) {
+		s.registrations = append(s.registrations, registration{name, on(d, name, l)})
+	}
+
+	return s
+}
+
+// Unsubscribe removes the listener of the subscription. Calling it again
+// does nothing.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		offRegistrations(s.d, s.registrations)
+		s.mu.Lock()
+		stop := s.stop
+		s.mu.Unlock()
+		if stop != nil {
+			stop()
+		}
+	})
+}
+
+// setStop sets the function stopping the automatic removal of the
+// subscription
+func (s *Subscription) setStop(stop func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stop = stop
+}
@@ -0,0 +1,47 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type counter struct {
+	c int
+}
+
+func (c *counter) Listen(e Event) {
+	c.c++
+}
+
+func TestSubscribeMethodValues(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	c1, c2 := &counter{}, &counter{}
+	s1 := d.Subscribe("a b", c1.Listen)
+	d.Subscribe("a", c2.Listen)
+	s1.Unsubscribe()
+	s1.Unsubscribe()
+	d.Dispatch(NewParamsEvent("a"))
+	d.Dispatch(NewParamsEvent("b"))
+	assert.Equal(0, c1.c, "The unsubscribed listener should not be called!")
+	assert.Equal(1, c2.c, "The listener of the same method with other receiver should be kept!")
+	assert.False(d.HasListeners("b"), "All names of the subscription should be removed!")
+}
+
+func TestSubscribeClosures(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var calls []int
+	var subs []*Subscription
+	for i := 0; i < 3; i++ {
+		i := i
+		subs = append(subs, d.Subscribe(TestEventName, func(e Event) {
+			calls = append(calls, i)
+		}))
+	}
+	subs[1].Unsubscribe()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]int{0, 2}, calls, "Only the unsubscribed closure should be removed!")
+}
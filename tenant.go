@@ -0,0 +1,69 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sort"
+)
+
+// TenantPrefix prefixes the namespaces of the tenants
+const TenantPrefix = "tenant" + NamespaceSeparator
+
+// TenantView is the namespace view isolating the listeners and the
+// dispatches of a single tenant, while sharing the dispatcher
+// infrastructure like workers, metrics and middlewares with other tenants.
+type TenantView struct {
+	*NamespaceView
+	id string
+}
+
+// ForTenant returns the view of the dispatcher for the tenant with given
+// id. The id must not contain the NamespaceSeparator, so the tenants never
+// overlap.
+func (d *EventDispatcher) ForTenant(id string) *TenantView {
+	d.RWMutex.Lock()
+	d.tenants[id] = true
+	d.RWMutex.Unlock()
+
+	return &TenantView{&NamespaceView{d, TenantPrefix + id + NamespaceSeparator}, id}
+}
+
+// Tenant returns the id of the tenant of the view
+func (v *TenantView) Tenant() string {
+	return v.id
+}
+
+// Tenants returns the sorted ids of the tenants the views were created for
+func (d *EventDispatcher) Tenants() []string {
+	d.RWMutex.RLock()
+	defer d.RWMutex.RUnlock()
+
+	ids := make([]string, 0, len(d.tenants))
+	for id := range d.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// DispatchTenants broadcasts the admin event to all tenants, one by one in
+// the order of their ids. Each tenant receives its own copy of the event
+// renamed to the namespace of the tenant, so the tenants do not see the
+// changes made by each other. Stopping the propagation of the copy ends the
+// broadcast. Returns the number of tenants the event was dispatched to.
+func (d *EventDispatcher) DispatchTenants(e Event) int {
+	var c int
+	if e.IsPropagationStopped() {
+		return c
+	}
+	for _, id := range d.Tenants() {
+		te := (&NamespaceView{d, TenantPrefix + id + NamespaceSeparator}).Dispatch(e)
+		c++
+		if te.IsPropagationStopped() {
+			break
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,65 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestForTenant(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	acme, globex := d.ForTenant("acme"), d.ForTenant("globex")
+	var got []string
+	acme.On(TestEventName, func(e Event) {
+		got = append(got, "acme")
+	})
+	globex.On(TestEventName, func(e Event) {
+		got = append(got, "globex")
+	})
+	acme.Dispatch(NewParamsEvent(TestEventName))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"acme"}, got, "The tenants should be isolated!")
+	assert.Equal("acme", acme.Tenant(), "Invalid tenant of the view!")
+	assert.Equal([]string{"acme", "globex"}, d.Tenants(), "Invalid tenants!")
+}
+
+func TestDispatchTenants(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	for _, id := range []string{"b", "a", "c"} {
+		v := d.ForTenant(id)
+		v.On(TestEventName, func(e Event) {
+			got = append(got, v.Tenant())
+			if v.Tenant() == "b" {
+				e.StopPropagation()
+			}
+		})
+	}
+	assert.Equal(2, d.DispatchTenants(NewParamsEvent(TestEventName)), "The broadcast should stop with the propagation!")
+	assert.Equal([]string{"a", "b"}, got, "The event should be broadcast in the order of tenant ids!")
+}
+
+func TestDispatchTenantsIsolation(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithDeduplication(time.Hour, 10))
+	var names []string
+	var leaked bool
+	for _, id := range []string{"a", "b"} {
+		d.ForTenant(id).On(TestEventName, func(e Event) {
+			pe := e.(*ParamsEvent)
+			names = append(names, pe.Name())
+			leaked = leaked || pe.HasParam("seen")
+			pe.SetParam("seen", true)
+		})
+	}
+	e := NewParamsEvent(TestEventName)
+	e.SetCorrelation("id", "id", "")
+	assert.Equal(2, d.DispatchTenants(e), "Each tenant should receive the event with an ID!")
+	assert.Equal([]string{"tenant.a.test_event", "tenant.b.test_event"}, names, "The tenants should see their namespaced names!")
+	assert.False(leaked, "The tenants should not share the event!")
+	assert.False(e.HasParam("seen"), "The broadcast event should not be changed!")
+}
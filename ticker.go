@@ -0,0 +1,99 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// TickEventPrefix prefixes the names of the events dispatched by the
+	// tickers, e.g. "tick.poll"
+	TickEventPrefix = "tick."
+
+	// ParamSequence is the param of the tick event holding its sequence
+	// number, increasing monotonically from 1 for the ticker
+	ParamSequence = "seq"
+
+	// ParamTime is the param of the tick event holding the time it has
+	// been scheduled at
+	ParamTime = "time"
+)
+
+// Ticker dispatches the TickEventPrefix prefixed events at the fixed
+// interval while running
+type Ticker struct {
+	sync.Mutex
+	d        *EventDispatcher
+	name     string
+	interval time.Duration
+	seq      uint64
+	next     time.Time
+	timer    Timer
+}
+
+// Ticker creates the stopped ticker dispatching the "tick.<name>" events
+// every interval once started
+func (d *EventDispatcher) Ticker(name string, interval time.Duration) *Ticker {
+	return &Ticker{d: d, name: TickEventPrefix + name, interval: interval}
+}
+
+// Name returns the name of the dispatched events
+func (t *Ticker) Name() string {
+	return t.name
+}
+
+// Start makes the ticker dispatch the events, the first one after the
+// interval. Does nothing if the ticker is running.
+func (t *Ticker) Start() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.timer != nil {
+		return
+	}
+	t.next = t.d.clock.Now().Add(t.interval)
+	t.timer = t.d.clock.AfterFunc(t.interval, t.tick)
+}
+
+// Stop stops dispatching the events. The sequence continues after the
+// ticker is started again.
+func (t *Ticker) Stop() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
+
+// IsRunning informs whether the ticker dispatches the events
+func (t *Ticker) IsRunning() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.timer != nil
+}
+
+// tick dispatches the event and schedules the next one. The ticks are
+// scheduled relative to the start, so they do not drift.
+func (t *Ticker) tick() {
+	t.Lock()
+	if t.timer == nil {
+		t.Unlock()
+		return
+	}
+	t.seq++
+	e := NewParamsEvent(t.name).SetParam(ParamSequence, t.seq).SetParam(ParamTime, t.next)
+	t.next = t.next.Add(t.interval)
+	delay := t.next.Sub(t.d.clock.Now())
+	if delay < 0 {
+		delay = 0
+	}
+	t.timer = t.d.clock.AfterFunc(delay, t.tick)
+	t.Unlock()
+
+	t.d.Dispatch(e)
+}
@@ -0,0 +1,39 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTicker(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	seqs := make(chan interface{}, 16)
+	d.On("tick.poll", func(e Event) {
+		seq, _ := e.(*ParamsEvent).GetParam(ParamSequence)
+		seqs <- seq
+	})
+	tk := d.Ticker("poll", 5*time.Millisecond)
+	assert.False(tk.IsRunning(), "The ticker should be stopped until started!")
+	tk.Start()
+	tk.Start()
+	assert.Equal(uint64(1), <-seqs)
+	assert.Equal(uint64(2), <-seqs)
+	tk.Stop()
+	assert.False(tk.IsRunning())
+	// The tick scheduled before stopping may still be dispatched
+	time.Sleep(15 * time.Millisecond)
+	last := uint64(2)
+	for len(seqs) != 0 {
+		last = (<-seqs).(uint64)
+	}
+	time.Sleep(15 * time.Millisecond)
+	assert.Equal(0, len(seqs), "The stopped ticker should not dispatch the events!")
+
+	tk.Start()
+	assert.Equal(last+1, <-seqs, "The sequence should continue after restart!")
+	tk.Stop()
+}
@@ -0,0 +1,249 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TopologyFormat is the format of the exported topology
+type TopologyFormat int
+
+const (
+	// TopologyDOT exports the topology as the Graphviz DOT graph
+	TopologyDOT TopologyFormat = iota
+
+	// TopologyMermaid exports the topology as the Mermaid flowchart
+	TopologyMermaid
+
+	// TopologyJSON exports the topology as the JSON encoded Topology
+	TopologyJSON
+)
+
+const (
+	// TopologyListener is the kind of the edge from the event name to its
+	// listener
+	TopologyListener = "listener"
+
+	// TopologyDerive is the kind of the edge from the event name to the
+	// name of the event derived with Derive
+	TopologyDerive = "derive"
+
+	// TopologyAlias is the kind of the edge from the event name to its
+	// alias
+	TopologyAlias = "alias"
+
+	// TopologyForward is the kind of the edge from the event name to the
+	// dispatcher the events are forwarded to with Forward
+	TopologyForward = "forward"
+
+	// TopologyAttach is the kind of the edge to the attached child
+	// dispatcher
+	TopologyAttach = "attach"
+
+	// TopologyBridge is the kind of the edge between the event name and
+	// the external system the bridge relays the events to or from
+	TopologyBridge = "bridge"
+
+	// TopologyAny is the node standing for all event names
+	TopologyAny = "*"
+)
+
+// ErrUnknownFormat is returned by ExportTopology for the unknown format
+var ErrUnknownFormat = errors.New("eventdispatcher: unknown topology format")
+
+// TopologyEdge is the relation between two nodes of the topology, e.g. the
+// event name and its listener
+type TopologyEdge struct {
+
+	// From is the source node
+	From string `json:"from"`
+
+	// To is the target node
+	To string `json:"to"`
+
+	// Kind is the kind of the relation, e.g. TopologyListener
+	Kind string `json:"kind"`
+}
+
+// Topology is the snapshot of "what reacts to what" in the dispatcher
+type Topology struct {
+
+	// Events are the event names and patterns listened to or linked, sorted
+	Events []string `json:"events"`
+
+	// Edges are the relations, sorted
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// DispatcherNode returns the name of the node standing for the dispatcher
+// d in the topology
+func DispatcherNode(d Dispatcher) string {
+	return fmt.Sprintf("dispatcher:%p", d)
+}
+
+// AddTopologyEdge adds the edge to the topology of the dispatcher, so the
+// relations the dispatcher does not know about, like the ones of the
+// bridges, are exported too. Returns the function removing the edge.
+func (d *EventDispatcher) AddTopologyEdge(e TopologyEdge) func() {
+	id := nextID(d)
+
+	d.RWMutex.Lock()
+	defer d.RWMutex.Unlock()
+	if d.edges == nil {
+		d.edges = make(map[uint64]TopologyEdge)
+	}
+	d.edges[id] = e
+
+	return func() {
+		d.RWMutex.Lock()
+		defer d.RWMutex.Unlock()
+		delete(d.edges, id)
+	}
+}
+
+// Topology returns the snapshot of the event names, the listeners, the
+// derivations, the aliases, the forwarding rules, the attached dispatchers
+// and the edges added with AddTopologyEdge. The listeners are named with
+// ListenerName.
+func (d *EventDispatcher) Topology() Topology {
+	var edges []TopologyEdge
+	for n, names := range d.ListenerNames() {
+		for _, name := range names {
+			edges = append(edges, TopologyEdge{n, name, TopologyListener})
+		}
+	}
+	if aliases := d.aliases.Load(); aliases != nil {
+		for n, names := range *aliases {
+			for _, alias := range names {
+				edges = append(edges, TopologyEdge{n, alias, TopologyAlias})
+			}
+		}
+	}
+
+	d.RWMutex.RLock()
+	for from, derivations := range d.derivations {
+		for _, dv := range derivations {
+			edges = append(edges, TopologyEdge{from, dv.to, TopologyDerive})
+		}
+	}
+	for _, c := range d.children {
+		edges = append(edges, TopologyEdge{TopologyAny, DispatcherNode(c), TopologyAttach})
+	}
+	for _, e := range d.edges {
+		edges = append(edges, e)
+	}
+	d.RWMutex.RUnlock()
+
+	return newTopology(edges)
+}
+
+// ExportTopology writes the topology of the dispatcher to w in given format
+func (d *EventDispatcher) ExportTopology(w io.Writer, f TopologyFormat) error {
+	t := d.Topology()
+	switch f {
+	case TopologyDOT:
+		return exportDOT(w, t)
+	case TopologyMermaid:
+		return exportMermaid(w, t)
+	case TopologyJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	}
+
+	return fmt.Errorf("%w: %d", ErrUnknownFormat, f)
+}
+
+// newTopology creates the topology of the unique edges, sorted, with the
+// event names being the sources of the edges other than the bridge ones
+func newTopology(edges []TopologyEdge) Topology {
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+
+	t := Topology{Events: []string{}, Edges: []TopologyEdge{}}
+	events := make(map[string]bool)
+	for i, e := range edges {
+		if i > 0 && edges[i-1] == e {
+			continue
+		}
+		t.Edges = append(t.Edges, e)
+		if e.Kind != TopologyBridge || e.To == TopologyAny {
+			events[e.From] = true
+		}
+		if e.Kind == TopologyDerive || e.Kind == TopologyAlias {
+			events[e.To] = true
+		}
+	}
+	for n := range events {
+		t.Events = append(t.Events, n)
+	}
+	sort.Strings(t.Events)
+
+	return t
+}
+
+// exportDOT writes the topology as the DOT graph, the event names being
+// the boxes
+func exportDOT(w io.Writer, t Topology) error {
+	var b strings.Builder
+	b.WriteString("digraph events {\n\trankdir=LR;\n")
+	for _, n := range t.Events {
+		fmt.Fprintf(&b, "\t%q [shape=box];\n", n)
+	}
+	for _, e := range t.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", e.From, e.To, e.Kind)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// exportMermaid writes the topology as the Mermaid flowchart, the event
+// names being the rectangles and the other nodes the rounded ones
+func exportMermaid(w io.Writer, t Topology) error {
+	ids := make(map[string]string)
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	node := func(n string) string {
+		if id, ok := ids[n]; ok {
+			return id
+		}
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[n] = id
+		label := strings.ReplaceAll(n, `"`, "#quot;")
+		if isTopologyEvent(t, n) {
+			return fmt.Sprintf("%s[\"%s\"]", id, label)
+		}
+		return fmt.Sprintf("%s(\"%s\")", id, label)
+	}
+	for _, n := range t.Events {
+		fmt.Fprintf(&b, "\t%s\n", node(n))
+	}
+	for _, e := range t.Edges {
+		fmt.Fprintf(&b, "\t%s -->|%s| %s\n", node(e.From), e.Kind, node(e.To))
+	}
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// isTopologyEvent informs whether the node n is the event name
+func isTopologyEvent(t Topology, n string) bool {
+	i := sort.SearchStrings(t.Events, n)
+	return i < len(t.Events) && t.Events[i] == n
+}
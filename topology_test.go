@@ -0,0 +1,65 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// sendReceipt is the named listener of the topology tests
+func sendReceipt(e Event) {}
+
+func TestTopology(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	child := NewDispatcher()
+	d.On("order.paid", sendReceipt)
+	d.Derive("order.paid", "email.send", nil)
+	d.Alias("order.paid", "payment.received", AliasForward)
+	d.Attach(child)
+	s := Forward(d, child, ForwardNames("order.*"))
+	remove := d.AddTopologyEdge(TopologyEdge{"order.paid", "mqtt:order/paid", TopologyBridge})
+
+	topology := d.Topology()
+	assert.Equal([]string{"*", "email.send", "order.*", "order.paid", "payment.received"}, topology.Events, "The event names should be listed!")
+	assert.Contains(topology.Edges, TopologyEdge{"order.paid", "github.com/gacek85/eventdispatcher.sendReceipt", TopologyListener}, "The listener should be linked!")
+	assert.Contains(topology.Edges, TopologyEdge{"order.paid", "email.send", TopologyDerive}, "The derivation should be linked!")
+	assert.Contains(topology.Edges, TopologyEdge{"order.paid", "payment.received", TopologyAlias}, "The alias should be linked!")
+	assert.Contains(topology.Edges, TopologyEdge{"*", DispatcherNode(child), TopologyAttach}, "The child should be linked!")
+	assert.Contains(topology.Edges, TopologyEdge{"order.*", DispatcherNode(child), TopologyForward}, "The forwarding rule should be linked!")
+	assert.Contains(topology.Edges, TopologyEdge{"order.paid", "mqtt:order/paid", TopologyBridge}, "The added edge should be linked!")
+
+	s.Unsubscribe()
+	remove()
+	topology = d.Topology()
+	assert.NotContains(topology.Edges, TopologyEdge{"order.*", DispatcherNode(child), TopologyForward}, "The removed rule should not be linked!")
+	assert.NotContains(topology.Edges, TopologyEdge{"order.paid", "mqtt:order/paid", TopologyBridge}, "The removed edge should not be linked!")
+}
+
+func TestExportTopology(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.Derive("order.paid", "email.send", nil)
+
+	var b bytes.Buffer
+	assert.Nil(d.ExportTopology(&b, TopologyDOT))
+	assert.Contains(b.String(), "digraph events {", "The DOT graph should be written!")
+	assert.Contains(b.String(), `"order.paid" -> "email.send" [label="derive"];`, "The edges should be written!")
+
+	b.Reset()
+	assert.Nil(d.ExportTopology(&b, TopologyMermaid))
+	assert.Contains(b.String(), "flowchart LR", "The Mermaid flowchart should be written!")
+	assert.Contains(b.String(), `n1["order.paid"]`, "The event names should be the rectangles!")
+	assert.Contains(b.String(), "n1 -->|derive| n0", "The edges should be written!")
+
+	b.Reset()
+	assert.Nil(d.ExportTopology(&b, TopologyJSON))
+	var topology Topology
+	assert.Nil(json.Unmarshal(b.Bytes(), &topology))
+	assert.Equal(d.Topology(), topology, "The topology should be encoded!")
+
+	assert.ErrorIs(d.ExportTopology(&b, TopologyFormat(42)), ErrUnknownFormat, "The unknown format should be rejected!")
+}
@@ -0,0 +1,24 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"time"
+)
+
+// OnFor registers a listener for given event name removed automatically
+// once the ttl elapses, regardless of how many times it has been called,
+// e.g. to watch the events for the next 30 seconds. Returns the
+// subscription to remove the listener earlier.
+func (d *EventDispatcher) OnFor(n string, l Listener, ttl time.Duration) *Subscription {
+	expires := d.clock.Now().Add(ttl)
+	s := d.Subscribe(n, func(e Event) {
+		// The timer may be late, so the listener checks the ttl itself
+		if d.clock.Now().Before(expires) {
+			l(e)
+		}
+	})
+	s.setStop(d.clock.AfterFunc(ttl, s.Unsubscribe).Stop)
+
+	return s
+}
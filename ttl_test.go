@@ -0,0 +1,42 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOnFor(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	d.OnFor(TestEventName, func(e Event) {
+		c++
+	}, 20*time.Millisecond)
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(2, c, "The listener should be called until the ttl elapses!")
+	time.Sleep(30 * time.Millisecond)
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(2, c, "The listener should not be called after the ttl!")
+	assert.Eventually(func() bool {
+		return d.HasListeners(TestEventName) == false
+	}, time.Second, time.Millisecond, "The listener should be removed after the ttl!")
+}
+
+func TestOnForUnsubscribe(t *testing.T) {
+	d := NewDispatcher()
+	s := d.OnFor(TestEventName, func(e Event) {}, time.Hour)
+	s.Unsubscribe()
+	assert.False(t, d.HasListeners(TestEventName), "The listener should be removed before the ttl!")
+}
+
+func TestOnForExpired(t *testing.T) {
+	d := NewDispatcher()
+	d.OnFor(TestEventName, func(e Event) {}, 0)
+	assert.Eventually(t, func() bool {
+		return d.HasListeners(TestEventName) == false
+	}, time.Second, time.Millisecond, "The listener without the ttl should be removed!")
+}
@@ -0,0 +1,96 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// unsubscribeHook is the function called once all registrations of the
+// subscription have been removed
+type unsubscribeHook struct {
+	once      sync.Once
+	remaining int32
+	f         func()
+}
+
+// unsubscribeHooks holds the hooks by the identifiers of the registrations
+type unsubscribeHooks struct {
+	sync.Mutex
+	hooks map[uint64]*unsubscribeHook
+}
+
+// OnUnsubscribe registers the function f called once the listener of the
+// subscription is removed, for all the names it has been registered for,
+// however it happens: with Unsubscribe, Off, OffAll, after the once
+// listener has been called or the ttl has expired. Meant to release the
+// resources held by the listener, like the connections or the files.
+// Returns this subscription instance
+func (s *Subscription) OnUnsubscribe(f func()) *Subscription {
+	h := &unsubscribeHook{remaining: int32(len(s.registrations)), f: f}
+	if len(s.registrations) == 0 {
+		// Nothing to track, e.g. the listener of all events removed only
+		// with Unsubscribe
+		stop := s.stop
+		s.stop = func() bool {
+			var ok bool
+			if stop != nil {
+				ok = stop()
+			}
+			h.once.Do(h.f)
+			return ok
+		}
+		return s
+	}
+
+	hs := s.d.hooks
+	hs.Lock()
+	defer hs.Unlock()
+	for _, r := range s.registrations {
+		hs.hooks[r.id] = h
+	}
+
+	return s
+}
+
+// SubscribeOnce registers a listener to be executed only once like Once
+// does and returns the subscription removing it
+func (d *EventDispatcher) SubscribeOnce(n string, l Listener) *Subscription {
+	s := &Subscription{d: d}
+	for _, name := range getNames(n) {
+		id := nextID(d)
+		nl := executeRemove(d, name, id, l)
+		addEntry(d, name, listenerEntry{id: id, l: nl, once: l})
+		s.registrations = append(s.registrations, registration{name, id})
+	}
+
+	return s
+}
+
+// unsubscribed calls the hooks of the subscriptions whose registrations
+// with given identifiers have all been removed
+func unsubscribed(d *EventDispatcher, ids []uint64) {
+	if len(ids) == 0 {
+		return
+	}
+
+	hs := d.hooks
+	var done []*unsubscribeHook
+	hs.Lock()
+	for _, id := range ids {
+		h, ok := hs.hooks[id]
+		if ok == false {
+			continue
+		}
+		delete(hs.hooks, id)
+		if atomic.AddInt32(&h.remaining, -1) == 0 {
+			done = append(done, h)
+		}
+	}
+	hs.Unlock()
+
+	for _, h := range done {
+		h.once.Do(h.f)
+	}
+}
@@ -0,0 +1,62 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOnUnsubscribe(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var c int
+	l := func(e Event) {}
+	d.Subscribe("a b", l).OnUnsubscribe(func() {
+		c++
+	})
+	d.OffAll("a")
+	assert.Equal(0, c, "The hook should wait until the listener is removed for all names!")
+	d.Off("b", l)
+	assert.Equal(1, c, "The hook should be called once the listener is removed!")
+
+	s := d.Subscribe("a", l).OnUnsubscribe(func() {
+		c++
+	})
+	s.Unsubscribe()
+	s.Unsubscribe()
+	assert.Equal(2, c, "The hook should be called once on Unsubscribe!")
+
+	d.OnAny(l).OnUnsubscribe(func() {
+		c++
+	}).Unsubscribe()
+	assert.Equal(3, c, "The hook of the listener of all events should be called!")
+}
+
+func TestOnUnsubscribeOnce(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	var got []string
+	d.SubscribeOnce(TestEventName, func(e Event) {
+		got = append(got, "listener")
+	}).OnUnsubscribe(func() {
+		got = append(got, "hook")
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal([]string{"listener", "hook"}, got, "The hook should be called after the once listener!")
+}
+
+func TestOnUnsubscribeTTL(t *testing.T) {
+	d := NewDispatcher()
+	called := make(chan bool)
+	d.OnFor(TestEventName, func(e Event) {}, time.Millisecond).OnUnsubscribe(func() {
+		close(called)
+	})
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		assert.Fail(t, "The hook should be called once the ttl expires!")
+	}
+}
@@ -0,0 +1,22 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+)
+
+// OnUntil registers a listener for given event name removed automatically
+// when the context is done, e.g. the per request or per connection
+// listener. Returns the subscription to remove the listener earlier.
+func (d *EventDispatcher) OnUntil(ctx context.Context, n string, l Listener) *Subscription {
+	s := d.Subscribe(n, func(e Event) {
+		// The listener may still be called by the dispatch in flight
+		if ctx.Err() == nil {
+			l(e)
+		}
+	})
+	s.setStop(context.AfterFunc(ctx, s.Unsubscribe))
+
+	return s
+}
@@ -0,0 +1,44 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOnUntil(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	var c int
+	d.OnUntil(ctx, TestEventName, func(e Event) {
+		c++
+	})
+	d.Dispatch(NewParamsEvent(TestEventName))
+	cancel()
+	d.Dispatch(NewParamsEvent(TestEventName))
+	assert.Equal(1, c, "The listener should not be called after the context is done!")
+	assert.Eventually(func() bool {
+		return d.HasListeners(TestEventName) == false
+	}, time.Second, time.Millisecond, "The listener should be removed when the context is done!")
+}
+
+func TestOnUntilUnsubscribe(t *testing.T) {
+	d := NewDispatcher()
+	s := d.OnUntil(context.Background(), TestEventName, func(e Event) {})
+	s.Unsubscribe()
+	assert.False(t, d.HasListeners(TestEventName), "The listener should be removed before the context is done!")
+}
+
+func TestOnUntilCanceled(t *testing.T) {
+	d := NewDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d.OnUntil(ctx, TestEventName, func(e Event) {})
+	assert.Eventually(t, func() bool {
+		return d.HasListeners(TestEventName) == false
+	}, time.Second, time.Millisecond, "The listener should be removed for the canceled context!")
+}
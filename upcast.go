@@ -0,0 +1,139 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// InitialVersion is the version of the newly created events
+const InitialVersion = 1
+
+// Upcaster transforms the params of the event from the version it is
+// registered for to the next one
+type Upcaster func(e *ParamsEvent) error
+
+// UpcasterRegistry holds the upcasters by event name and version, so the
+// persisted events of old payload shapes may be transformed to the current
+// shape on deserialization or replay
+type UpcasterRegistry struct {
+	sync.RWMutex
+	upcasters map[string]map[int]Upcaster
+}
+
+// Register registers the upcaster u transforming the events with given
+// name n (may contain many space separated names) from version v to v+1
+func (r *UpcasterRegistry) Register(n string, v int, u Upcaster) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, name := range getNames(n) {
+		if r.upcasters[name] == nil {
+			r.upcasters[name] = make(map[int]Upcaster)
+		}
+		r.upcasters[name][v] = u
+	}
+}
+
+// CurrentVersion returns the version the events with given name n are
+// upcasted to
+func (r *UpcasterRegistry) CurrentVersion(n string) int {
+	r.RLock()
+	defer r.RUnlock()
+
+	v := InitialVersion
+	for r.upcasters[n][v] != nil {
+		v++
+	}
+
+	return v
+}
+
+// Upcast applies the upcasters to the event one by one, until it reaches
+// the current version
+func (r *UpcasterRegistry) Upcast(e *ParamsEvent) error {
+	for {
+		r.RLock()
+		u := r.upcasters[e.Name()][e.Version()]
+		r.RUnlock()
+		if u == nil {
+			return nil
+		}
+		if err := u(e); err != nil {
+			return fmt.Errorf("eventdispatcher: upcasting %s from version %d: %w", e.Name(), e.Version(), err)
+		}
+		e.SetVersion(e.Version() + 1)
+	}
+}
+
+// Unmarshal decodes the JSON encoded event and upcasts it to the current
+// version
+func (r *UpcasterRegistry) Unmarshal(data []byte) (*ParamsEvent, error) {
+	e := NewParamsEvent("")
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	if err := r.Upcast(e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Filter returns the dispatcher filter upcasting the replayed ParamsEvent
+// instances. Events failing to upcast are dropped.
+func (r *UpcasterRegistry) Filter() Filter {
+	return func(e Event) (Event, bool) {
+		pe, ok := e.(*ParamsEvent)
+		if ok == false {
+			return e, true
+		}
+
+		return pe, r.Upcast(pe) == nil
+	}
+}
+
+// paramsEventJSON is the JSON representation of the ParamsEvent
+type paramsEventJSON struct {
+	Name          string                 `json:"name"`
+	Version       int                    `json:"version"`
+	ID            string                 `json:"id,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	CausationID   string                 `json:"causation_id,omitempty"`
+	Priority      Priority               `json:"priority,omitempty"`
+	Params        map[string]interface{} `json:"params,omitempty"`
+}
+
+// MarshalJSON encodes the event with its params and metadata
+func (event *ParamsEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(paramsEventJSON{
+		event.name, event.version, event.id, event.correlationID, event.causationID, event.priority, event.params,
+	})
+}
+
+// UnmarshalJSON decodes the event with its params and metadata. The
+// events encoded without the version get the InitialVersion.
+func (event *ParamsEvent) UnmarshalJSON(data []byte) error {
+	var ej paramsEventJSON
+	if err := json.Unmarshal(data, &ej); err != nil {
+		return err
+	}
+	if ej.Version == 0 {
+		ej.Version = InitialVersion
+	}
+	if ej.Params == nil {
+		ej.Params = make(map[string]interface{})
+	}
+	event.name, event.version, event.params = ej.Name, ej.Version, ej.Params
+	event.id, event.correlationID, event.causationID = ej.ID, ej.CorrelationID, ej.CausationID
+	event.priority = ej.Priority
+
+	return nil
+}
+
+// NewUpcasterRegistry creates an empty upcaster registry
+func NewUpcasterRegistry() *UpcasterRegistry {
+	return &UpcasterRegistry{upcasters: make(map[string]map[int]Upcaster)}
+}
@@ -0,0 +1,73 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func newTestUpcasterRegistry() *UpcasterRegistry {
+	r := NewUpcasterRegistry()
+	// v1 had a single "name" param, v2 splits it into first and last name
+	r.Register("user.created", 1, func(e *ParamsEvent) error {
+		n, _ := e.GetParam("name")
+		parts := strings.SplitN(n.(string), " ", 2)
+		e.RemoveParam("name").SetParam("first_name", parts[0]).SetParam("last_name", parts[1])
+		return nil
+	})
+	// v3 renames "last_name" to "surname"
+	r.Register("user.created", 2, func(e *ParamsEvent) error {
+		v, ok := e.GetParam("last_name")
+		if ok == false {
+			return errors.New("missing last_name")
+		}
+		e.RemoveParam("last_name").SetParam("surname", v)
+		return nil
+	})
+	return r
+}
+
+func TestUpcast(t *testing.T) {
+	assert := assert.New(t)
+	r := newTestUpcasterRegistry()
+	assert.Equal(3, r.CurrentVersion("user.created"))
+	assert.Equal(InitialVersion, r.CurrentVersion("user.deleted"))
+
+	e, err := r.Unmarshal([]byte(`{"name":"user.created","params":{"name":"John Smith"}}`))
+	assert.Nil(err)
+	assert.Equal(3, e.Version(), "The event should be upcasted to the current version!")
+	v, _ := e.GetParam("surname")
+	assert.Equal("Smith", v)
+	assert.False(e.HasParam("name"))
+
+	_, err = r.Unmarshal([]byte(`{"name":"user.created","version":2,"params":{}}`))
+	assert.NotNil(err, "The failed upcast should return an error!")
+}
+
+func TestParamsEventJSON(t *testing.T) {
+	assert := assert.New(t)
+	e := NewParamsEvent(TestEventName).SetParam("foo", "bar").SetVersion(2)
+	e.SetCorrelation("id", "correlation", "causation")
+	data, err := json.Marshal(e)
+	assert.Nil(err)
+
+	de := NewParamsEvent("")
+	assert.Nil(json.Unmarshal(data, de))
+	assert.Equal(e, de, "The decoded event should be equal to the encoded one!")
+}
+
+func TestUpcastFilter(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	d.AddFilter(newTestUpcasterRegistry().Filter())
+	var received *ParamsEvent
+	d.On("user.created", func(e Event) {
+		received = e.(*ParamsEvent)
+	})
+	d.Dispatch(NewParamsEvent("user.created").SetParam("name", "John Smith"))
+	assert.Equal(3, received.Version(), "The replayed event should be upcasted!")
+}
@@ -0,0 +1,167 @@
+// Package eventdispatcher is the context-first version 2 API of the event
+// dispatcher. Every method takes the context and returns the errors, the
+// listeners receive the context of the dispatch and may fail. It is
+// implemented by adapting the version 1 dispatcher, which keeps working
+// unchanged, so both APIs may be used with the same dispatcher during the
+// migration.
+package eventdispatcher
+
+import (
+	"context"
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"reflect"
+	"sync"
+)
+
+// Event is the event dispatched by the dispatcher, the same as in version 1
+type Event = ed.Event
+
+// Listener handles the event dispatched with given context. The returned
+// error is reported by the dispatcher and returned from Dispatch.
+type Listener func(ctx context.Context, e Event) error
+
+// Subscription is the handle of the registered listener
+type Subscription interface {
+
+	// Unsubscribe removes the listener
+	Unsubscribe()
+}
+
+// Dispatcher is the context-first event dispatcher
+type Dispatcher interface {
+
+	// Dispatch dispatches the event and returns it after all listeners do
+	// their jobs, along with the errors of the listeners. Returns the
+	// context error without dispatching if the context is done.
+	Dispatch(ctx context.Context, e Event) (Event, error)
+
+	// On registers the listener for given event name
+	On(ctx context.Context, n string, l Listener) (Subscription, error)
+
+	// OffAll removes all listeners for given event name
+	OffAll(ctx context.Context, n string) error
+
+	// HasListeners informs whether any listener for given event name has
+	// been registered
+	HasListeners(ctx context.Context, n string) (bool, error)
+}
+
+// V1 is the version 1 dispatcher adapted by the version 2 API
+type V1 interface {
+	ed.Dispatcher
+
+	// TryDispatch dispatches the event, returning the error if the event
+	// has not been dispatched
+	TryDispatch(e Event) (Event, error)
+
+	// Subscribe registers the listener and returns its subscription
+	Subscribe(n string, l ed.Listener) *ed.Subscription
+
+	// ReportError reports the error of the listener handling the event
+	ReportError(e Event, err error)
+}
+
+// dispatchState is the context and the collected errors of the dispatch in
+// flight
+type dispatchState struct {
+	ctx  context.Context
+	mu   sync.Mutex
+	errs []error
+}
+
+// adapter implements the Dispatcher with the version 1 dispatcher
+type adapter struct {
+	d        V1
+	inFlight sync.Map
+}
+
+// Dispatch dispatches the event with the version 1 dispatcher, passing the
+// context to the version 2 listeners
+func (a *adapter) Dispatch(ctx context.Context, e Event) (Event, error) {
+	if err := ctx.Err(); err != nil {
+		return e, err
+	}
+	if reflect.TypeOf(e).Comparable() == false {
+		return a.d.TryDispatch(e)
+	}
+
+	// The same event may be dispatched again by the listeners, so the
+	// outer dispatch state is restored afterwards
+	s := &dispatchState{ctx: ctx}
+	prev, nested := a.inFlight.Swap(e, s)
+	defer func() {
+		if nested {
+			a.inFlight.Store(e, prev)
+			return
+		}
+		a.inFlight.Delete(e)
+	}()
+
+	e, err := a.d.TryDispatch(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return e, errors.Join(append(s.errs, err)...)
+}
+
+// On registers the listener with the version 1 dispatcher
+func (a *adapter) On(ctx context.Context, n string, l Listener) (Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return a.d.Subscribe(n, func(e Event) {
+		lctx := context.Background()
+		var s *dispatchState
+		if v, ok := a.inFlight.Load(e); ok {
+			s = v.(*dispatchState)
+			lctx = s.ctx
+		}
+		err := lctx.Err()
+		if err == nil {
+			err = l(lctx, e)
+		}
+		if err == nil {
+			return
+		}
+		if s != nil {
+			s.mu.Lock()
+			s.errs = append(s.errs, err)
+			s.mu.Unlock()
+		}
+		a.d.ReportError(e, err)
+	}), nil
+}
+
+// OffAll removes all listeners for given event name
+func (a *adapter) OffAll(ctx context.Context, n string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	a.d.OffAll(n)
+
+	return nil
+}
+
+// HasListeners informs whether any listener for given event name has been
+// registered
+func (a *adapter) HasListeners(ctx context.Context, n string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return a.d.HasListeners(n), nil
+}
+
+// Adapt returns the version 2 API of the version 1 dispatcher d. Listeners
+// registered with either API receive the events dispatched with both.
+func Adapt(d V1) Dispatcher {
+	return &adapter{d: d}
+}
+
+// NewDispatcher creates the version 2 dispatcher configured with given
+// version 1 options
+func NewDispatcher(opts ...ed.Option) Dispatcher {
+	return Adapt(ed.NewDispatcher(opts...))
+}
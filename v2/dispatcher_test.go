@@ -0,0 +1,69 @@
+package eventdispatcher
+
+import (
+	"context"
+	"errors"
+	ed "github.com/gacek85/eventdispatcher"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func TestDispatch(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-1")
+	var got interface{}
+	_, err := d.On(ctx, "user.created", func(ctx context.Context, e Event) error {
+		got = ctx.Value(ctxKey{})
+		return nil
+	})
+	assert.Nil(err)
+	_, err = d.Dispatch(ctx, ed.NewParamsEvent("user.created"))
+	assert.Nil(err)
+	assert.Equal("request-1", got, "The listener should receive the dispatch context!")
+}
+
+func TestDispatchErrors(t *testing.T) {
+	assert := assert.New(t)
+	v1 := ed.NewDispatcher()
+	d := Adapt(v1)
+	failed := errors.New("failed")
+	ctx := context.Background()
+	d.On(ctx, "a", func(ctx context.Context, e Event) error {
+		return failed
+	})
+	var reported error
+	v1.On(ed.ErrorEventName, func(e ed.Event) {
+		reported = e.(*ed.ErrorEvent).Err
+	})
+	_, err := d.Dispatch(ctx, ed.NewParamsEvent("a"))
+	assert.True(errors.Is(err, failed), "The listener error should be returned!")
+	assert.Equal(failed, reported, "The listener error should be reported!")
+
+	v1.Dispatch(ed.NewParamsEvent("a"))
+	assert.Equal(failed, reported, "The v1 dispatch should reach the v2 listeners!")
+}
+
+func TestContextDone(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	var c int
+	s, _ := d.On(ctx, "a", func(ctx context.Context, e Event) error {
+		c++
+		return nil
+	})
+	cancel()
+	_, err := d.Dispatch(ctx, ed.NewParamsEvent("a"))
+	assert.Equal(context.Canceled, err, "The done context should be returned!")
+	_, err = d.On(ctx, "a", nil)
+	assert.Equal(context.Canceled, err)
+	ok, _ := d.HasListeners(context.Background(), "a")
+	assert.True(ok)
+	s.Unsubscribe()
+	ok, _ = d.HasListeners(context.Background(), "a")
+	assert.False(ok, "The subscription should remove the listener!")
+	assert.Equal(0, c)
+}
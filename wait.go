@@ -0,0 +1,46 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"strings"
+)
+
+// WaitAny blocks until the first event with any of given names is
+// dispatched and returns it, e.g. waiting for "app.ready" or "app.failed"
+// at startup. Returns the error of the context if it is done first.
+func (d *EventDispatcher) WaitAny(ctx context.Context, names ...string) (Event, error) {
+	return wait(d, ctx, strings.Join(names, " "), nil)
+}
+
+// WaitFor blocks until the event with given name matching the predicate is
+// dispatched and returns it. The nil predicate matches any event. Returns
+// the error of the context if it is done first.
+func (d *EventDispatcher) WaitFor(ctx context.Context, n string, predicate func(Event) bool) (Event, error) {
+	return wait(d, ctx, n, predicate)
+}
+
+// wait registers the temporary listener for given event name n and blocks
+// until it receives the event matching the predicate or the context is
+// done. The predicate is called from the dispatching goroutine.
+func wait(d *EventDispatcher, ctx context.Context, n string, predicate func(Event) bool) (Event, error) {
+	ch := make(chan Event, 1)
+	s := d.Subscribe(n, func(e Event) {
+		if predicate != nil && predicate(e) == false {
+			return
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+	defer s.Unsubscribe()
+
+	select {
+	case e := <-ch:
+		return e, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,67 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWaitAny(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	failed := NewParamsEvent("app.failed")
+	go func() {
+		for d.HasListeners("app.failed") == false {
+			time.Sleep(time.Millisecond)
+		}
+		d.Dispatch(failed)
+		d.Dispatch(NewParamsEvent("app.ready"))
+	}()
+	e, err := d.WaitAny(context.Background(), "app.ready", "app.failed")
+	assert.Nil(err, "Waiting should succeed!")
+	assert.Equal(failed, e, "The first event should be returned!")
+	assert.Eventually(func() bool {
+		return d.HasListeners("app.ready") == false
+	}, time.Second, time.Millisecond, "The listener should be removed after waiting!")
+}
+
+func TestWaitAnyCanceled(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	e, err := d.WaitAny(ctx, "app.ready")
+	assert.Nil(e, "No event should be returned!")
+	assert.ErrorIs(err, context.DeadlineExceeded, "The context error should be returned!")
+	assert.False(d.HasListeners("app.ready"), "The listener should be removed after waiting!")
+}
+
+func TestWaitFor(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	go func() {
+		for d.HasListeners("job.done") == false {
+			time.Sleep(time.Millisecond)
+		}
+		d.Dispatch(NewParamsEvent("job.done").SetParam("job", 1))
+		d.Dispatch(NewParamsEvent("job.done").SetParam("job", 2))
+	}()
+	e, err := d.WaitFor(context.Background(), "job.done", func(e Event) bool {
+		v, _ := e.(*ParamsEvent).GetParam("job")
+		return v == 2
+	})
+	assert.Nil(err, "Waiting should succeed!")
+	v, _ := e.(*ParamsEvent).GetParam("job")
+	assert.Equal(2, v, "The event matching the predicate should be returned!")
+}
+
+func TestWaitForCanceled(t *testing.T) {
+	d := NewDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := d.WaitFor(ctx, "job.done", nil)
+	assert.ErrorIs(t, err, context.Canceled, "The context error should be returned!")
+}
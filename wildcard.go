@@ -0,0 +1,113 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// WildcardSegment in the listened event name matches any single
+	// segment of the dispatched event name
+	WildcardSegment = "*"
+
+	// WildcardSegments in the listened event name matches any number of
+	// segments of the dispatched event name, including none
+	WildcardSegments = "**"
+)
+
+// WithWildcardMatching makes the listeners registered for names containing
+// wildcard segments, e.g. "user.*" or "order.**", receive all matching
+// events. Segments are separated with the NamespaceSeparator.
+func WithWildcardMatching() Option {
+	return func(d *EventDispatcher) {
+		d.wildcards = true
+	}
+}
+
+// listenersFor returns the listeners for given event name n, including the
+// ones registered for its aliases and, if the dispatcher matches wildcards,
+// for the matching patterns, by phase and in the order of registration or
+// the one set with SetListenerOrder, followed by the one set with RunAfter
+// for the name n
+func listenersFor(d *EventDispatcher, n string) listenersCollection {
+	n = normalize(d, n)
+	aliases := aliasesOf(d, n)
+	if d.wildcards == false && len(aliases) == 0 {
+		s := shardOf(d, n)
+		s.RLock()
+		defer s.RUnlock()
+
+		return s.listeners[n]
+	}
+
+	rlockShards(d)
+	defer runlockShards(d)
+
+	names := append([]string{n}, aliases...)
+	var sources []listenersCollection
+	for _, name := range names {
+		if listeners := shardOf(d, name).listeners[name]; len(listeners) != 0 {
+			sources = append(sources, listeners)
+		}
+	}
+	if d.wildcards {
+		for _, s := range d.shards {
+			for p, pl := range s.listeners {
+				if isPattern(p) && slices.Contains(names, p) == false && matchAny(p, names) {
+					sources = append(sources, pl)
+				}
+			}
+		}
+	}
+	switch len(sources) {
+	case 0:
+		return nil
+	case 1:
+		return orderDependencies(d, n, sources[0])
+	}
+
+	return orderDependencies(d, n, mergeListeners(sources))
+}
+
+// matchAny informs whether any of the event names matches the pattern p
+func matchAny(p string, names []string) bool {
+	for _, n := range names {
+		if matchPattern(p, n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isPattern informs whether the event name n contains wildcard segments
+func isPattern(n string) bool {
+	return strings.Contains(n, WildcardSegment)
+}
+
+// matchPattern informs whether the event name n matches the pattern p
+func matchPattern(p string, n string) bool {
+	return matchSegments(strings.Split(p, NamespaceSeparator), strings.Split(n, NamespaceSeparator))
+}
+
+// matchSegments matches the name segments ns against the pattern segments
+// ps
+func matchSegments(ps []string, ns []string) bool {
+	for i, p := range ps {
+		if p == WildcardSegments {
+			for j := i; j <= len(ns); j++ {
+				if matchSegments(ps[i+1:], ns[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(ns) || (p != WildcardSegment && p != ns[i]) {
+			return false
+		}
+	}
+
+	return len(ps) == len(ns)
+}
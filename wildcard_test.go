@@ -0,0 +1,61 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWildcardMatching(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	var calls []string
+	d.On("user.*", func(e Event) {
+		calls = append(calls, "user.*")
+	})
+	d.On("user.created", func(e Event) {
+		calls = append(calls, "user.created")
+	})
+	d.On("**", func(e Event) {
+		calls = append(calls, "**")
+	})
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal([]string{"user.*", "user.created", "**"}, calls, "Matching listeners should be called in the order of registration!")
+
+	calls = nil
+	d.Dispatch(NewParamsEvent("user.profile.updated"))
+	assert.Equal([]string{"**"}, calls, "The single segment wildcard should not match many segments!")
+}
+
+func TestWildcardMatchingDisabled(t *testing.T) {
+	d := NewDispatcher()
+	var c int
+	d.On("user.*", func(e Event) {
+		c++
+	})
+	d.Dispatch(NewParamsEvent("user.created"))
+	assert.Equal(t, 0, c, "Wildcards should not be matched by default!")
+}
+
+func TestMatchPattern(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(matchPattern("user.*", "user.created"))
+	assert.False(matchPattern("user.*", "user"))
+	assert.True(matchPattern("user.**", "user"))
+	assert.True(matchPattern("user.**.done", "user.a.b.done"))
+	assert.True(matchPattern("*.created", "order.created"))
+	assert.False(matchPattern("*.created", "order.deleted"))
+}
+
+func TestHasListenersPatterns(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher(WithWildcardMatching())
+	d.On("user.*", func(e Event) {})
+	d.On("user.created", func(e Event) {})
+	assert.True(d.HasListeners("user.deleted"), "The pattern listeners should be taken into account!")
+	assert.Equal(2, d.ListenerCount("user.created"))
+	assert.Equal(1, d.ListenerCount("user.deleted"))
+	assert.Equal(0, d.ListenerCount("order.created"))
+	assert.False(d.HasListeners("order.created"))
+}
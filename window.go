@@ -0,0 +1,228 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// ParamWindowStart is the param of the window summary event holding the
+	// start of the aggregated window
+	ParamWindowStart = "window_start"
+
+	// ParamWindowEnd is the param of the window summary event holding the
+	// end of the aggregated window
+	ParamWindowEnd = "window_end"
+
+	// ParamWindowCount is the param of the window summary event holding the
+	// number of the aggregated events
+	ParamWindowCount = "count"
+
+	// ParamWindowValue is the param of the window summary event holding the
+	// value computed by the reducer
+	ParamWindowValue = "value"
+)
+
+// Reducer folds the event e into the accumulated value acc, nil for the
+// first event of the window, and returns the new accumulated value
+type Reducer func(acc interface{}, e Event) interface{}
+
+// CountReducer counts the events of the window, as an int
+func CountReducer(acc interface{}, e Event) interface{} {
+	c, _ := acc.(int)
+	return c + 1
+}
+
+// SumReducer returns the reducer summing the numeric param p of the events,
+// as a float64. The events without the numeric param are skipped.
+func SumReducer(p string) Reducer {
+	return func(acc interface{}, e Event) interface{} {
+		sum, _ := acc.(float64)
+		pe, ok := e.(interface {
+			GetParam(k string) (interface{}, bool)
+		})
+		if ok == false {
+			return sum
+		}
+		v, _ := pe.GetParam(p)
+		f, _ := toFloat(v)
+
+		return sum + f
+	}
+}
+
+// toFloat converts the numeric value v to float64. Returns false if v is
+// not numeric.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// windowed is the event aggregated by the window along with the time it
+// has arrived at
+type windowed struct {
+	at time.Time
+	e  Event
+}
+
+// Window aggregates the events over the time windows and dispatches the
+// summary events holding the ParamWindowStart, ParamWindowEnd,
+// ParamWindowCount and ParamWindowValue params. The empty windows are not
+// summarized.
+type Window struct {
+	sync.Mutex
+	d       *EventDispatcher
+	out     string
+	size    time.Duration
+	every   time.Duration
+	tumble  bool
+	r       Reducer
+	events  []windowed
+	next    time.Time
+	timer   Timer
+	sub     *Subscription
+	stopped bool
+}
+
+// Tumbling aggregates the events with given name (may contain many space
+// separated names) over the consecutive, non-overlapping windows of given
+// size with the reducer r, dispatching the summary event with the name out
+// at the end of each window
+func (d *EventDispatcher) Tumbling(n string, out string, size time.Duration, r Reducer) *Window {
+	return newWindow(d, n, out, size, size, true, r)
+}
+
+// Sliding aggregates the events with given name (may contain many space
+// separated names) over the windows of given size with the reducer r,
+// dispatching the summary event with the name out every given period, so
+// the windows overlap if the period is shorter than the size
+func (d *EventDispatcher) Sliding(n string, out string, size time.Duration, every time.Duration, r Reducer) *Window {
+	return newWindow(d, n, out, size, every, false, r)
+}
+
+// newWindow creates the running window aggregating the events with given
+// name
+func newWindow(d *EventDispatcher, n string, out string, size time.Duration, every time.Duration, tumble bool, r Reducer) *Window {
+	w := &Window{d: d, out: out, size: size, every: every, tumble: tumble, r: r}
+	w.next = d.clock.Now().Add(every)
+	w.timer = d.clock.AfterFunc(every, w.tick)
+	w.sub = d.Subscribe(n, w.add)
+
+	return w
+}
+
+// Flush dispatches the summary of the window ending now, unless it is
+// empty. The tumbling window starts over.
+func (w *Window) Flush() {
+	w.Lock()
+	e := summarize(w, w.d.clock.Now())
+	w.Unlock()
+
+	if e != nil {
+		w.d.Dispatch(e)
+	}
+}
+
+// Stop removes the listener of the window and stops dispatching the
+// summaries. The events aggregated so far are dropped, call Flush before
+// to dispatch their summary.
+func (w *Window) Stop() {
+	w.sub.Unsubscribe()
+
+	w.Lock()
+	defer w.Unlock()
+	w.stopped = true
+	w.timer.Stop()
+	w.events = nil
+}
+
+// add is the listener adding the event to the window
+func (w *Window) add(e Event) {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.stopped == false {
+		w.events = append(w.events, windowed{w.d.clock.Now(), e})
+	}
+}
+
+// tick dispatches the summary of the window and schedules the next one.
+// The ticks are scheduled relative to the start, so they do not drift.
+func (w *Window) tick() {
+	w.Lock()
+	if w.stopped {
+		w.Unlock()
+		return
+	}
+	end := w.next
+	e := summarize(w, end)
+	w.next = w.next.Add(w.every)
+	delay := w.next.Sub(w.d.clock.Now())
+	if delay < 0 {
+		delay = 0
+	}
+	w.timer = w.d.clock.AfterFunc(delay, w.tick)
+	w.Unlock()
+
+	if e != nil {
+		w.d.Dispatch(e)
+	}
+}
+
+// summarize evicts the events that fell out of the window ending at end
+// and returns the summary event of the remaining ones, or nil if there are
+// none. The tumbling window is emptied. Must be called with the lock held.
+func summarize(w *Window, end time.Time) *ParamsEvent {
+	start := end.Add(-w.size)
+	i := 0
+	for i < len(w.events) && w.events[i].at.After(start) == false {
+		i++
+	}
+	events := w.events[i:]
+	w.events = events
+	if w.tumble {
+		w.events = nil
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	var acc interface{}
+	for _, we := range events {
+		acc = w.r(acc, we.e)
+	}
+
+	return NewParamsEvent(w.out).
+		SetParam(ParamWindowStart, start).
+		SetParam(ParamWindowEnd, end).
+		SetParam(ParamWindowCount, len(events)).
+		SetParam(ParamWindowValue, acc)
+}
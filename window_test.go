@@ -0,0 +1,95 @@
+// Package eventdispatcher contains a set of tools making up a simple and
+// reliable event dispatcher
+package eventdispatcher
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// windowSummaries records the summary events dispatched under name n
+func windowSummaries(d *EventDispatcher, n string) func() []*ParamsEvent {
+	var mu sync.Mutex
+	var got []*ParamsEvent
+	d.On(n, func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e.(*ParamsEvent))
+	})
+
+	return func() []*ParamsEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return got
+	}
+}
+
+func TestTumbling(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	summaries := windowSummaries(d, "orders.total")
+	w := d.Tumbling("order.paid", "orders.total", time.Hour, SumReducer("amount"))
+	defer w.Stop()
+
+	d.Dispatch(NewParamsEvent("order.paid").SetParam("amount", 10))
+	d.Dispatch(NewParamsEvent("order.paid").SetParam("amount", 2.5))
+	d.Dispatch(NewParamsEvent("order.paid"))
+	w.Flush()
+	w.Flush()
+	d.Dispatch(NewParamsEvent("order.paid").SetParam("amount", 1))
+	w.Flush()
+
+	got := summaries()
+	assert.Equal(2, len(got), "The empty window should not be summarized!")
+	count, _ := got[0].GetParam(ParamWindowCount)
+	value, _ := got[0].GetParam(ParamWindowValue)
+	assert.Equal(3, count, "All events of the window should be counted!")
+	assert.Equal(12.5, value, "The param should be summed!")
+	value, _ = got[1].GetParam(ParamWindowValue)
+	assert.Equal(1.0, value, "The tumbling window should start over!")
+}
+
+func TestTumblingTick(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	summaries := make(chan Event, 1)
+	d.On("requests.count", func(e Event) {
+		summaries <- e
+	})
+	w := d.Tumbling("request", "requests.count", 10*time.Millisecond, CountReducer)
+	defer w.Stop()
+
+	d.Dispatch(NewParamsEvent("request"))
+	select {
+	case e := <-summaries:
+		count, _ := e.(*ParamsEvent).GetParam(ParamWindowValue)
+		assert.Equal(1, count, "The events should be counted!")
+	case <-time.After(time.Second):
+		assert.Fail("The summary should be dispatched at the end of the window!")
+	}
+}
+
+func TestSliding(t *testing.T) {
+	assert := assert.New(t)
+	d := NewDispatcher()
+	summaries := windowSummaries(d, "requests.count")
+	w := d.Sliding("request", "requests.count", 30*time.Millisecond, time.Hour, CountReducer)
+
+	d.Dispatch(NewParamsEvent("request"))
+	time.Sleep(40 * time.Millisecond)
+	d.Dispatch(NewParamsEvent("request"))
+	w.Flush()
+	w.Flush()
+	w.Stop()
+	d.Dispatch(NewParamsEvent("request"))
+	w.Flush()
+
+	got := summaries()
+	assert.Equal(2, len(got), "The stopped window should not be summarized!")
+	for _, e := range got {
+		count, _ := e.GetParam(ParamWindowCount)
+		assert.Equal(1, count, "Only the events within the window should be aggregated!")
+	}
+}